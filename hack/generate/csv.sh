@@ -193,6 +193,7 @@ function set_operator_upstream_image {
 
 function set_operator_ingress_image {
   yq write --inplace "$1" "spec.install.spec.deployments(name==knative-openshift-ingress).spec.template.spec.containers(name==knative-openshift-ingress).image" "${SERVERLESS_INGRESS}"
+  yq write --inplace "$1" "spec.install.spec.deployments(name==knative-openshift-ingress).spec.template.spec.containers(name==kube-rbac-proxy).image" "${rbac_proxy}"
 }
 
 # since we also parse the environment variables in the upstream (actually midstream) operator,
@@ -0,0 +1,220 @@
+// kafka-render prints the resources the KnativeKafka controller would apply for a given
+// KnativeKafka, without applying anything.
+//
+// The Kafka manifests are loaded from the same environment variables as the operator's
+// (KAFKACHANNEL_MANIFEST_PATH, KAFKASOURCE_MANIFEST_PATH, ...), or from the directory
+// given with --resources, laid out like knative-operator/deploy/resources/knativekafka.
+//
+// By default, the cluster state the transformers depend on (KnativeEventing,
+// config-features, ...) is read from the cluster of the current kubeconfig. With
+// --offline, it is read from the input file instead, next to the KnativeKafka, and an
+// empty KnativeEventing and config-features are assumed if the file doesn't define them,
+// and the Kubernetes version is taken from --kube-version.
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	operatorv1beta1 "knative.dev/operator/pkg/apis/operator/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	zapr "sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift-knative/serverless-operator/knative-operator/pkg/apis"
+	serverlessoperatorv1alpha1 "github.com/openshift-knative/serverless-operator/knative-operator/pkg/apis/operator/v1alpha1"
+	"github.com/openshift-knative/serverless-operator/knative-operator/pkg/controller/knativekafka"
+)
+
+// manifestPathEnvs maps the environment variables of the Kafka manifests to their
+// directory in the resources layout.
+var manifestPathEnvs = map[string]string{
+	"KAFKACHANNEL_MANIFEST_PATH":    "channel",
+	"KAFKASOURCE_MANIFEST_PATH":     "source",
+	"KAFKACONTROLLER_MANIFEST_PATH": "controller",
+	"KAFKABROKER_MANIFEST_PATH":     "broker",
+	"KAFKASINK_MANIFEST_PATH":       "sink",
+}
+
+func main() {
+	var (
+		filename    string
+		resources   string
+		offline     bool
+		kubeVersion string
+		verbose     bool
+	)
+	pflag.StringVarP(&filename, "filename", "f", "-", "File containing the KnativeKafka to render, - for stdin")
+	pflag.StringVar(&resources, "resources", "", "Directory of the Kafka manifests, overrides the *_MANIFEST_PATH environment variables")
+	pflag.BoolVar(&offline, "offline", false, "Read the cluster state from the input file instead of the cluster")
+	pflag.StringVar(&kubeVersion, "kube-version", "v1.31.0", "Kubernetes version assumed with --offline")
+	pflag.BoolVarP(&verbose, "verbose", "v", false, "Log the controller output to stderr")
+	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
+	pflag.Parse()
+
+	if verbose {
+		logf.SetLogger(zapr.New(zapr.WriteTo(os.Stderr)))
+	}
+	if offline {
+		// The deprecated APIs transformers discover the Kubernetes version otherwise.
+		os.Setenv("TEST_DEPRECATED_APIS_K8S_VERSION", kubeVersion)
+	}
+
+	if err := run(context.Background(), os.Stdout, filename, resources, offline); err != nil {
+		fmt.Fprintln(os.Stderr, "kafka-render:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, out io.Writer, filename, resources string, offline bool) error {
+	if resources != "" {
+		for env, dir := range manifestPathEnvs {
+			if err := os.Setenv(env, filepath.Join(resources, dir)); err != nil {
+				return err
+			}
+		}
+	}
+
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		return err
+	}
+
+	objects, err := readObjects(filename)
+	if err != nil {
+		return err
+	}
+	instance, others, err := splitKnativeKafka(scheme, objects)
+	if err != nil {
+		return err
+	}
+
+	var c client.Client
+	if offline {
+		c = offlineClient(scheme, instance, others)
+	} else {
+		cfg, err := config.GetConfig()
+		if err != nil {
+			return err
+		}
+		if c, err = client.New(cfg, client.Options{Scheme: scheme}); err != nil {
+			return err
+		}
+	}
+
+	rendered, err := knativekafka.Render(ctx, c, instance)
+	if err != nil {
+		return err
+	}
+	return writeObjects(out, rendered)
+}
+
+// readObjects reads the YAML or JSON documents of the given file.
+func readObjects(filename string) ([]unstructured.Unstructured, error) {
+	var r io.Reader = os.Stdin
+	if filename != "-" {
+		f, err := os.Open(filename)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var objects []unstructured.Unstructured
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bufio.NewReader(r), 4096)
+	for {
+		u := unstructured.Unstructured{}
+		if err := decoder.Decode(&u.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				return objects, nil
+			}
+			return nil, fmt.Errorf("failed to decode %s: %w", filename, err)
+		}
+		if len(u.Object) > 0 {
+			objects = append(objects, u)
+		}
+	}
+}
+
+// splitKnativeKafka returns the only KnativeKafka of the given objects, along with the others.
+func splitKnativeKafka(scheme *runtime.Scheme, objects []unstructured.Unstructured) (*serverlessoperatorv1alpha1.KnativeKafka, []unstructured.Unstructured, error) {
+	var instance *serverlessoperatorv1alpha1.KnativeKafka
+	var others []unstructured.Unstructured
+	for i := range objects {
+		if objects[i].GetKind() != "KnativeKafka" {
+			others = append(others, objects[i])
+			continue
+		}
+		if instance != nil {
+			return nil, nil, errors.New("only one KnativeKafka can be rendered at a time")
+		}
+		instance = &serverlessoperatorv1alpha1.KnativeKafka{}
+		if err := scheme.Convert(&objects[i], instance, nil); err != nil {
+			return nil, nil, fmt.Errorf("failed to decode KnativeKafka: %w", err)
+		}
+	}
+	if instance == nil {
+		return nil, nil, errors.New("no KnativeKafka found in the input")
+	}
+	if instance.Namespace == "" {
+		instance.Namespace = "knative-eventing"
+	}
+	return instance, others, nil
+}
+
+// offlineClient returns a client serving the given objects, defaulting the objects the
+// controller requires.
+func offlineClient(scheme *runtime.Scheme, instance *serverlessoperatorv1alpha1.KnativeKafka, others []unstructured.Unstructured) client.Client {
+	hasEventing, hasFeatures := false, false
+	builder := fake.NewClientBuilder().WithScheme(scheme).WithObjects(instance)
+	for i := range others {
+		switch {
+		case others[i].GetKind() == "KnativeEventing":
+			hasEventing = true
+		case others[i].GetKind() == "ConfigMap" && others[i].GetName() == "config-features":
+			hasFeatures = true
+		}
+		builder = builder.WithObjects(&others[i])
+	}
+	if !hasEventing {
+		builder = builder.WithObjects(&operatorv1beta1.KnativeEventing{
+			ObjectMeta: metav1.ObjectMeta{Namespace: instance.Namespace, Name: "knative-eventing"},
+		})
+	}
+	if !hasFeatures {
+		builder = builder.WithObjects(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: instance.Namespace, Name: "config-features"},
+		})
+	}
+	return builder.Build()
+}
+
+// writeObjects writes the given objects as a multi-document YAML stream.
+func writeObjects(out io.Writer, objects []unstructured.Unstructured) error {
+	for i := range objects {
+		b, err := yaml.Marshal(objects[i].Object)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(out, "---\n%s", b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"knative.dev/pkg/apis"
+
+	"github.com/openshift-knative/serverless-operator/knative-operator/pkg/apis/operator/v1beta1"
+)
+
+// ConvertTo implements apis.Convertible
+// Converts KnativeKafka from v1alpha1.KnativeKafka into v1beta1.KnativeKafka, the hub.
+func (kk *KnativeKafka) ConvertTo(_ context.Context, to apis.Convertible) error {
+	sink, ok := to.(*v1beta1.KnativeKafka)
+	if !ok {
+		return fmt.Errorf("unknown version, got: %T", to)
+	}
+	in := kk.DeepCopy()
+	sink.ObjectMeta = in.ObjectMeta
+	sink.Spec = v1beta1.KnativeKafkaSpec{
+		Broker: v1beta1.BrokerSpec{
+			Enabled: in.Spec.Broker.Enabled,
+			Kafka: v1beta1.KafkaConnection{
+				Cluster:          in.Spec.Broker.DefaultConfig.Cluster,
+				BootstrapServers: in.Spec.Broker.DefaultConfig.BootstrapServers,
+				AuthSecretName:   in.Spec.Broker.DefaultConfig.AuthSecretName,
+			},
+			Topic: v1beta1.TopicSpec{
+				NumPartitions:     in.Spec.Broker.DefaultConfig.NumPartitions,
+				ReplicationFactor: in.Spec.Broker.DefaultConfig.ReplicationFactor,
+			},
+			DataPlane: dataPlaneTo(in.Spec.Broker.DataPlane),
+			Scaling:   scalingTo(in.Spec.Broker.Scaling),
+		},
+		Source: v1beta1.SourceSpec{
+			Enabled:   in.Spec.Source.Enabled,
+			DataPlane: dataPlaneTo(in.Spec.Source.DataPlane),
+		},
+		Sink: v1beta1.SinkSpec{
+			Enabled:   in.Spec.Sink.Enabled,
+			Cluster:   in.Spec.Sink.Cluster,
+			DataPlane: dataPlaneTo(in.Spec.Sink.DataPlane),
+			Scaling:   scalingTo(in.Spec.Sink.Scaling),
+		},
+		Channel: v1beta1.ChannelSpec{
+			Enabled: in.Spec.Channel.Enabled,
+			Kafka: v1beta1.ChannelKafkaConnection{
+				KafkaConnection: v1beta1.KafkaConnection{
+					Cluster:          in.Spec.Channel.Cluster,
+					BootstrapServers: in.Spec.Channel.BootstrapServers,
+					AuthSecretName:   in.Spec.Channel.AuthSecretName,
+				},
+				AuthSecretNamespace: in.Spec.Channel.AuthSecretNamespace,
+			},
+			DataPlane: dataPlaneTo(in.Spec.Channel.DataPlane),
+			Scaling:   scalingTo(in.Spec.Channel.Scaling),
+		},
+		Config:            in.Spec.Config,
+		DispatcherScaling: (*v1beta1.DispatcherScaling)(in.Spec.DispatcherScaling),
+		HighAvailability:  in.Spec.HighAvailability,
+		Workloads:         in.Spec.Workloads,
+	}
+	if topic := in.Spec.Broker.DefaultConfig.Topic; topic != nil {
+		sink.Spec.Broker.Topic.RetentionMillis = topic.RetentionMillis
+		sink.Spec.Broker.Topic.MinInSyncReplicas = topic.MinInSyncReplicas
+	}
+	if in.Spec.Logging != nil {
+		sink.Spec.Logging = &v1beta1.Logging{Level: v1beta1.LogLevel(in.Spec.Logging.Level)}
+	}
+	for _, c := range in.Spec.Clusters {
+		sink.Spec.Clusters = append(sink.Spec.Clusters, v1beta1.KafkaCluster{
+			Name:             c.Name,
+			BootstrapServers: c.BootstrapServers,
+			AuthSecretName:   c.AuthSecretName,
+		})
+	}
+	sink.Status = v1beta1.KnativeKafkaStatus{
+		Status:         in.Status.Status,
+		Version:        in.Status.Version,
+		Rollout:        (*v1beta1.KafkaRolloutStatus)(in.Status.Rollout),
+		ManifestHashes: in.Status.ManifestHashes,
+	}
+	return nil
+}
+
+// ConvertFrom implements apis.Convertible
+// Converts KnativeKafka from v1beta1.KnativeKafka, the hub, into v1alpha1.KnativeKafka.
+func (kk *KnativeKafka) ConvertFrom(_ context.Context, from apis.Convertible) error {
+	source, ok := from.(*v1beta1.KnativeKafka)
+	if !ok {
+		return fmt.Errorf("unknown version, got: %T", from)
+	}
+	in := source.DeepCopy()
+	kk.ObjectMeta = in.ObjectMeta
+	kk.Spec = KnativeKafkaSpec{
+		Broker: Broker{
+			Enabled: in.Spec.Broker.Enabled,
+			DefaultConfig: BrokerDefaultConfig{
+				BootstrapServers:  in.Spec.Broker.Kafka.BootstrapServers,
+				NumPartitions:     in.Spec.Broker.Topic.NumPartitions,
+				ReplicationFactor: in.Spec.Broker.Topic.ReplicationFactor,
+				AuthSecretName:    in.Spec.Broker.Kafka.AuthSecretName,
+				Cluster:           in.Spec.Broker.Kafka.Cluster,
+			},
+			DataPlane: dataPlaneFrom(in.Spec.Broker.DataPlane),
+			Scaling:   scalingFrom(in.Spec.Broker.Scaling),
+		},
+		Source: Source{
+			Enabled:   in.Spec.Source.Enabled,
+			DataPlane: dataPlaneFrom(in.Spec.Source.DataPlane),
+		},
+		Sink: Sink{
+			Enabled:   in.Spec.Sink.Enabled,
+			Cluster:   in.Spec.Sink.Cluster,
+			DataPlane: dataPlaneFrom(in.Spec.Sink.DataPlane),
+			Scaling:   scalingFrom(in.Spec.Sink.Scaling),
+		},
+		Channel: Channel{
+			Enabled:             in.Spec.Channel.Enabled,
+			BootstrapServers:    in.Spec.Channel.Kafka.BootstrapServers,
+			AuthSecretNamespace: in.Spec.Channel.Kafka.AuthSecretNamespace,
+			AuthSecretName:      in.Spec.Channel.Kafka.AuthSecretName,
+			Cluster:             in.Spec.Channel.Kafka.Cluster,
+			DataPlane:           dataPlaneFrom(in.Spec.Channel.DataPlane),
+			Scaling:             scalingFrom(in.Spec.Channel.Scaling),
+		},
+		Config:            in.Spec.Config,
+		DispatcherScaling: (*DispatcherScaling)(in.Spec.DispatcherScaling),
+		HighAvailability:  in.Spec.HighAvailability,
+		Workloads:         in.Spec.Workloads,
+	}
+	if topic := in.Spec.Broker.Topic; topic.RetentionMillis != nil || topic.MinInSyncReplicas != nil {
+		kk.Spec.Broker.DefaultConfig.Topic = &TopicConfig{
+			RetentionMillis:   topic.RetentionMillis,
+			MinInSyncReplicas: topic.MinInSyncReplicas,
+		}
+	}
+	if in.Spec.Logging != nil {
+		kk.Spec.Logging = &Logging{Level: string(in.Spec.Logging.Level)}
+	}
+	for _, c := range in.Spec.Clusters {
+		kk.Spec.Clusters = append(kk.Spec.Clusters, KafkaCluster{
+			Name:             c.Name,
+			BootstrapServers: c.BootstrapServers,
+			AuthSecretName:   c.AuthSecretName,
+		})
+	}
+	kk.Status = KnativeKafkaStatus{
+		Status:         in.Status.Status,
+		Version:        in.Status.Version,
+		Rollout:        (*KafkaRolloutStatus)(in.Status.Rollout),
+		ManifestHashes: in.Status.ManifestHashes,
+	}
+	return nil
+}
+
+func dataPlaneTo(in *DataPlaneConfig) *v1beta1.DataPlaneConfig {
+	if in == nil {
+		return nil
+	}
+	return &v1beta1.DataPlaneConfig{
+		Producer:   in.Producer,
+		Consumer:   in.Consumer,
+		Dispatcher: dispatcherTo(in.Dispatcher),
+	}
+}
+
+func dispatcherTo(in *DispatcherConfig) *v1beta1.DispatcherConfig {
+	if in == nil {
+		return nil
+	}
+	return &v1beta1.DispatcherConfig{
+		MaxPollRecords: in.MaxPollRecords,
+		MaxPoolSize:    in.MaxPoolSize,
+		DeliveryOrder:  v1beta1.DeliveryOrder(in.DeliveryOrder),
+	}
+}
+
+func dataPlaneFrom(in *v1beta1.DataPlaneConfig) *DataPlaneConfig {
+	if in == nil {
+		return nil
+	}
+	return &DataPlaneConfig{
+		Producer:   in.Producer,
+		Consumer:   in.Consumer,
+		Dispatcher: dispatcherFrom(in.Dispatcher),
+	}
+}
+
+func dispatcherFrom(in *v1beta1.DispatcherConfig) *DispatcherConfig {
+	if in == nil {
+		return nil
+	}
+	return &DispatcherConfig{
+		MaxPollRecords: in.MaxPollRecords,
+		MaxPoolSize:    in.MaxPoolSize,
+		DeliveryOrder:  DeliveryOrder(in.DeliveryOrder),
+	}
+}
+
+func scalingTo(in *Scaling) *v1beta1.Scaling {
+	if in == nil {
+		return nil
+	}
+	return &v1beta1.Scaling{
+		Receiver: (*v1beta1.WorkloadScaling)(in.Receiver),
+	}
+}
+
+func scalingFrom(in *v1beta1.Scaling) *Scaling {
+	if in == nil {
+		return nil
+	}
+	return &Scaling{
+		Receiver: (*WorkloadScaling)(in.Receiver),
+	}
+}
@@ -0,0 +1,171 @@
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/operator/pkg/apis/operator/base"
+	operatorv1beta1 "knative.dev/operator/pkg/apis/operator/v1beta1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/ptr"
+
+	"github.com/openshift-knative/serverless-operator/knative-operator/pkg/apis/operator/v1beta1"
+)
+
+func TestKnativeKafkaConversionBadType(t *testing.T) {
+	good, bad := &KnativeKafka{}, &operatorv1beta1.KnativeServing{}
+
+	if err := good.ConvertTo(context.Background(), bad); err == nil {
+		t.Errorf("ConvertTo() = %#v, wanted error", bad)
+	}
+	if err := good.ConvertFrom(context.Background(), bad); err == nil {
+		t.Errorf("ConvertFrom() = %#v, wanted error", good)
+	}
+}
+
+func TestKnativeKafkaConversion(t *testing.T) {
+	alpha := &KnativeKafka{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "knative-eventing", Name: "knative-kafka"},
+		Spec: KnativeKafkaSpec{
+			Broker: Broker{
+				Enabled: true,
+				DefaultConfig: BrokerDefaultConfig{
+					BootstrapServers:  "my-cluster-kafka-bootstrap.kafka:9092",
+					NumPartitions:     12,
+					ReplicationFactor: 3,
+					AuthSecretName:    "broker-auth",
+					Topic:             &TopicConfig{RetentionMillis: ptr.Int64(-1), MinInSyncReplicas: ptr.Int32(2)},
+				},
+				DataPlane: &DataPlaneConfig{
+					Producer:   map[string]string{"linger.ms": "5"},
+					Dispatcher: &DispatcherConfig{MaxPollRecords: ptr.Int32(100), DeliveryOrder: DeliveryOrderOrdered},
+				},
+				Scaling: &Scaling{
+					Receiver: &WorkloadScaling{MaxReplicas: 5, CPUUtilization: ptr.Int32(70)},
+				},
+			},
+			Source: Source{Enabled: true, DataPlane: &DataPlaneConfig{Consumer: map[string]string{"fetch.min.bytes": "1"}}},
+			Sink:   Sink{Enabled: true, Cluster: "secure"},
+			Channel: Channel{
+				Enabled:             true,
+				BootstrapServers:    "my-cluster-kafka-bootstrap.kafka:9092",
+				AuthSecretNamespace: "kafka",
+				AuthSecretName:      "channel-auth",
+			},
+			Config:            base.ConfigMapData{"config-kafka-features": {"dispatcher.rate-limiter": "enabled"}},
+			DispatcherScaling: &DispatcherScaling{MaxScale: ptr.Int32(10), LagThreshold: ptr.Int64(20)},
+			HighAvailability:  &base.HighAvailability{Replicas: ptr.Int32(2)},
+			Logging:           &Logging{Level: "DEBUG"},
+			Workloads:         []base.WorkloadOverride{{Name: "kafka-controller", Replicas: ptr.Int32(2)}},
+			Clusters: []KafkaCluster{{
+				Name:             "secure",
+				BootstrapServers: "secure-kafka-bootstrap.kafka:9093",
+				AuthSecretName:   "secure-auth",
+			}},
+		},
+		Status: KnativeKafkaStatus{
+			Status: duckv1.Status{
+				ObservedGeneration: 2,
+				Conditions:         duckv1.Conditions{{Type: apis.ConditionReady, Status: "True"}},
+			},
+			Version:        "1.33.0",
+			Rollout:        &KafkaRolloutStatus{Completed: []string{"ControlPlane", "Webhook"}},
+			ManifestHashes: map[string]string{"broker": "abc"},
+		},
+	}
+
+	beta := &v1beta1.KnativeKafka{}
+	if err := alpha.ConvertTo(context.Background(), beta); err != nil {
+		t.Fatalf("ConvertTo() = %v", err)
+	}
+
+	// The per-component configuration is nested.
+	wantBroker := v1beta1.BrokerSpec{
+		Enabled: true,
+		Kafka: v1beta1.KafkaConnection{
+			BootstrapServers: "my-cluster-kafka-bootstrap.kafka:9092",
+			AuthSecretName:   "broker-auth",
+		},
+		Topic: v1beta1.TopicSpec{
+			NumPartitions:     12,
+			ReplicationFactor: 3,
+			RetentionMillis:   ptr.Int64(-1),
+			MinInSyncReplicas: ptr.Int32(2),
+		},
+		DataPlane: &v1beta1.DataPlaneConfig{
+			Producer:   map[string]string{"linger.ms": "5"},
+			Dispatcher: &v1beta1.DispatcherConfig{MaxPollRecords: ptr.Int32(100), DeliveryOrder: v1beta1.DeliveryOrderOrdered},
+		},
+		Scaling: &v1beta1.Scaling{
+			Receiver: &v1beta1.WorkloadScaling{MaxReplicas: 5, CPUUtilization: ptr.Int32(70)},
+		},
+	}
+	if !cmp.Equal(beta.Spec.Broker, wantBroker) {
+		t.Errorf("Broker = %s", cmp.Diff(wantBroker, beta.Spec.Broker))
+	}
+	wantChannel := v1beta1.ChannelSpec{
+		Enabled: true,
+		Kafka: v1beta1.ChannelKafkaConnection{
+			KafkaConnection: v1beta1.KafkaConnection{
+				BootstrapServers: "my-cluster-kafka-bootstrap.kafka:9092",
+				AuthSecretName:   "channel-auth",
+			},
+			AuthSecretNamespace: "kafka",
+		},
+	}
+	if !cmp.Equal(beta.Spec.Channel, wantChannel) {
+		t.Errorf("Channel = %s", cmp.Diff(wantChannel, beta.Spec.Channel))
+	}
+	if got, want := beta.Spec.Logging.Level, v1beta1.LogLevelDebug; got != want {
+		t.Errorf("Logging.Level = %q, want %q", got, want)
+	}
+
+	// v1alpha1 -> v1beta1 -> v1alpha1 is lossless.
+	gotAlpha := &KnativeKafka{}
+	if err := gotAlpha.ConvertFrom(context.Background(), beta); err != nil {
+		t.Fatalf("ConvertFrom() = %v", err)
+	}
+	if !cmp.Equal(gotAlpha, alpha) {
+		t.Errorf("v1alpha1 round trip = %s", cmp.Diff(alpha, gotAlpha))
+	}
+
+	// v1beta1 -> v1alpha1 -> v1beta1 is lossless.
+	gotBeta := &v1beta1.KnativeKafka{}
+	if err := gotAlpha.ConvertTo(context.Background(), gotBeta); err != nil {
+		t.Fatalf("ConvertTo() = %v", err)
+	}
+	if !cmp.Equal(gotBeta, beta) {
+		t.Errorf("v1beta1 round trip = %s", cmp.Diff(beta, gotBeta))
+	}
+}
+
+func TestKnativeKafkaConversionEmpty(t *testing.T) {
+	beta := &v1beta1.KnativeKafka{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "knative-eventing", Name: "knative-kafka"},
+		Spec: v1beta1.KnativeKafkaSpec{
+			Channel: v1beta1.ChannelSpec{
+				Enabled: true,
+				Kafka:   v1beta1.ChannelKafkaConnection{KafkaConnection: v1beta1.KafkaConnection{Cluster: "secure"}},
+			},
+		},
+	}
+
+	alpha := &KnativeKafka{}
+	if err := alpha.ConvertFrom(context.Background(), beta); err != nil {
+		t.Fatalf("ConvertFrom() = %v", err)
+	}
+	if alpha.Spec.Broker.DefaultConfig.Topic != nil || alpha.Spec.Logging != nil {
+		t.Errorf("Spec = %#v, want the unset optional fields to stay unset", alpha.Spec)
+	}
+
+	got := &v1beta1.KnativeKafka{}
+	if err := alpha.ConvertTo(context.Background(), got); err != nil {
+		t.Fatalf("ConvertTo() = %v", err)
+	}
+	if !cmp.Equal(got, beta) {
+		t.Errorf("v1beta1 round trip = %s", cmp.Diff(beta, got))
+	}
+}
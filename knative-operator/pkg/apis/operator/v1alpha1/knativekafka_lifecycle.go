@@ -1,6 +1,8 @@
 package v1alpha1
 
 import (
+	"strings"
+
 	"knative.dev/operator/pkg/apis/operator/base"
 	"knative.dev/pkg/apis"
 )
@@ -9,6 +11,30 @@ const (
 	// StatefulSetsAvailable is a Condition indicating whether or not the StatefulSets of
 	// the respective component have come up successfully.
 	StatefulSetsAvailable apis.ConditionType = "StatefulSetsAvailable"
+
+	// ControlPlaneReady is a Condition indicating whether or not the workloads of the
+	// Kafka control plane (controller and webhook) are available.
+	ControlPlaneReady apis.ConditionType = "ControlPlaneReady"
+
+	// BrokerReady is a Condition indicating whether or not the workloads of the
+	// KafkaBroker data plane are available.
+	BrokerReady apis.ConditionType = "BrokerReady"
+
+	// SinkReady is a Condition indicating whether or not the workloads of the
+	// KafkaSink data plane are available.
+	SinkReady apis.ConditionType = "SinkReady"
+
+	// SourceReady is a Condition indicating whether or not the workloads of the
+	// KafkaSource data plane are available.
+	SourceReady apis.ConditionType = "SourceReady"
+
+	// ChannelReady is a Condition indicating whether or not the workloads of the
+	// KafkaChannel data plane are available.
+	ChannelReady apis.ConditionType = "ChannelReady"
+
+	// KafkaClusterReachable is a Condition indicating whether or not the Kafka clusters
+	// configured for the broker and the channel can be reached.
+	KafkaClusterReachable apis.ConditionType = "KafkaClusterReachable"
 )
 
 var (
@@ -16,6 +42,7 @@ var (
 		base.DeploymentsAvailable,
 		StatefulSetsAvailable,
 		base.InstallSucceeded,
+		KafkaClusterReachable,
 	)
 )
 
@@ -43,6 +70,15 @@ func (is *KnativeKafkaStatus) MarkInstallFailed(msg string) {
 		"Install failed with message: %s", msg)
 }
 
+// MarkInstallRollingOut marks the InstallationSucceeded status as unknown while the
+// workloads of the given rollout phase become available.
+func (is *KnativeKafkaStatus) MarkInstallRollingOut(phase string, workloads []string) {
+	kafkaCondSet.Manage(is).MarkUnknown(
+		base.InstallSucceeded,
+		"RollingOut",
+		"Waiting on the %s rollout phase: %s", phase, strings.Join(workloads, ", "))
+}
+
 // MarkDeploymentsAvailable marks the DeploymentsAvailable status as true.
 func (is *KnativeKafkaStatus) MarkDeploymentsAvailable() {
 	kafkaCondSet.Manage(is).MarkTrue(base.DeploymentsAvailable)
@@ -70,3 +106,50 @@ func (is *KnativeKafkaStatus) MarkStatefulSetNotReady() {
 		"NotReady",
 		"Waiting on StatefulSets")
 }
+
+// MarkComponentReady marks the given component condition as true.
+// Component conditions are informational, the overall readiness is still driven by
+// DeploymentsAvailable and StatefulSetsAvailable.
+func (is *KnativeKafkaStatus) MarkComponentReady(component apis.ConditionType) {
+	kafkaCondSet.Manage(is).MarkTrue(component)
+}
+
+// MarkComponentNotReady marks the given component condition as false and lists the
+// workloads that are not available yet.
+func (is *KnativeKafkaStatus) MarkComponentNotReady(component apis.ConditionType, workloads []string) {
+	kafkaCondSet.Manage(is).MarkFalse(
+		component,
+		"NotReady",
+		"Waiting on workloads: %s", strings.Join(workloads, ", "))
+}
+
+// MarkComponentDisabled removes the given component condition, as the component is not
+// installed.
+func (is *KnativeKafkaStatus) MarkComponentDisabled(component apis.ConditionType) {
+	// ClearCondition only fails for terminal conditions and component conditions are not terminal.
+	_ = kafkaCondSet.Manage(is).ClearCondition(component)
+}
+
+// MarkKafkaClusterReachable marks the KafkaClusterReachable status as true with the
+// given probe details.
+func (is *KnativeKafkaStatus) MarkKafkaClusterReachable(msg string) {
+	kafkaCondSet.Manage(is).MarkTrueWithReason(KafkaClusterReachable, "Reachable", msg)
+}
+
+// MarkKafkaClusterUnreachable marks the KafkaClusterReachable status as false with the
+// given message.
+func (is *KnativeKafkaStatus) MarkKafkaClusterUnreachable(msg string) {
+	kafkaCondSet.Manage(is).MarkFalse(
+		KafkaClusterReachable,
+		"Unreachable",
+		"Failed to reach Kafka cluster: %s", msg)
+}
+
+// MarkKafkaClusterNotConfigured marks the KafkaClusterReachable status as true, as neither
+// an enabled component nor a cluster profile configures a Kafka cluster.
+func (is *KnativeKafkaStatus) MarkKafkaClusterNotConfigured() {
+	kafkaCondSet.Manage(is).MarkTrueWithReason(
+		KafkaClusterReachable,
+		"NotConfigured",
+		"No Kafka cluster configured for the broker, the channel or the cluster profiles")
+}
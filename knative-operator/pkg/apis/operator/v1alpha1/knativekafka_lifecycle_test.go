@@ -46,6 +46,13 @@ func TestKnativeKafkaHappyPath(t *testing.T) {
 	apistest.CheckConditionSucceeded(ks, base.InstallSucceeded, t)
 	apistest.CheckConditionSucceeded(ks, base.DeploymentsAvailable, t)
 	apistest.CheckConditionSucceeded(ks, base.InstallSucceeded, t)
+	if ready := ks.IsReady(); ready {
+		t.Errorf("ks.IsReady() = %v, want false", ready)
+	}
+
+	// The Kafka cluster is reachable.
+	ks.MarkKafkaClusterReachable("3 brokers")
+	apistest.CheckConditionSucceeded(ks, KafkaClusterReachable, t)
 
 	if ready := ks.IsReady(); !ready {
 		t.Errorf("ks.IsReady() = %v, want true", ready)
@@ -85,7 +92,46 @@ func TestKnativeKafkaErrorPath(t *testing.T) {
 	apistest.CheckConditionSucceeded(ks, base.DeploymentsAvailable, t)
 	apistest.CheckConditionSucceeded(ks, StatefulSetsAvailable, t)
 	apistest.CheckConditionSucceeded(ks, base.InstallSucceeded, t)
+
+	// The Kafka cluster cannot be reached.
+	ks.MarkKafkaClusterUnreachable("connection refused")
+	apistest.CheckConditionFailed(ks, KafkaClusterReachable, t)
+	if ready := ks.IsReady(); ready {
+		t.Errorf("ks.IsReady() = %v, want false", ready)
+	}
+
+	// No cluster-wide Kafka cluster is configured anymore.
+	ks.MarkKafkaClusterNotConfigured()
+	apistest.CheckConditionSucceeded(ks, KafkaClusterReachable, t)
 	if ready := ks.IsReady(); !ready {
 		t.Errorf("ks.IsReady() = %v, want true", ready)
 	}
 }
+
+func TestKnativeKafkaComponentConditions(t *testing.T) {
+	ks := &KnativeKafkaStatus{}
+	ks.InitializeConditions()
+	ks.MarkInstallSucceeded()
+	ks.MarkDeploymentsAvailable()
+	ks.MarkStatefulSetsAvailable()
+	ks.MarkKafkaClusterNotConfigured()
+
+	ks.MarkComponentReady(ControlPlaneReady)
+	ks.MarkComponentNotReady(BrokerReady, []string{"kafka-broker-dispatcher", "kafka-broker-receiver"})
+	apistest.CheckConditionSucceeded(ks, ControlPlaneReady, t)
+	apistest.CheckConditionFailed(ks, BrokerReady, t)
+
+	if got, want := ks.GetCondition(BrokerReady).Message, "Waiting on workloads: kafka-broker-dispatcher, kafka-broker-receiver"; got != want {
+		t.Errorf("BrokerReady message = %q, want %q", got, want)
+	}
+
+	// Component conditions are informational and don't drive readiness.
+	if ready := ks.IsReady(); !ready {
+		t.Errorf("ks.IsReady() = %v, want true", ready)
+	}
+
+	ks.MarkComponentDisabled(BrokerReady)
+	if c := ks.GetCondition(BrokerReady); c != nil {
+		t.Errorf("BrokerReady = %v, want nil", c)
+	}
+}
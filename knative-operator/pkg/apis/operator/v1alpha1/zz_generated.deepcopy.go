@@ -1,17 +1,76 @@
 //go:build !ignore_autogenerated
 // +build !ignore_autogenerated
 
-// Code generated by operator-sdk-v0.10.1-x86_64-linux-gnu. DO NOT EDIT.
+// Code generated by deepcopy-gen. DO NOT EDIT.
 
 package v1alpha1
 
 import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	base "knative.dev/operator/pkg/apis/operator/base"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Broker) DeepCopyInto(out *Broker) {
+	*out = *in
+	in.DefaultConfig.DeepCopyInto(&out.DefaultConfig)
+	if in.DataPlane != nil {
+		in, out := &in.DataPlane, &out.DataPlane
+		*out = new(DataPlaneConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scaling != nil {
+		in, out := &in.Scaling, &out.Scaling
+		*out = new(Scaling)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Broker.
+func (in *Broker) DeepCopy() *Broker {
+	if in == nil {
+		return nil
+	}
+	out := new(Broker)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerDefaultConfig) DeepCopyInto(out *BrokerDefaultConfig) {
+	*out = *in
+	if in.Topic != nil {
+		in, out := &in.Topic, &out.Topic
+		*out = new(TopicConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerDefaultConfig.
+func (in *BrokerDefaultConfig) DeepCopy() *BrokerDefaultConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerDefaultConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Channel) DeepCopyInto(out *Channel) {
 	*out = *in
+	if in.DataPlane != nil {
+		in, out := &in.DataPlane, &out.DataPlane
+		*out = new(DataPlaneConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scaling != nil {
+		in, out := &in.Scaling, &out.Scaling
+		*out = new(Scaling)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -25,12 +84,146 @@ func (in *Channel) DeepCopy() *Channel {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataPlaneConfig) DeepCopyInto(out *DataPlaneConfig) {
+	*out = *in
+	if in.Producer != nil {
+		in, out := &in.Producer, &out.Producer
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Consumer != nil {
+		in, out := &in.Consumer, &out.Consumer
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Dispatcher != nil {
+		in, out := &in.Dispatcher, &out.Dispatcher
+		*out = new(DispatcherConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataPlaneConfig.
+func (in *DataPlaneConfig) DeepCopy() *DataPlaneConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DataPlaneConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DispatcherConfig) DeepCopyInto(out *DispatcherConfig) {
+	*out = *in
+	if in.MaxPollRecords != nil {
+		in, out := &in.MaxPollRecords, &out.MaxPollRecords
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxPoolSize != nil {
+		in, out := &in.MaxPoolSize, &out.MaxPoolSize
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DispatcherConfig.
+func (in *DispatcherConfig) DeepCopy() *DispatcherConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DispatcherConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DispatcherScaling) DeepCopyInto(out *DispatcherScaling) {
+	*out = *in
+	if in.MinScale != nil {
+		in, out := &in.MinScale, &out.MinScale
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxScale != nil {
+		in, out := &in.MaxScale, &out.MaxScale
+		*out = new(int32)
+		**out = **in
+	}
+	if in.LagThreshold != nil {
+		in, out := &in.LagThreshold, &out.LagThreshold
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DispatcherScaling.
+func (in *DispatcherScaling) DeepCopy() *DispatcherScaling {
+	if in == nil {
+		return nil
+	}
+	out := new(DispatcherScaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaCluster) DeepCopyInto(out *KafkaCluster) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaCluster.
+func (in *KafkaCluster) DeepCopy() *KafkaCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaRolloutStatus) DeepCopyInto(out *KafkaRolloutStatus) {
+	*out = *in
+	if in.Completed != nil {
+		in, out := &in.Completed, &out.Completed
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Waiting != nil {
+		in, out := &in.Waiting, &out.Waiting
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaRolloutStatus.
+func (in *KafkaRolloutStatus) DeepCopy() *KafkaRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KnativeKafka) DeepCopyInto(out *KnativeKafka) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 	return
 }
@@ -57,7 +250,7 @@ func (in *KnativeKafka) DeepCopyObject() runtime.Object {
 func (in *KnativeKafkaList) DeepCopyInto(out *KnativeKafkaList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	out.ListMeta = in.ListMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
 		*out = make([]KnativeKafka, len(*in))
@@ -89,8 +282,54 @@ func (in *KnativeKafkaList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KnativeKafkaSpec) DeepCopyInto(out *KnativeKafkaSpec) {
 	*out = *in
-	out.Source = in.Source
-	out.Channel = in.Channel
+	in.Broker.DeepCopyInto(&out.Broker)
+	in.Source.DeepCopyInto(&out.Source)
+	in.Sink.DeepCopyInto(&out.Sink)
+	in.Channel.DeepCopyInto(&out.Channel)
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(base.ConfigMapData, len(*in))
+		for key, val := range *in {
+			var outVal map[string]string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(map[string]string, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.DispatcherScaling != nil {
+		in, out := &in.DispatcherScaling, &out.DispatcherScaling
+		*out = new(DispatcherScaling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HighAvailability != nil {
+		in, out := &in.HighAvailability, &out.HighAvailability
+		*out = new(base.HighAvailability)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(Logging)
+		**out = **in
+	}
+	if in.Workloads != nil {
+		in, out := &in.Workloads, &out.Workloads
+		*out = make([]base.WorkloadOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]KafkaCluster, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -108,6 +347,18 @@ func (in *KnativeKafkaSpec) DeepCopy() *KnativeKafkaSpec {
 func (in *KnativeKafkaStatus) DeepCopyInto(out *KnativeKafkaStatus) {
 	*out = *in
 	in.Status.DeepCopyInto(&out.Status)
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(KafkaRolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ManifestHashes != nil {
+		in, out := &in.ManifestHashes, &out.ManifestHashes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
@@ -121,9 +372,77 @@ func (in *KnativeKafkaStatus) DeepCopy() *KnativeKafkaStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Logging) DeepCopyInto(out *Logging) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Logging.
+func (in *Logging) DeepCopy() *Logging {
+	if in == nil {
+		return nil
+	}
+	out := new(Logging)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Scaling) DeepCopyInto(out *Scaling) {
+	*out = *in
+	if in.Receiver != nil {
+		in, out := &in.Receiver, &out.Receiver
+		*out = new(WorkloadScaling)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Scaling.
+func (in *Scaling) DeepCopy() *Scaling {
+	if in == nil {
+		return nil
+	}
+	out := new(Scaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Sink) DeepCopyInto(out *Sink) {
+	*out = *in
+	if in.DataPlane != nil {
+		in, out := &in.DataPlane, &out.DataPlane
+		*out = new(DataPlaneConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scaling != nil {
+		in, out := &in.Scaling, &out.Scaling
+		*out = new(Scaling)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Sink.
+func (in *Sink) DeepCopy() *Sink {
+	if in == nil {
+		return nil
+	}
+	out := new(Sink)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Source) DeepCopyInto(out *Source) {
 	*out = *in
+	if in.DataPlane != nil {
+		in, out := &in.DataPlane, &out.DataPlane
+		*out = new(DataPlaneConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -136,3 +455,60 @@ func (in *Source) DeepCopy() *Source {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopicConfig) DeepCopyInto(out *TopicConfig) {
+	*out = *in
+	if in.RetentionMillis != nil {
+		in, out := &in.RetentionMillis, &out.RetentionMillis
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MinInSyncReplicas != nil {
+		in, out := &in.MinInSyncReplicas, &out.MinInSyncReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopicConfig.
+func (in *TopicConfig) DeepCopy() *TopicConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TopicConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadScaling) DeepCopyInto(out *WorkloadScaling) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CPUUtilization != nil {
+		in, out := &in.CPUUtilization, &out.CPUUtilization
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MemoryUtilization != nil {
+		in, out := &in.MemoryUtilization, &out.MemoryUtilization
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadScaling.
+func (in *WorkloadScaling) DeepCopy() *WorkloadScaling {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadScaling)
+	in.DeepCopyInto(out)
+	return out
+}
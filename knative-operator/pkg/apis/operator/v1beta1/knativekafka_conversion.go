@@ -0,0 +1,20 @@
+package v1beta1
+
+import (
+	"context"
+	"fmt"
+
+	"knative.dev/pkg/apis"
+)
+
+// ConvertTo implements apis.Convertible
+// Converts KnativeKafka from v1beta1.KnativeKafka into a higher version.
+func (kk *KnativeKafka) ConvertTo(_ context.Context, sink apis.Convertible) error {
+	return fmt.Errorf("v1beta1 is the highest known version, got: %T", sink)
+}
+
+// ConvertFrom implements apis.Convertible
+// Converts KnativeKafka from a higher version into v1beta1.KnativeKafka
+func (kk *KnativeKafka) ConvertFrom(_ context.Context, source apis.Convertible) error {
+	return fmt.Errorf("v1beta1 is the highest known version, got: %T", source)
+}
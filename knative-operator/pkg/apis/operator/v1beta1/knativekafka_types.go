@@ -0,0 +1,357 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/operator/pkg/apis/operator/base"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+// KnativeKafkaSpec defines the desired state of KnativeKafka
+// +k8s:openapi-gen=true
+type KnativeKafkaSpec struct {
+	// Broker allows configuration of the KafkaBroker installation.
+	// +optional
+	Broker BrokerSpec `json:"broker,omitempty"`
+
+	// Source allows configuration of the KafkaSource installation.
+	// +optional
+	Source SourceSpec `json:"source,omitempty"`
+
+	// Sink allows configuration of the KafkaSink installation.
+	// +optional
+	Sink SinkSpec `json:"sink,omitempty"`
+
+	// Channel allows configuration of the KafkaChannel installation.
+	// +optional
+	Channel ChannelSpec `json:"channel,omitempty"`
+
+	// A means to override the corresponding entries in the upstream configmaps
+	// +optional
+	Config base.ConfigMapData `json:"config,omitempty"`
+
+	// DispatcherScaling allows configuration of the autoscaling of the dispatchers of all
+	// the data planes.
+	// +optional
+	DispatcherScaling *DispatcherScaling `json:"dispatcherScaling,omitempty"`
+
+	// HighAvailability allows specification of HA control plane.
+	// +optional
+	HighAvailability *base.HighAvailability `json:"high-availability,omitempty"`
+
+	// Logging allows configuration of the logging of the data plane.
+	// +optional
+	Logging *Logging `json:"logging,omitempty"`
+
+	// Workloads overrides workloads configurations such as resources and replicas.
+	// +optional
+	Workloads []base.WorkloadOverride `json:"workloads,omitempty"`
+
+	// Clusters are named Kafka cluster profiles that the broker, channel and sink can
+	// reference. A broker config ConfigMap named kafka-cluster-profile-<name> is generated
+	// for each profile, which tenants can select in their Broker's spec.config.
+	// +optional
+	Clusters []KafkaCluster `json:"clusters,omitempty"`
+}
+
+// BrokerSpec allows configuration of the KafkaBroker installation.
+type BrokerSpec struct {
+	// Enabled defines if the KafkaBroker installation is enabled.
+	Enabled bool `json:"enabled"`
+
+	// Kafka is the Kafka cluster the brokers use by default. It can be overridden on the
+	// individual broker object's config map.
+	// +optional
+	Kafka KafkaConnection `json:"kafka,omitempty"`
+
+	// Topic allows configuration of the topics created for the brokers.
+	// +optional
+	Topic TopicSpec `json:"topic,omitempty"`
+
+	// DataPlane allows tuning of the KafkaBroker data plane.
+	// +optional
+	DataPlane *DataPlaneConfig `json:"dataPlane,omitempty"`
+
+	// Scaling allows configuration of the autoscaling of the KafkaBroker data plane.
+	// +optional
+	Scaling *Scaling `json:"scaling,omitempty"`
+}
+
+// SourceSpec allows configuration of the KafkaSource installation.
+type SourceSpec struct {
+	// Enabled defines if the KafkaSource installation is enabled.
+	Enabled bool `json:"enabled"`
+
+	// DataPlane allows tuning of the KafkaSource data plane.
+	// +optional
+	DataPlane *DataPlaneConfig `json:"dataPlane,omitempty"`
+}
+
+// SinkSpec allows configuration of the KafkaSink installation.
+type SinkSpec struct {
+	// Enabled defines if the KafkaSink installation is enabled.
+	Enabled bool `json:"enabled"`
+
+	// Cluster is the name of the Kafka cluster profile used for the general KafkaSink
+	// configuration. Individual KafkaSinks still define their own bootstrap servers.
+	// +optional
+	Cluster string `json:"cluster,omitempty"`
+
+	// DataPlane allows tuning of the KafkaSink data plane.
+	// +optional
+	DataPlane *DataPlaneConfig `json:"dataPlane,omitempty"`
+
+	// Scaling allows configuration of the autoscaling of the KafkaSink data plane.
+	// +optional
+	Scaling *Scaling `json:"scaling,omitempty"`
+}
+
+// ChannelSpec allows configuration of the KafkaChannel installation.
+type ChannelSpec struct {
+	// Enabled defines if the KafkaChannel installation is enabled.
+	Enabled bool `json:"enabled"`
+
+	// Kafka is the Kafka cluster the KafkaChannels use.
+	// +optional
+	Kafka ChannelKafkaConnection `json:"kafka,omitempty"`
+
+	// DataPlane allows tuning of the KafkaChannel data plane.
+	// +optional
+	DataPlane *DataPlaneConfig `json:"dataPlane,omitempty"`
+
+	// Scaling allows configuration of the autoscaling of the KafkaChannel data plane.
+	// +optional
+	Scaling *Scaling `json:"scaling,omitempty"`
+}
+
+// KafkaConnection defines the Kafka cluster a component connects to, either through a
+// cluster profile or through its bootstrap servers and auth secret.
+type KafkaConnection struct {
+	// Cluster is the name of the Kafka cluster profile. It is mutually exclusive with
+	// BootstrapServers and AuthSecretName.
+	// +optional
+	Cluster string `json:"cluster,omitempty"`
+
+	// BootstrapServers is a comma-separated string of bootstrap servers of the cluster.
+	// +optional
+	BootstrapServers string `json:"bootstrapServers,omitempty"`
+
+	// AuthSecretName is the name of the secret that contains Kafka auth configuration.
+	// +optional
+	AuthSecretName string `json:"authSecretName,omitempty"`
+}
+
+// ChannelKafkaConnection defines the Kafka cluster the KafkaChannels connect to.
+type ChannelKafkaConnection struct {
+	KafkaConnection `json:",inline"`
+
+	// AuthSecretNamespace is the namespace of the secret that contains Kafka auth
+	// configuration. It is mutually exclusive with Cluster.
+	// +optional
+	AuthSecretNamespace string `json:"authSecretNamespace,omitempty"`
+}
+
+// TopicSpec allows configuration of the Kafka topics created by the control plane.
+type TopicSpec struct {
+	// NumPartitions is the number of partitions of a Kafka topic. By default, it is set to 10.
+	// +optional
+	NumPartitions int32 `json:"numPartitions,omitempty"`
+
+	// ReplicationFactor is the replication factor of a Kafka topic. By default, it is set to 3.
+	// +optional
+	ReplicationFactor int16 `json:"replicationFactor,omitempty"`
+
+	// RetentionMillis is the time in milliseconds a message is retained in the topic
+	// (retention.ms). Use -1 to retain messages forever.
+	// +optional
+	RetentionMillis *int64 `json:"retentionMillis,omitempty"`
+
+	// MinInSyncReplicas is the minimum number of replicas that must acknowledge a
+	// write (min.insync.replicas). It must not exceed the replication factor.
+	// +optional
+	MinInSyncReplicas *int32 `json:"minInSyncReplicas,omitempty"`
+}
+
+// KafkaCluster is a named Kafka cluster profile.
+type KafkaCluster struct {
+	// Name identifies the profile, it must be a DNS-1123 label.
+	Name string `json:"name"`
+
+	// BootstrapServers is a comma-separated string of bootstrap servers of the cluster.
+	BootstrapServers string `json:"bootstrapServers"`
+
+	// AuthSecretName is the name of the secret that contains Kafka auth configuration.
+	// The secret must live in the namespace of the KnativeKafka.
+	// +optional
+	AuthSecretName string `json:"authSecretName,omitempty"`
+}
+
+// DataPlaneConfig allows tuning of the Kafka clients used by a data plane.
+type DataPlaneConfig struct {
+	// Producer overrides properties of the Kafka producer, e.g. "linger.ms".
+	// +optional
+	Producer map[string]string `json:"producer,omitempty"`
+
+	// Consumer overrides properties of the Kafka consumer, e.g. "fetch.min.bytes".
+	// Not supported by KafkaSink, which doesn't consume.
+	// +optional
+	Consumer map[string]string `json:"consumer,omitempty"`
+
+	// Dispatcher allows configuration of the dispatcher.
+	// Not supported by KafkaSink, which doesn't dispatch.
+	// +optional
+	Dispatcher *DispatcherConfig `json:"dispatcher,omitempty"`
+}
+
+// DispatcherConfig allows configuration of how events are dispatched to subscribers.
+type DispatcherConfig struct {
+	// MaxPollRecords is the maximum number of records returned by a single poll
+	// (max.poll.records), which bounds the number of events dispatched concurrently.
+	// +optional
+	MaxPollRecords *int32 `json:"maxPollRecords,omitempty"`
+
+	// MaxPoolSize is the maximum number of concurrent HTTP connections the dispatcher
+	// opens to subscribers.
+	// +optional
+	MaxPoolSize *int32 `json:"maxPoolSize,omitempty"`
+
+	// DeliveryOrder is the delivery order of the subscriptions that don't set their own,
+	// either ordered or unordered.
+	// +optional
+	// +kubebuilder:validation:Enum=ordered;unordered
+	DeliveryOrder DeliveryOrder `json:"deliveryOrder,omitempty"`
+}
+
+// DeliveryOrder is the order in which the dispatcher delivers the events of a partition.
+type DeliveryOrder string
+
+const (
+	// DeliveryOrderOrdered waits for an event to be delivered before dispatching the next
+	// event of the partition.
+	DeliveryOrderOrdered DeliveryOrder = "ordered"
+	// DeliveryOrderUnordered dispatches the events of a partition concurrently.
+	DeliveryOrderUnordered DeliveryOrder = "unordered"
+)
+
+// Scaling allows configuration of the autoscaling of the workloads of a data plane. The
+// replicas of a scaled workload are left to its autoscaler, they are no longer set by the
+// operator, including through the workloads overrides. The dispatchers are scaled by the
+// kafka-controller, see DispatcherScaling.
+type Scaling struct {
+	// Receiver allows configuration of the autoscaling of the receiver Deployment.
+	// +optional
+	Receiver *WorkloadScaling `json:"receiver,omitempty"`
+}
+
+// WorkloadScaling allows configuration of the autoscaling of a workload through a
+// HorizontalPodAutoscaler.
+type WorkloadScaling struct {
+	// MinReplicas is the lower limit of the number of replicas. By default, it is set to 1.
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// MaxReplicas is the upper limit of the number of replicas.
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// CPUUtilization is the target average CPU utilization, in percent of the requests.
+	// +optional
+	CPUUtilization *int32 `json:"cpuUtilization,omitempty"`
+
+	// MemoryUtilization is the target average memory utilization, in percent of the requests.
+	// +optional
+	MemoryUtilization *int32 `json:"memoryUtilization,omitempty"`
+}
+
+// DispatcherScaling allows configuration of the autoscaling of the dispatchers. The
+// kafka-controller scales the dispatcher StatefulSets on the consumer lag of the consumer
+// groups they host, these values are rendered into its config-kafka-autoscaler ConfigMap.
+type DispatcherScaling struct {
+	// MinScale is the minimum number of consumers of a consumer group.
+	// +optional
+	MinScale *int32 `json:"minScale,omitempty"`
+
+	// MaxScale is the maximum number of consumers of a consumer group.
+	// +optional
+	MaxScale *int32 `json:"maxScale,omitempty"`
+
+	// LagThreshold is the target lag per consumer of a consumer group.
+	// +optional
+	LagThreshold *int64 `json:"lagThreshold,omitempty"`
+}
+
+// LogLevel is the log level of the data plane.
+type LogLevel string
+
+const (
+	LogLevelTrace LogLevel = "TRACE"
+	LogLevelDebug LogLevel = "DEBUG"
+	LogLevelInfo  LogLevel = "INFO"
+	LogLevelWarn  LogLevel = "WARN"
+	LogLevelError LogLevel = "ERROR"
+)
+
+// Logging allows configuration of the logging of the data plane.
+type Logging struct {
+	// Level is the log level. By default, it is set to INFO.
+	// +optional
+	Level LogLevel `json:"level,omitempty"`
+}
+
+// KnativeKafkaStatus defines the observed state of KnativeKafka
+// +k8s:openapi-gen=true
+type KnativeKafkaStatus struct {
+	duckv1.Status `json:",inline"`
+
+	// The version of the installed release
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Rollout records the progress of the staged rollout of the workloads.
+	// +optional
+	Rollout *KafkaRolloutStatus `json:"rollout,omitempty"`
+
+	// ManifestHashes are the content hashes of the last successfully applied manifest of
+	// each enabled component, keyed by component.
+	// +optional
+	ManifestHashes map[string]string `json:"manifestHashes,omitempty"`
+}
+
+// KafkaRolloutStatus records the progress of the staged rollout of the workloads.
+type KafkaRolloutStatus struct {
+	// Phase is the rollout phase waiting for its workloads to become available, if any.
+	// +optional
+	Phase string `json:"phase,omitempty"`
+
+	// Completed lists the rollout phases whose workloads are available, in rollout order.
+	// +optional
+	Completed []string `json:"completed,omitempty"`
+
+	// Waiting lists the workloads of Phase that are not available yet.
+	// +optional
+	Waiting []string `json:"waiting,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// KnativeKafka is the Schema for the knativekafkas API
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+type KnativeKafka struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KnativeKafkaSpec   `json:"spec,omitempty"`
+	Status KnativeKafkaStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// KnativeKafkaList contains a list of KnativeKafka
+type KnativeKafkaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KnativeKafka `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KnativeKafka{}, &KnativeKafkaList{})
+}
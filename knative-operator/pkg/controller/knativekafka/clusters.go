@@ -0,0 +1,108 @@
+package knativekafka
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	mf "github.com/manifestival/manifestival"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	serverlessoperatorv1alpha1 "github.com/openshift-knative/serverless-operator/knative-operator/pkg/apis/operator/v1alpha1"
+)
+
+const (
+	brokerConfigMapName  = "kafka-broker-config"
+	channelConfigMapName = "kafka-channel-config"
+
+	// clusterConfigMapPrefix prefixes the names of the broker config ConfigMaps generated
+	// for the Kafka cluster profiles. It prefixes none of the ConfigMaps of the manifests, so
+	// that a profile can't take over one of them.
+	clusterConfigMapPrefix = "kafka-cluster-profile-"
+	// clusterLabelKey labels the generated broker config ConfigMaps with their profile name.
+	clusterLabelKey = "operator.serverless.openshift.io/kafka-cluster"
+
+	sinkGeneralConfigMapEnv = "SINK_GENERAL_CONFIG_MAP_NAME"
+)
+
+// clusterConfigMapName returns the name of the broker config ConfigMap of the given profile.
+func clusterConfigMapName(cluster string) string {
+	return clusterConfigMapPrefix + cluster
+}
+
+// clusterProfileResources returns a copy of the kafka-broker-config ConfigMap of the given
+// manifest for each Kafka cluster profile. The copies are configured by configureEventingKafka.
+func clusterProfileResources(manifest mf.Manifest, clusters []serverlessoperatorv1alpha1.KafkaCluster) []unstructured.Unstructured {
+	base := manifest.Filter(mf.ByKind("ConfigMap"), mf.ByName(brokerConfigMapName)).Resources()
+	if len(base) == 0 {
+		return nil
+	}
+	resources := make([]unstructured.Unstructured, 0, len(clusters))
+	for _, cluster := range clusters {
+		u := base[0].DeepCopy()
+		u.SetName(clusterConfigMapName(cluster.Name))
+		labels := u.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[clusterLabelKey] = cluster.Name
+		u.SetLabels(labels)
+		resources = append(resources, *u)
+	}
+	return resources
+}
+
+// configureBrokerConfig renders the broker defaults into a broker config ConfigMap. The
+// bootstrap servers and auth secret are taken from the given profile, if any.
+func configureBrokerConfig(u *unstructured.Unstructured, cfg serverlessoperatorv1alpha1.BrokerDefaultConfig, cluster *serverlessoperatorv1alpha1.KafkaCluster) error {
+	bootstrapServers, authSecretName := cfg.BootstrapServers, cfg.AuthSecretName
+	if cluster != nil {
+		bootstrapServers, authSecretName = cluster.BootstrapServers, cluster.AuthSecretName
+	}
+
+	if err := unstructured.SetNestedField(u.Object, bootstrapServers, "data", "bootstrap.servers"); err != nil {
+		return err
+	}
+	if err := unstructured.SetNestedField(u.Object, strconv.FormatInt(int64(cfg.NumPartitions), 10), "data", "default.topic.partitions"); err != nil {
+		return err
+	}
+	if err := unstructured.SetNestedField(u.Object, strconv.FormatInt(int64(cfg.ReplicationFactor), 10), "data", "default.topic.replication.factor"); err != nil {
+		return err
+	}
+	if authSecretName != "" {
+		if err := unstructured.SetNestedField(u.Object, authSecretName, "data", "auth.secret.ref.name"); err != nil {
+			return err
+		}
+	}
+	if cfg.Topic != nil {
+		if err := configureTopic(u, cfg.Topic); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteStaleClusterProfiles deletes the broker config ConfigMaps of the Kafka cluster
+// profiles that have been removed from the spec, in the namespace of the KnativeKafka.
+func (r *ReconcileKnativeKafka) deleteStaleClusterProfiles(ctx context.Context) stage {
+	return func(_ *mf.Manifest, instance *serverlessoperatorv1alpha1.KnativeKafka) error {
+		configMaps := &corev1.ConfigMapList{}
+		if err := r.client.List(ctx, configMaps, client.InNamespace(instance.Namespace), client.HasLabels{clusterLabelKey}); err != nil {
+			return fmt.Errorf("failed to list Kafka cluster profile ConfigMaps: %w", err)
+		}
+		for i := range configMaps.Items {
+			cm := &configMaps.Items[i]
+			if enableControlPlaneManifest(instance.Spec) && instance.Spec.Cluster(cm.Labels[clusterLabelKey]) != nil {
+				continue
+			}
+			log.Info("Deleting stale Kafka cluster profile", "name", cm.Name)
+			if err := r.client.Delete(ctx, cm); err != nil && !apierrors.IsNotFound(err) {
+				return fmt.Errorf("failed to delete Kafka cluster profile ConfigMap %s: %w", cm.Name, err)
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,188 @@
+package knativekafka
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	mf "github.com/manifestival/manifestival"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openshift-knative/serverless-operator/knative-operator/pkg/apis/operator/v1alpha1"
+)
+
+var testClusters = []v1alpha1.KafkaCluster{{
+	Name:             "tenant-a",
+	BootstrapServers: "a.kafka:9092",
+}, {
+	Name:             "tenant-b",
+	BootstrapServers: "b.kafka:9093",
+	AuthSecretName:   "tenant-b-auth",
+}}
+
+func TestClusterProfileResources(t *testing.T) {
+	brokerConfig := unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":      "kafka-broker-config",
+			"namespace": "knative-eventing",
+			"labels":    map[string]interface{}{"app.kubernetes.io/version": "v1.21"},
+		},
+		"data": map[string]interface{}{
+			"bootstrap.servers": "my-cluster-kafka-bootstrap.kafka:9092",
+		},
+	}}
+	manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{brokerConfig}))
+	if err != nil {
+		t.Fatalf("Failed to build manifest: %v", err)
+	}
+
+	spec := v1alpha1.KnativeKafkaSpec{
+		Broker: v1alpha1.Broker{
+			DefaultConfig: v1alpha1.BrokerDefaultConfig{
+				BootstrapServers:  "default.kafka:9092",
+				NumPartitions:     12,
+				ReplicationFactor: 3,
+			},
+		},
+		Clusters: testClusters,
+	}
+
+	profiles := clusterProfileResources(manifest, spec.Clusters)
+	if len(profiles) != 2 {
+		t.Fatalf("clusterProfileResources() returned %d resources, want 2", len(profiles))
+	}
+	for i := range profiles {
+		if err := configureEventingKafka(spec)(&profiles[i]); err != nil {
+			t.Fatalf("configureEventingKafka: (%v)", err)
+		}
+	}
+
+	want := []map[string]interface{}{{
+		"bootstrap.servers":                "a.kafka:9092",
+		"default.topic.partitions":         "12",
+		"default.topic.replication.factor": "3",
+	}, {
+		"bootstrap.servers":                "b.kafka:9093",
+		"auth.secret.ref.name":             "tenant-b-auth",
+		"default.topic.partitions":         "12",
+		"default.topic.replication.factor": "3",
+	}}
+	for i, profile := range profiles {
+		if got, want := profile.GetName(), "kafka-cluster-profile-"+testClusters[i].Name; got != want {
+			t.Errorf("name = %q, want %q", got, want)
+		}
+		if got := profile.GetLabels()[clusterLabelKey]; got != testClusters[i].Name {
+			t.Errorf("label %s = %q, want %q", clusterLabelKey, got, testClusters[i].Name)
+		}
+		if !cmp.Equal(profile.Object["data"], want[i]) {
+			t.Errorf("data = %s", cmp.Diff(want[i], profile.Object["data"]))
+		}
+	}
+
+	// The base ConfigMap is left untouched.
+	if got := manifest.Resources()[0].GetName(); got != "kafka-broker-config" {
+		t.Errorf("base ConfigMap name = %q, want kafka-broker-config", got)
+	}
+}
+
+func TestClusterReferences(t *testing.T) {
+	spec := v1alpha1.KnativeKafkaSpec{
+		Broker: v1alpha1.Broker{
+			DefaultConfig: v1alpha1.BrokerDefaultConfig{
+				Cluster:           "tenant-a",
+				NumPartitions:     10,
+				ReplicationFactor: 3,
+			},
+		},
+		Channel:  v1alpha1.Channel{Cluster: "tenant-b"},
+		Sink:     v1alpha1.Sink{Cluster: "tenant-a"},
+		Clusters: testClusters,
+	}
+
+	broker := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "kafka-broker-config", "namespace": "knative-eventing"},
+	}}
+	if err := configureEventingKafka(spec)(broker); err != nil {
+		t.Fatalf("configureEventingKafka: (%v)", err)
+	}
+	if got := broker.Object["data"].(map[string]interface{})["bootstrap.servers"]; got != "a.kafka:9092" {
+		t.Errorf("kafka-broker-config bootstrap.servers = %v, want a.kafka:9092", got)
+	}
+
+	channel := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "kafka-channel-config", "namespace": "knative-eventing"},
+	}}
+	if err := configureEventingKafka(spec)(channel); err != nil {
+		t.Fatalf("configureEventingKafka: (%v)", err)
+	}
+	wantChannel := map[string]interface{}{
+		"bootstrap.servers":         "b.kafka:9093",
+		"auth.secret.ref.name":      "tenant-b-auth",
+		"auth.secret.ref.namespace": "knative-eventing",
+	}
+	if !cmp.Equal(channel.Object["data"], wantChannel) {
+		t.Errorf("kafka-channel-config data = %s", cmp.Diff(wantChannel, channel.Object["data"]))
+	}
+
+	deployment := makeEventingKafkaDeployment(t)
+	d := extractDeployment(t, deployment)
+	d.Spec.Template.Spec.Containers[0].Env = []corev1.EnvVar{
+		{Name: "BROKER_GENERAL_CONFIG_MAP_NAME", Value: "kafka-broker-config"},
+		{Name: sinkGeneralConfigMapEnv, Value: "kafka-broker-config"},
+	}
+	if err := scheme.Scheme.Convert(d, deployment, nil); err != nil {
+		t.Fatalf("Failed to convert Deployment: %v", err)
+	}
+	if err := configureEventingKafka(spec)(deployment); err != nil {
+		t.Fatalf("configureEventingKafka: (%v)", err)
+	}
+	wantEnv := []corev1.EnvVar{
+		{Name: "BROKER_GENERAL_CONFIG_MAP_NAME", Value: "kafka-broker-config"},
+		{Name: sinkGeneralConfigMapEnv, Value: "kafka-cluster-profile-tenant-a"},
+	}
+	if got := extractDeployment(t, deployment).Spec.Template.Spec.Containers[0].Env; !cmp.Equal(got, wantEnv) {
+		t.Errorf("env = %s", cmp.Diff(wantEnv, got))
+	}
+}
+
+func TestDeleteStaleClusterProfiles(t *testing.T) {
+	profile := func(namespace, name string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      clusterConfigMapName(name),
+			Labels:    map[string]string{clusterLabelKey: name},
+		}}
+	}
+	cl := fake.NewClientBuilder().WithObjects(profile("knative-eventing", "tenant-a"), profile("knative-eventing", "removed"),
+		profile("other", "removed")).Build()
+	r := &ReconcileKnativeKafka{client: cl}
+
+	instance := makeCr(withSourceEnabled, func(kk *v1alpha1.KnativeKafka) {
+		kk.Spec.Clusters = testClusters
+	})
+	if err := r.deleteStaleClusterProfiles(context.Background())(&mf.Manifest{}, instance); err != nil {
+		t.Fatalf("deleteStaleClusterProfiles() = %v", err)
+	}
+
+	if err := cl.Get(context.Background(), types.NamespacedName{Namespace: "knative-eventing", Name: "kafka-cluster-profile-tenant-a"}, &corev1.ConfigMap{}); err != nil {
+		t.Errorf("Get(kafka-cluster-profile-tenant-a) = %v, want nil", err)
+	}
+	if err := cl.Get(context.Background(), types.NamespacedName{Namespace: "knative-eventing", Name: "kafka-cluster-profile-removed"}, &corev1.ConfigMap{}); !apierrors.IsNotFound(err) {
+		t.Errorf("Get(kafka-cluster-profile-removed) = %v, want NotFound", err)
+	}
+	if err := cl.Get(context.Background(), types.NamespacedName{Namespace: "other", Name: "kafka-cluster-profile-removed"}, &corev1.ConfigMap{}); err != nil {
+		t.Errorf("Get(other/kafka-cluster-profile-removed) = %v, want the ConfigMap of the other namespace to be kept", err)
+	}
+}
@@ -0,0 +1,273 @@
+package knativekafka
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	mf "github.com/manifestival/manifestival"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/eventing-kafka-broker/control-plane/pkg/kafka"
+	"knative.dev/eventing-kafka-broker/control-plane/pkg/security"
+
+	serverlessoperatorv1alpha1 "github.com/openshift-knative/serverless-operator/knative-operator/pkg/apis/operator/v1alpha1"
+)
+
+const (
+	// clusterProbeTimeout bounds the time spent dialing and querying a Kafka cluster.
+	clusterProbeTimeout = 5 * time.Second
+	// clusterProbeRequeueAfter is the delay after which an unreachable Kafka cluster is probed again.
+	clusterProbeRequeueAfter = time.Minute
+	// clusterProbeCacheTTL is the time during which a successful probe of a Kafka cluster is reused,
+	// sparing a connection to the cluster on every reconciliation.
+	clusterProbeCacheTTL = 5 * time.Minute
+
+	clusterProbeClientID = "knative-kafka-openshift-probe"
+)
+
+// ClusterProbeResult holds the outcome of a successful Kafka cluster probe.
+type ClusterProbeResult struct {
+	// Brokers is the number of brokers advertised in the cluster metadata.
+	Brokers int
+	// Latency is the time it took to connect and fetch the cluster metadata.
+	Latency time.Duration
+}
+
+// ClusterProbe checks whether a Kafka cluster can be reached.
+type ClusterProbe interface {
+	// Probe connects to the given bootstrap servers, authenticating with the given secret
+	// if any, and fetches the cluster metadata.
+	Probe(ctx context.Context, bootstrapServers []string, secret *corev1.Secret) (ClusterProbeResult, error)
+}
+
+// saramaClusterProbe probes Kafka clusters with a metadata request issued by a sarama client.
+type saramaClusterProbe struct {
+	timeout time.Duration
+}
+
+var _ ClusterProbe = saramaClusterProbe{}
+
+func (p saramaClusterProbe) Probe(ctx context.Context, bootstrapServers []string, secret *corev1.Secret) (ClusterProbeResult, error) {
+	timeout := p.timeout
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := time.Until(deadline); remaining < timeout {
+			timeout = remaining
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return ClusterProbeResult{}, err
+	}
+
+	var opts []kafka.ConfigOption
+	if secret != nil {
+		opt, err := security.NewSaramaSecurityOptionFromSecret(secret)
+		if err != nil {
+			return ClusterProbeResult{}, fmt.Errorf("invalid auth secret %s/%s: %w", secret.Namespace, secret.Name, err)
+		}
+		opts = append(opts, opt)
+	}
+	config, err := kafka.GetSaramaConfig(opts...)
+	if err != nil {
+		return ClusterProbeResult{}, fmt.Errorf("failed to create Kafka client configuration: %w", err)
+	}
+	config.ClientID = clusterProbeClientID
+	config.Net.DialTimeout = timeout
+	config.Net.ReadTimeout = timeout
+	config.Net.WriteTimeout = timeout
+	config.Metadata.Retry.Max = 0
+	config.Metadata.Full = true
+
+	type clientResult struct {
+		client sarama.Client
+		err    error
+	}
+	start := time.Now()
+	done := make(chan clientResult, 1)
+	go func() {
+		// Creating the client dials the bootstrap servers and fetches the cluster metadata.
+		c, err := sarama.NewClient(bootstrapServers, config)
+		done <- clientResult{client: c, err: err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		// Close the client once created, the dials are bounded by the timeouts.
+		go func() {
+			if res := <-done; res.err == nil {
+				res.client.Close()
+			}
+		}()
+		return ClusterProbeResult{}, ctx.Err()
+	case res := <-done:
+		if res.err != nil {
+			return ClusterProbeResult{}, res.err
+		}
+		defer res.client.Close()
+		return ClusterProbeResult{
+			Brokers: len(res.client.Brokers()),
+			Latency: time.Since(start),
+		}, nil
+	}
+}
+
+// clusterProbeCache holds the successful probes of Kafka clusters for a while.
+type clusterProbeCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	now     func() time.Time
+	results map[string]cachedClusterProbe
+}
+
+type cachedClusterProbe struct {
+	result ClusterProbeResult
+	expiry time.Time
+}
+
+func newClusterProbeCache(ttl time.Duration) *clusterProbeCache {
+	return &clusterProbeCache{
+		ttl:     ttl,
+		now:     time.Now,
+		results: make(map[string]cachedClusterProbe),
+	}
+}
+
+// clusterProbeKey identifies a probe by the cluster and the revision of its auth secret, so that
+// changing the credentials probes the cluster again.
+func clusterProbeKey(bootstrapServers string, secret *corev1.Secret) string {
+	if secret == nil {
+		return bootstrapServers
+	}
+	return bootstrapServers + "|" + secret.Namespace + "/" + secret.Name + "@" + secret.ResourceVersion
+}
+
+func (c *clusterProbeCache) get(key string) (ClusterProbeResult, bool) {
+	if c == nil {
+		return ClusterProbeResult{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached, ok := c.results[key]
+	if !ok || c.now().After(cached.expiry) {
+		delete(c.results, key)
+		return ClusterProbeResult{}, false
+	}
+	return cached.result, true
+}
+
+func (c *clusterProbeCache) put(key string, result ClusterProbeResult) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.results[key] = cachedClusterProbe{result: result, expiry: c.now().Add(c.ttl)}
+}
+
+// kafkaCluster is a Kafka cluster used by a component along with the secret to authenticate with.
+type kafkaCluster struct {
+	bootstrapServers string
+	authSecret       *types.NamespacedName
+}
+
+// kafkaClusters returns the distinct Kafka clusters configured for the enabled components,
+// including the cluster profiles that tenants can select. The source and the sink are
+// configured per resource and only contribute through the profiles.
+func kafkaClusters(instance *serverlessoperatorv1alpha1.KnativeKafka) []kafkaCluster {
+	var clusters []kafkaCluster
+	add := func(c kafkaCluster) {
+		for _, existing := range clusters {
+			if existing.bootstrapServers == c.bootstrapServers &&
+				(existing.authSecret == nil) == (c.authSecret == nil) &&
+				(existing.authSecret == nil || *existing.authSecret == *c.authSecret) {
+				return
+			}
+		}
+		clusters = append(clusters, c)
+	}
+	profile := func(p *serverlessoperatorv1alpha1.KafkaCluster) kafkaCluster {
+		c := kafkaCluster{bootstrapServers: p.BootstrapServers}
+		if p.AuthSecretName != "" {
+			c.authSecret = &types.NamespacedName{Namespace: instance.Namespace, Name: p.AuthSecretName}
+		}
+		return c
+	}
+
+	spec := &instance.Spec
+	if broker := spec.Broker; broker.Enabled {
+		if p := spec.Cluster(broker.DefaultConfig.Cluster); p != nil {
+			add(profile(p))
+		} else if broker.DefaultConfig.BootstrapServers != "" {
+			c := kafkaCluster{bootstrapServers: broker.DefaultConfig.BootstrapServers}
+			if broker.DefaultConfig.AuthSecretName != "" {
+				c.authSecret = &types.NamespacedName{Namespace: instance.Namespace, Name: broker.DefaultConfig.AuthSecretName}
+			}
+			add(c)
+		}
+	}
+	if channel := spec.Channel; channel.Enabled {
+		if p := spec.Cluster(channel.Cluster); p != nil {
+			add(profile(p))
+		} else if channel.BootstrapServers != "" {
+			c := kafkaCluster{bootstrapServers: channel.BootstrapServers}
+			if channel.AuthSecretName != "" {
+				c.authSecret = &types.NamespacedName{Namespace: channel.AuthSecretNamespace, Name: channel.AuthSecretName}
+			}
+			add(c)
+		}
+	}
+	if enableControlPlaneManifest(*spec) {
+		for i := range spec.Clusters {
+			add(profile(&spec.Clusters[i]))
+		}
+	}
+	return clusters
+}
+
+// probeKafkaClusters probes the Kafka clusters of the enabled components and records the
+// outcome in the KafkaClusterReachable condition. An unreachable cluster doesn't fail the
+// reconciliation, so that the remaining components are still installed and removed.
+func (r *ReconcileKnativeKafka) probeKafkaClusters(ctx context.Context) stage {
+	return func(_ *mf.Manifest, instance *serverlessoperatorv1alpha1.KnativeKafka) error {
+		clusters := kafkaClusters(instance)
+		if len(clusters) == 0 {
+			instance.Status.MarkKafkaClusterNotConfigured()
+			return nil
+		}
+
+		log.Info("Probing Kafka clusters")
+		results := make([]string, 0, len(clusters))
+		for _, cluster := range clusters {
+			var secret *corev1.Secret
+			if cluster.authSecret != nil {
+				secret = &corev1.Secret{}
+				// Read the secret from the API server, sparing a cache of all the secrets.
+				if err := r.apiReader.Get(ctx, *cluster.authSecret, secret); err != nil {
+					instance.Status.MarkKafkaClusterUnreachable(fmt.Sprintf("failed to get auth secret %s: %v", cluster.authSecret, err))
+					return nil
+				}
+			}
+
+			key := clusterProbeKey(cluster.bootstrapServers, secret)
+			result, ok := r.clusterProbes.get(key)
+			if !ok {
+				probeCtx, cancel := context.WithTimeout(ctx, clusterProbeTimeout)
+				var err error
+				result, err = r.clusterProbe.Probe(probeCtx, kafka.BootstrapServersArray(cluster.bootstrapServers), secret)
+				cancel()
+				if err != nil {
+					instance.Status.MarkKafkaClusterUnreachable(fmt.Sprintf("%s: %v", cluster.bootstrapServers, err))
+					return nil
+				}
+				r.clusterProbes.put(key, result)
+			}
+			results = append(results, fmt.Sprintf("%s: %d brokers in %s",
+				cluster.bootstrapServers, result.Brokers, result.Latency.Round(time.Millisecond)))
+		}
+		instance.Status.MarkKafkaClusterReachable(strings.Join(results, "; "))
+		return nil
+	}
+}
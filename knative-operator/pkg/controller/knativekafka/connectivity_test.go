@@ -0,0 +1,236 @@
+package knativekafka
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	mf "github.com/manifestival/manifestival"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openshift-knative/serverless-operator/knative-operator/pkg/apis/operator/v1alpha1"
+)
+
+// fakeClusterProbe records the probed clusters and returns a fixed result.
+type fakeClusterProbe struct {
+	err     error
+	brokers int
+	probed  []string
+}
+
+func (p *fakeClusterProbe) Probe(_ context.Context, bootstrapServers []string, _ *corev1.Secret) (ClusterProbeResult, error) {
+	p.probed = append(p.probed, strings.Join(bootstrapServers, ","))
+	if p.err != nil {
+		return ClusterProbeResult{}, p.err
+	}
+	return ClusterProbeResult{Brokers: p.brokers, Latency: 12 * time.Millisecond}, nil
+}
+
+func TestSaramaClusterProbe(t *testing.T) {
+	seed := sarama.NewMockBroker(t, 1)
+	defer seed.Close()
+	// The second broker is only advertised in the metadata and is closed below.
+	other := sarama.NewMockBroker(t, 2)
+
+	seed.SetHandlerByMap(map[string]sarama.MockResponse{
+		"ApiVersionsRequest": sarama.NewMockApiVersionsResponse(t),
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(seed.Addr(), seed.BrokerID()).
+			SetBroker(other.Addr(), other.BrokerID()).
+			SetController(seed.BrokerID()),
+	})
+
+	probe := saramaClusterProbe{timeout: time.Second}
+
+	result, err := probe.Probe(context.Background(), []string{seed.Addr()}, nil)
+	if err != nil {
+		t.Fatalf("Probe() = %v", err)
+	}
+	if result.Brokers != 2 {
+		t.Errorf("Probe() brokers = %d, want 2", result.Brokers)
+	}
+	if result.Latency <= 0 {
+		t.Errorf("Probe() latency = %v, want > 0", result.Latency)
+	}
+
+	// Nothing listens on a closed broker.
+	addr := other.Addr()
+	other.Close()
+	if _, err := probe.Probe(context.Background(), []string{addr}, nil); err == nil {
+		t.Error("Probe() = nil, want an error for an unreachable cluster")
+	}
+
+	invalidSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "knative-eventing", Name: "auth"},
+		Data:       map[string][]byte{"protocol": []byte("FOO")},
+	}
+	if _, err := probe.Probe(context.Background(), []string{seed.Addr()}, invalidSecret); err == nil {
+		t.Error("Probe() = nil, want an error for an invalid auth secret")
+	}
+}
+
+func TestProbeKafkaClusters(t *testing.T) {
+	authSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "knative-eventing", Name: "broker-auth"},
+		Data:       map[string][]byte{"protocol": []byte("PLAINTEXT")},
+	}
+
+	tests := []struct {
+		name       string
+		instance   *v1alpha1.KnativeKafka
+		probe      *fakeClusterProbe
+		wantProbed []string
+		wantStatus corev1.ConditionStatus
+		wantReason string
+		wantMsg    string
+	}{{
+		name:       "no cluster configured",
+		instance:   makeCr(withSourceEnabled),
+		probe:      &fakeClusterProbe{},
+		wantStatus: corev1.ConditionTrue,
+		wantReason: "NotConfigured",
+	}, {
+		name:       "channel cluster reachable",
+		instance:   makeCr(withChannelEnabled),
+		probe:      &fakeClusterProbe{brokers: 3},
+		wantProbed: []string{"foo.bar.com"},
+		wantStatus: corev1.ConditionTrue,
+		wantReason: "Reachable",
+		wantMsg:    "foo.bar.com: 3 brokers in 12ms",
+	}, {
+		name:       "channel cluster unreachable",
+		instance:   makeCr(withChannelEnabled),
+		probe:      &fakeClusterProbe{err: errors.New("connection refused")},
+		wantProbed: []string{"foo.bar.com"},
+		wantStatus: corev1.ConditionFalse,
+		wantReason: "Unreachable",
+		wantMsg:    "Failed to reach Kafka cluster: foo.bar.com: connection refused",
+	}, {
+		name: "broker and channel share the cluster",
+		instance: makeCr(withChannelEnabled, func(kk *v1alpha1.KnativeKafka) {
+			kk.Spec.Broker.Enabled = true
+			kk.Spec.Broker.DefaultConfig.BootstrapServers = "foo.bar.com"
+		}),
+		probe:      &fakeClusterProbe{brokers: 3},
+		wantProbed: []string{"foo.bar.com"},
+		wantStatus: corev1.ConditionTrue,
+		wantReason: "Reachable",
+		wantMsg:    "foo.bar.com: 3 brokers in 12ms",
+	}, {
+		name: "broker with auth secret",
+		instance: makeCr(func(kk *v1alpha1.KnativeKafka) {
+			kk.Spec.Broker.Enabled = true
+			kk.Spec.Broker.DefaultConfig.BootstrapServers = "a:9092,b:9092"
+			kk.Spec.Broker.DefaultConfig.AuthSecretName = "broker-auth"
+		}),
+		probe:      &fakeClusterProbe{brokers: 2},
+		wantProbed: []string{"a:9092,b:9092"},
+		wantStatus: corev1.ConditionTrue,
+		wantReason: "Reachable",
+		wantMsg:    "a:9092,b:9092: 2 brokers in 12ms",
+	}, {
+		name: "broker with missing auth secret",
+		instance: makeCr(func(kk *v1alpha1.KnativeKafka) {
+			kk.Spec.Broker.Enabled = true
+			kk.Spec.Broker.DefaultConfig.BootstrapServers = "a:9092"
+			kk.Spec.Broker.DefaultConfig.AuthSecretName = "missing"
+		}),
+		probe:      &fakeClusterProbe{},
+		wantStatus: corev1.ConditionFalse,
+		wantReason: "Unreachable",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			kubeClient := fake.NewClientBuilder().WithObjects(authSecret).Build()
+			r := &ReconcileKnativeKafka{
+				client:       kubeClient,
+				apiReader:    kubeClient,
+				clusterProbe: test.probe,
+			}
+			test.instance.Status.InitializeConditions()
+
+			if err := r.probeKafkaClusters(context.Background())(&mf.Manifest{}, test.instance); err != nil {
+				t.Fatalf("probeKafkaClusters() = %v", err)
+			}
+
+			if got := strings.Join(test.probe.probed, " "); got != strings.Join(test.wantProbed, " ") {
+				t.Errorf("probed = %q, want %q", got, test.wantProbed)
+			}
+			c := test.instance.Status.GetCondition(v1alpha1.KafkaClusterReachable)
+			if c == nil {
+				t.Fatal("KafkaClusterReachable condition is missing")
+			}
+			if c.Status != test.wantStatus || c.Reason != test.wantReason {
+				t.Errorf("KafkaClusterReachable = %s/%s, want %s/%s", c.Status, c.Reason, test.wantStatus, test.wantReason)
+			}
+			if test.wantMsg != "" && c.Message != test.wantMsg {
+				t.Errorf("KafkaClusterReachable message = %q, want %q", c.Message, test.wantMsg)
+			}
+		})
+	}
+}
+
+func TestProbeKafkaClustersCache(t *testing.T) {
+	probe := &fakeClusterProbe{brokers: 3}
+	cache := newClusterProbeCache(time.Minute)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	c := fake.NewClientBuilder().Build()
+	r := &ReconcileKnativeKafka{
+		client:        c,
+		apiReader:     c,
+		clusterProbe:  probe,
+		clusterProbes: cache,
+	}
+
+	probeTwice := func() {
+		for i := 0; i < 2; i++ {
+			instance := makeCr(withChannelEnabled)
+			instance.Status.InitializeConditions()
+			if err := r.probeKafkaClusters(context.Background())(&mf.Manifest{}, instance); err != nil {
+				t.Fatalf("probeKafkaClusters() = %v", err)
+			}
+			if !instance.Status.GetCondition(v1alpha1.KafkaClusterReachable).IsTrue() {
+				t.Fatal("KafkaClusterReachable is not true")
+			}
+		}
+	}
+
+	probeTwice()
+	if len(probe.probed) != 1 {
+		t.Errorf("probed %d times, want the successful probe to be cached", len(probe.probed))
+	}
+
+	now = now.Add(2 * time.Minute)
+	probeTwice()
+	if len(probe.probed) != 2 {
+		t.Errorf("probed %d times, want the expired probe to be repeated", len(probe.probed))
+	}
+
+	probe.err = errors.New("connection refused")
+	now = now.Add(2 * time.Minute)
+	instance := makeCr(withChannelEnabled)
+	instance.Status.InitializeConditions()
+	if err := r.probeKafkaClusters(context.Background())(&mf.Manifest{}, instance); err != nil {
+		t.Fatalf("probeKafkaClusters() = %v", err)
+	}
+	if _, ok := cache.get(clusterProbeKey("foo.bar.com", nil)); ok {
+		t.Error("Failed probe was cached")
+	}
+}
+
+func TestSaramaClusterProbeContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	probe := saramaClusterProbe{timeout: time.Second}
+	if _, err := probe.Probe(ctx, []string{"127.0.0.1:1"}, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("Probe() = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,148 @@
+package knativekafka
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	serverlessoperatorv1alpha1 "github.com/openshift-knative/serverless-operator/knative-operator/pkg/apis/operator/v1alpha1"
+)
+
+const (
+	topicRetentionMsKey         = "default.topic.config.retention.ms"
+	topicMinInSyncReplicasKey   = "default.topic.config.min.insync.replicas"
+	consumerMaxPollRecordsKey   = "max.poll.records"
+	webClientMaxPoolSizeKey     = "maxPoolSize"
+	deliveryOrderKey            = "default.delivery.order"
+	dataPlaneConfigMapKeyPrefix = "config-kafka-"
+)
+
+// dataPlaneConfigFor returns the data plane configuration of the component owning the given
+// data plane ConfigMap, along with the prefix of the ConfigMap keys.
+func dataPlaneConfigFor(spec serverlessoperatorv1alpha1.KnativeKafkaSpec, configMap string) (*serverlessoperatorv1alpha1.DataPlaneConfig, string) {
+	switch configMap {
+	case "config-kafka-broker-data-plane":
+		return spec.Broker.DataPlane, dataPlaneConfigMapKeyPrefix + "broker"
+	case "config-kafka-channel-data-plane":
+		return spec.Channel.DataPlane, dataPlaneConfigMapKeyPrefix + "channel"
+	case "config-kafka-source-data-plane":
+		return spec.Source.DataPlane, dataPlaneConfigMapKeyPrefix + "source"
+	case "config-kafka-sink-data-plane":
+		return spec.Sink.DataPlane, dataPlaneConfigMapKeyPrefix + "sink"
+	}
+	return nil, ""
+}
+
+// configureDataPlane renders the data plane configuration into the properties files of the
+// given data plane ConfigMap.
+func configureDataPlane(u *unstructured.Unstructured, prefix string, cfg *serverlessoperatorv1alpha1.DataPlaneConfig) error {
+	consumer := make(map[string]string, len(cfg.Consumer)+1)
+	for k, v := range cfg.Consumer {
+		consumer[k] = v
+	}
+	webClient := make(map[string]string, 1)
+	if cfg.Dispatcher != nil {
+		if cfg.Dispatcher.MaxPollRecords != nil {
+			consumer[consumerMaxPollRecordsKey] = strconv.FormatInt(int64(*cfg.Dispatcher.MaxPollRecords), 10)
+		}
+		if cfg.Dispatcher.MaxPoolSize != nil {
+			webClient[webClientMaxPoolSizeKey] = strconv.FormatInt(int64(*cfg.Dispatcher.MaxPoolSize), 10)
+		}
+	}
+
+	files := map[string]map[string]string{
+		prefix + "-producer.properties":  cfg.Producer,
+		prefix + "-consumer.properties":  consumer,
+		prefix + "-webclient.properties": webClient,
+	}
+	for key, overrides := range files {
+		if len(overrides) == 0 {
+			continue
+		}
+		existing, _, _ := unstructured.NestedString(u.Object, "data", key)
+		if err := unstructured.SetNestedField(u.Object, setProperties(existing, overrides), "data", key); err != nil {
+			return err
+		}
+	}
+	if cfg.Dispatcher != nil && cfg.Dispatcher.DeliveryOrder != "" {
+		if err := unstructured.SetNestedField(u.Object, string(cfg.Dispatcher.DeliveryOrder), "data", deliveryOrderKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// configureTopic renders the topic configuration into the kafka-broker-config ConfigMap.
+func configureTopic(u *unstructured.Unstructured, topic *serverlessoperatorv1alpha1.TopicConfig) error {
+	if topic.RetentionMillis != nil {
+		if err := unstructured.SetNestedField(u.Object, strconv.FormatInt(*topic.RetentionMillis, 10), "data", topicRetentionMsKey); err != nil {
+			return err
+		}
+	}
+	if topic.MinInSyncReplicas != nil {
+		if err := unstructured.SetNestedField(u.Object, strconv.FormatInt(int64(*topic.MinInSyncReplicas), 10), "data", topicMinInSyncReplicasKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setProperties sets the given properties in a Java properties document. Existing entries,
+// including commented-out ones, are replaced in place and the remaining ones are appended
+// in alphabetical order.
+func setProperties(existing string, overrides map[string]string) string {
+	lines := strings.Split(existing, "\n")
+	set := make(map[string]bool, len(overrides))
+
+	// Prefer active entries over commented-out ones.
+	for _, commented := range []bool{false, true} {
+		for i, line := range lines {
+			key, isCommented, ok := propertyKey(line)
+			if !ok || isCommented != commented || set[key] {
+				continue
+			}
+			if value, found := overrides[key]; found {
+				lines[i] = key + "=" + value
+				set[key] = true
+			}
+		}
+	}
+
+	missing := make([]string, 0, len(overrides))
+	for key := range overrides {
+		if !set[key] {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+
+	// Keep a trailing newline at the end of the document.
+	if n := len(lines); n > 0 && lines[n-1] == "" {
+		lines = lines[:n-1]
+	}
+	for _, key := range missing {
+		lines = append(lines, key+"="+overrides[key])
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// propertyKey returns the key of a Java properties line and whether it's commented out.
+func propertyKey(line string) (key string, commented bool, ok bool) {
+	line = strings.TrimSpace(line)
+	if strings.HasPrefix(line, "#") {
+		commented = true
+		line = strings.TrimSpace(strings.TrimPrefix(line, "#"))
+	}
+	key, _, found := strings.Cut(line, "=")
+	key = strings.TrimSpace(key)
+	if key == "" || strings.ContainsAny(key, " \t") {
+		return "", false, false
+	}
+	// Commented-out lines without a value, like "# sasl.mechanism", are placeholders as well.
+	if !found && !commented {
+		return "", false, false
+	}
+	return key, commented, true
+}
@@ -0,0 +1,161 @@
+package knativekafka
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"knative.dev/pkg/ptr"
+
+	"github.com/openshift-knative/serverless-operator/knative-operator/pkg/apis/operator/v1alpha1"
+)
+
+func TestSetProperties(t *testing.T) {
+	tests := []struct {
+		name      string
+		existing  string
+		overrides map[string]string
+		expected  string
+	}{{
+		name:      "empty document",
+		overrides: map[string]string{"linger.ms": "5", "acks": "1"},
+		expected:  "acks=1\nlinger.ms=5\n",
+	}, {
+		name:      "replace existing entry",
+		existing:  "acks=all\nlinger.ms=0\n",
+		overrides: map[string]string{"linger.ms": "5"},
+		expected:  "acks=all\nlinger.ms=5\n",
+	}, {
+		name:      "replace commented-out entries",
+		existing:  "acks=all\n# compression.type=snappy\n# sasl.mechanism\n",
+		overrides: map[string]string{"compression.type": "lz4", "sasl.mechanism": "PLAIN"},
+		expected:  "acks=all\ncompression.type=lz4\nsasl.mechanism=PLAIN\n",
+	}, {
+		name:      "prefer active entries over commented-out ones",
+		existing:  "# linger.ms=10\nlinger.ms=0\n",
+		overrides: map[string]string{"linger.ms": "5"},
+		expected:  "# linger.ms=10\nlinger.ms=5\n",
+	}, {
+		name:      "append missing entries",
+		existing:  "acks=all",
+		overrides: map[string]string{"linger.ms": "5", "batch.size": "32768"},
+		expected:  "acks=all\nbatch.size=32768\nlinger.ms=5\n",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := setProperties(test.existing, test.overrides)
+			if got != test.expected {
+				t.Errorf("Got = %q, want: %q", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestDataPlaneCfg(t *testing.T) {
+	configMap := func(name string, data map[string]interface{}) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata": map[string]interface{}{
+					"name": name,
+				},
+			},
+		}
+		if data != nil {
+			u.Object["data"] = data
+		}
+		return u
+	}
+
+	tests := []struct {
+		name         string
+		obj          *unstructured.Unstructured
+		knativeKafka v1alpha1.KnativeKafkaSpec
+		expect       *unstructured.Unstructured
+	}{{
+		name: "Update broker data plane",
+		obj: configMap("config-kafka-broker-data-plane", map[string]interface{}{
+			"config-kafka-broker-producer.properties":  "acks=all\nlinger.ms=0\n",
+			"config-kafka-broker-consumer.properties":  "max.poll.records=50\n",
+			"config-kafka-broker-webclient.properties": "idleTimeout=10000\nmaxPoolSize=100\n",
+		}),
+		knativeKafka: v1alpha1.KnativeKafkaSpec{
+			Broker: v1alpha1.Broker{
+				DataPlane: &v1alpha1.DataPlaneConfig{
+					Producer: map[string]string{"linger.ms": "5"},
+					Consumer: map[string]string{"fetch.min.bytes": "1024"},
+					Dispatcher: &v1alpha1.DispatcherConfig{
+						MaxPollRecords: ptr.Int32(200),
+						MaxPoolSize:    ptr.Int32(300),
+					},
+				},
+			},
+		},
+		expect: configMap("config-kafka-broker-data-plane", map[string]interface{}{
+			"config-kafka-broker-producer.properties":  "acks=all\nlinger.ms=5\n",
+			"config-kafka-broker-consumer.properties":  "max.poll.records=200\nfetch.min.bytes=1024\n",
+			"config-kafka-broker-webclient.properties": "idleTimeout=10000\nmaxPoolSize=300\n",
+		}),
+	}, {
+		name: "Update sink data plane",
+		obj: configMap("config-kafka-sink-data-plane", map[string]interface{}{
+			"config-kafka-sink-producer.properties": "acks=all\n",
+		}),
+		knativeKafka: v1alpha1.KnativeKafkaSpec{
+			Sink: v1alpha1.Sink{
+				DataPlane: &v1alpha1.DataPlaneConfig{
+					Producer: map[string]string{"compression.type": "lz4"},
+				},
+			},
+		},
+		expect: configMap("config-kafka-sink-data-plane", map[string]interface{}{
+			"config-kafka-sink-producer.properties": "acks=all\ncompression.type=lz4\n",
+		}),
+	}, {
+		name: "Set delivery order",
+		obj: configMap("config-kafka-source-data-plane", map[string]interface{}{
+			"config-kafka-source-consumer.properties": "max.poll.records=50\n",
+		}),
+		knativeKafka: v1alpha1.KnativeKafkaSpec{
+			Source: v1alpha1.Source{
+				DataPlane: &v1alpha1.DataPlaneConfig{
+					Dispatcher: &v1alpha1.DispatcherConfig{DeliveryOrder: v1alpha1.DeliveryOrderUnordered},
+				},
+			},
+		},
+		expect: configMap("config-kafka-source-data-plane", map[string]interface{}{
+			"config-kafka-source-consumer.properties": "max.poll.records=50\n",
+			"default.delivery.order":                  "unordered",
+		}),
+	}, {
+		name: "Do not update data plane without configuration",
+		obj: configMap("config-kafka-channel-data-plane", map[string]interface{}{
+			"config-kafka-channel-producer.properties": "acks=all\n",
+		}),
+		knativeKafka: v1alpha1.KnativeKafkaSpec{
+			Broker: v1alpha1.Broker{
+				DataPlane: &v1alpha1.DataPlaneConfig{
+					Producer: map[string]string{"linger.ms": "5"},
+				},
+			},
+		},
+		expect: configMap("config-kafka-channel-data-plane", map[string]interface{}{
+			"config-kafka-channel-producer.properties": "acks=all\n",
+		}),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := configureEventingKafka(test.knativeKafka)(test.obj)
+			if err != nil {
+				t.Fatalf("configureEventingKafka: (%v)", err)
+			}
+
+			if !cmp.Equal(test.expect, test.obj) {
+				t.Fatalf("Resource wasn't what we expected, diff: (-want, +got)\n%s", cmp.Diff(test.expect, test.obj))
+			}
+		})
+	}
+}
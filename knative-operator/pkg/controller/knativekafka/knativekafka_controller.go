@@ -6,13 +6,15 @@ import (
 	"fmt"
 	"os"
 	"regexp"
-	"strconv"
+	"sort"
 	"strings"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	operatorv1beta1 "knative.dev/operator/pkg/apis/operator/v1beta1"
 
 	"github.com/go-logr/logr"
+	configv1 "github.com/openshift/api/config/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
@@ -26,6 +28,7 @@ import (
 	mfc "github.com/manifestival/controller-runtime-client"
 
 	"knative.dev/operator/pkg/apis/operator/base"
+	"knative.dev/pkg/apis"
 
 	mf "github.com/manifestival/manifestival"
 	appsv1 "k8s.io/api/apps/v1"
@@ -66,6 +69,11 @@ var (
 	KafkaHAComponents = []string{"kafka-controller", "kafka-webhook-eventing"}
 
 	dependentConfigMaps = sets.New[string]("config-observability", "config-tracing", "kafka-config-logging", "config-features")
+
+	// proxyWorkloads are the workloads delivering events to sinks, which might be outside of the
+	// cluster and thus called through the cluster proxy. They trust the CA of the proxy through the
+	// trusted CA bundle, whose changes roll them.
+	proxyWorkloads = []string{"kafka-broker-dispatcher", "kafka-channel-dispatcher", "kafka-source-dispatcher"}
 )
 
 type stage func(*mf.Manifest, *serverlessoperatorv1alpha1.KnativeKafka) error
@@ -82,6 +90,17 @@ func Add(mgr manager.Manager) error {
 
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(mgr manager.Manager) (*ReconcileKnativeKafka, error) {
+	r, err := newReconcilerWithClient(mgr.GetClient(), mgr.GetScheme())
+	if err != nil {
+		return nil, err
+	}
+	r.apiReader = mgr.GetAPIReader()
+	return r, nil
+}
+
+// newReconcilerWithClient returns a new reconcile.Reconciler using the given client, loading the
+// Kafka manifests from the paths in the environment.
+func newReconcilerWithClient(c client.Client, s *runtime.Scheme) (*ReconcileKnativeKafka, error) {
 	kafkaChannelManifest, err := mf.ManifestFrom(mf.Path(os.Getenv("KAFKACHANNEL_MANIFEST_PATH")))
 	if err != nil {
 		return nil, fmt.Errorf("failed to load KafkaChannel manifest: %w", err)
@@ -108,13 +127,16 @@ func newReconciler(mgr manager.Manager) (*ReconcileKnativeKafka, error) {
 	}
 
 	reconcileKnativeKafka := ReconcileKnativeKafka{
-		client:                     mgr.GetClient(),
-		scheme:                     mgr.GetScheme(),
+		client:                     c,
+		apiReader:                  c,
+		scheme:                     s,
 		rawKafkaChannelManifest:    kafkaChannelManifest,
 		rawKafkaSourceManifest:     kafkaSourceManifest,
 		rawKafkaControllerManifest: kafkaControllerManifest,
 		rawKafkaBrokerManifest:     kafkaBrokerManifest,
 		rawKafkaSinkManifest:       kafkaSinkManifest,
+		clusterProbe:               saramaClusterProbe{timeout: clusterProbeTimeout},
+		clusterProbes:              newClusterProbeCache(clusterProbeCacheTTL),
 	}
 	return &reconcileKnativeKafka, nil
 }
@@ -159,13 +181,23 @@ func add(mgr manager.Manager, r *ReconcileKnativeKafka) error {
 
 	for _, t := range gvkToEventingResource {
 		err = c.Watch(source.Kind(mgr.GetCache(), t, filteredGlobalResync(context.Background(), mgr.GetLogger(), r, func(object client.Object) bool {
-			return object.GetNamespace() == "knative-eventing" && dependentConfigMaps.Has(object.GetName())
+			return object.GetNamespace() == "knative-eventing" &&
+				(dependentConfigMaps.Has(object.GetName()) || object.GetName() == socommon.TrustedCAConfigMapName)
 		})))
 		if err != nil {
 			return err
 		}
 	}
 
+	// Roll the data plane when the cluster proxy configuration changes.
+	err = c.Watch(source.Kind(mgr.GetCache(), client.Object(&configv1.Proxy{}), filteredGlobalResync(context.Background(), mgr.GetLogger(), r,
+		func(object client.Object) bool {
+			return object.GetName() == socommon.ClusterProxyName
+		})))
+	if err != nil {
+		return err
+	}
+
 	// watch KnativeEventing instances as KnativeKafka instances are dependent on them
 	err = c.Watch(source.Kind(mgr.GetCache(), client.Object(&operatorv1beta1.KnativeEventing{}), filteredGlobalResync(context.Background(), mgr.GetLogger(), r,
 		func(_ client.Object) bool {
@@ -210,13 +242,20 @@ var _ reconcile.Reconciler = &ReconcileKnativeKafka{}
 type ReconcileKnativeKafka struct {
 	// This client, initialized using mgr.Client() above, is a split client
 	// that reads objects from the cache and writes to the apiserver
-	client                     client.Client
+	client client.Client
+	// apiReader reads from the apiserver directly, it is used to check the rollout of the
+	// workloads that were just applied and to read the Kafka auth secrets without caching
+	// all the secrets of the cluster.
+	apiReader                  client.Reader
 	scheme                     *runtime.Scheme
 	rawKafkaChannelManifest    mf.Manifest
 	rawKafkaSourceManifest     mf.Manifest
 	rawKafkaControllerManifest mf.Manifest
 	rawKafkaBrokerManifest     mf.Manifest
 	rawKafkaSinkManifest       mf.Manifest
+	clusterProbe               ClusterProbe
+	// clusterProbes caches the successful probes of the Kafka clusters.
+	clusterProbes *clusterProbeCache
 }
 
 // Reconcile reads that state of the cluster for a KnativeKafka object and makes changes based on the state read
@@ -245,7 +284,13 @@ func (r *ReconcileKnativeKafka) Reconcile(ctx context.Context, request reconcile
 	}
 
 	instance := original.DeepCopy()
+	start := time.Now()
 	reconcileErr := r.reconcileKnativeKafka(ctx, instance)
+	result := "success"
+	if reconcileErr != nil {
+		result = "error"
+	}
+	monitoring.KnativeKafkaReconcileDuration.WithLabelValues(result).Observe(time.Since(start).Seconds())
 
 	if !equality.Semantic.DeepEqual(original.Status, instance.Status) {
 		if err := r.client.Status().Update(context.TODO(), instance); err != nil {
@@ -259,6 +304,11 @@ func (r *ReconcileKnativeKafka) Reconcile(ctx context.Context, request reconcile
 	} else {
 		monitoring.KnativeKafkaUpG.Set(0)
 	}
+
+	// Nothing notifies about a Kafka cluster becoming reachable, so probe it again later.
+	if c := instance.Status.GetCondition(serverlessoperatorv1alpha1.KafkaClusterReachable); reconcileErr == nil && c != nil && c.IsFalse() {
+		return reconcile.Result{RequeueAfter: clusterProbeRequeueAfter}, nil
+	}
 	return reconcile.Result{}, reconcileErr
 }
 
@@ -279,18 +329,44 @@ func (r *ReconcileKnativeKafka) executeInstallStages(ctx context.Context, instan
 		return fmt.Errorf("failed to load and build manifest: %w", err)
 	}
 
-	stages := []stage{
-		r.configure,
-		r.ensureFinalizers,
-		r.transform,
-		removeCreationTimestamp,
-		r.handleTLSResources(ctx),
-		r.apply,
-		r.checkDeployments,
-		r.checkStatefulSets,
+	return executeStages(instance, manifest, r.installStages(ctx, false))
+}
+
+// installStages returns the stages installing the enabled components. When rendering, only the
+// stages transforming the manifest are returned, the ones writing to or probing the cluster are
+// left out and the TLS resources are filtered out without being deleted.
+func (r *ReconcileKnativeKafka) installStages(ctx context.Context, rendering bool) []stage {
+	tlsResources := r.handleTLSResources(ctx)
+	if rendering {
+		tlsResources = r.filterTLSResources(ctx)
 	}
 
-	return executeStages(instance, manifest, stages)
+	unavailable := unavailableWorkloads{}
+	stages := []struct {
+		run       stage
+		transform bool
+	}{
+		{run: r.configure, transform: true},
+		{run: r.ensureFinalizers},
+		{run: r.transform, transform: true},
+		{run: removeCreationTimestamp, transform: true},
+		{run: tlsResources, transform: true},
+		{run: r.apply(ctx)},
+		{run: r.deleteStaleClusterProfiles(ctx)},
+		{run: r.deleteStaleScalers(ctx)},
+		{run: r.checkDeployments(unavailable)},
+		{run: r.checkStatefulSets(unavailable)},
+		{run: r.checkComponents(unavailable)},
+		{run: r.probeKafkaClusters(ctx)},
+	}
+
+	result := make([]stage, 0, len(stages))
+	for _, s := range stages {
+		if s.transform || !rendering {
+			result = append(result, s.run)
+		}
+	}
+	return result
 }
 
 func (r *ReconcileKnativeKafka) executeDeleteStages(instance *serverlessoperatorv1alpha1.KnativeKafka) error {
@@ -347,6 +423,14 @@ func (r *ReconcileKnativeKafka) transform(manifest *mf.Manifest, instance *serve
 			return err
 		}
 	}
+	proxy, err := r.clusterProxy(context.TODO())
+	if err != nil {
+		return err
+	}
+	caBundle, err := r.trustedCABundle(context.TODO(), instance.Namespace)
+	if err != nil {
+		return err
+	}
 	tfs := []mf.Transformer{}
 	tfs = append(append(tfs,
 		injectOwner(instance),
@@ -361,7 +445,9 @@ func (r *ReconcileKnativeKafka) transform(manifest *mf.Manifest, instance *serve
 		socommon.VersionedJobNameTransform(),
 		socommon.InjectCommonEnvironment(),
 		socommon.ApplyCABundlesTransform(),
+		socommon.InjectProxyTransform(socommon.ProxyEnvironment(proxy), caBundle, proxyWorkloads...),
 		operatorcommon.OverridesTransform(instance.Spec.Workloads, logging.FromContext(context.TODO())),
+		unsetScaledReplicas(scaledWorkloads(*manifest)),
 		socommon.ConfigMapVolumeChecksumTransform(context.Background(), r.client, dependentConfigMaps),
 		socommon.JobsRemoveTTLSecondsAfterFinished(),
 		injectNamespacedBrokerMonitoring(r.client)), socommon.DeprecatedAPIsTranformersFromConfig()...)
@@ -375,6 +461,33 @@ func (r *ReconcileKnativeKafka) transform(manifest *mf.Manifest, instance *serve
 	return nil
 }
 
+// trustedCABundle returns the trusted CA bundle mounted into the data plane in the given namespace,
+// nil if there is none.
+func (r *ReconcileKnativeKafka) trustedCABundle(ctx context.Context, namespace string) (*corev1.ConfigMap, error) {
+	cm := &corev1.ConfigMap{}
+	err := r.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: socommon.TrustedCAConfigMapName}, cm)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the trusted CA bundle: %w", err)
+	}
+	return cm, nil
+}
+
+// clusterProxy returns the cluster-wide Proxy configuration, nil if there is none.
+func (r *ReconcileKnativeKafka) clusterProxy(ctx context.Context) (*configv1.Proxy, error) {
+	proxy := &configv1.Proxy{}
+	err := r.client.Get(ctx, types.NamespacedName{Name: socommon.ClusterProxyName}, proxy)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the cluster proxy: %w", err)
+	}
+	return proxy, nil
+}
+
 func injectOwner(owner mf.Owner) mf.Transformer {
 	return func(u *unstructured.Unstructured) error {
 		if u.GetNamespace() != "" {
@@ -385,76 +498,175 @@ func injectOwner(owner mf.Owner) mf.Transformer {
 }
 
 // Install Knative Kafka components
-func (r *ReconcileKnativeKafka) apply(manifest *mf.Manifest, instance *serverlessoperatorv1alpha1.KnativeKafka) error {
-	log.Info("Installing manifest")
-	// The Operator needs a higher level of permissions if it 'bind's non-existent roles.
-	// To avoid this, we strictly order the manifest application as (Cluster)Roles, then
-	// (Cluster)RoleBindings, then the rest of the manifest.
-	if err := manifest.Filter(role).Apply(); err != nil {
-		instance.Status.MarkInstallFailed(err.Error())
-		return fmt.Errorf("failed to apply (cluster)roles in manifest: %w", err)
-	}
-	if err := manifest.Filter(rolebinding).Apply(); err != nil {
-		instance.Status.MarkInstallFailed(err.Error())
-		return fmt.Errorf("failed to apply (cluster)rolebindings in manifest: %w", err)
-	}
-	if err := manifest.Filter(mf.Not(roleOrRoleBinding)).Apply(); err != nil {
-		instance.Status.MarkInstallFailed(err.Error())
-		return fmt.Errorf("failed to apply non rbac manifest: %w", err)
-	}
-	instance.Status.MarkInstallSucceeded()
-	instance.Status.Version = os.Getenv("KNATIVE_EVENTING_KAFKA_BROKER_VERSION")
-	return nil
-}
-
-func (r *ReconcileKnativeKafka) checkDeployments(manifest *mf.Manifest, instance *serverlessoperatorv1alpha1.KnativeKafka) error {
-	log.Info("Checking deployments")
-	for _, u := range manifest.Filter(mf.ByKind("Deployment")).Resources() {
-		u := u // To avoid memory aliasing
-		resource, err := manifest.Client.Get(&u)
+func (r *ReconcileKnativeKafka) apply(ctx context.Context) stage {
+	return func(manifest *mf.Manifest, instance *serverlessoperatorv1alpha1.KnativeKafka) error {
+		hashes, err := manifestHashes(*manifest)
 		if err != nil {
-			instance.Status.MarkDeploymentsNotReady()
-			if apierrors.IsNotFound(err) {
-				return nil
-			}
 			return err
 		}
-		deployment := &appsv1.Deployment{}
-		if err := scheme.Scheme.Convert(resource, deployment, nil); err != nil {
+		upToDate, err := r.upToDateComponents(ctx, *manifest, instance, hashes)
+		if err != nil {
 			return err
 		}
-		if !isDeploymentAvailable(deployment) {
-			instance.Status.MarkDeploymentsNotReady()
+		for component := range upToDate {
+			monitoring.KnativeKafkaManifestApplies.WithLabelValues(component, "skipped").Inc()
+		}
+		if upToDate.Len() == len(hashes) {
+			log.Info("Manifest unchanged, skipping apply")
 			return nil
 		}
+		changed := manifest.Filter(func(u *unstructured.Unstructured) bool {
+			return !upToDate.Has(componentOf(u))
+		})
+		countApplies := func(result string) {
+			for component := range hashes {
+				if !upToDate.Has(component) {
+					monitoring.KnativeKafkaManifestApplies.WithLabelValues(component, result).Inc()
+				}
+			}
+		}
+
+		log.Info("Installing manifest")
+		// The Operator needs a higher level of permissions if it 'bind's non-existent roles.
+		// To avoid this, we strictly order the manifest application as (Cluster)Roles, then
+		// (Cluster)RoleBindings, then the rest of the manifest, and finally the workloads
+		// of the rollout phases.
+		if err := changed.Filter(role).Apply(); err != nil {
+			countApplies("failed")
+			instance.Status.MarkInstallFailed(err.Error())
+			return fmt.Errorf("failed to apply (cluster)roles in manifest: %w", err)
+		}
+		if err := changed.Filter(rolebinding).Apply(); err != nil {
+			countApplies("failed")
+			instance.Status.MarkInstallFailed(err.Error())
+			return fmt.Errorf("failed to apply (cluster)rolebindings in manifest: %w", err)
+		}
+		if err := changed.Filter(mf.Not(roleOrRoleBinding), mf.Not(rolloutWorkload)).Apply(); err != nil {
+			countApplies("failed")
+			instance.Status.MarkInstallFailed(err.Error())
+			return fmt.Errorf("failed to apply non rbac manifest: %w", err)
+		}
+		done, err := r.rollout(ctx, &changed, instance)
+		if err != nil {
+			countApplies("failed")
+			instance.Status.MarkInstallFailed(err.Error())
+			return err
+		}
+		countApplies("applied")
+		if !done {
+			// The next phases are applied once the workloads become available, which
+			// triggers a new reconciliation.
+			instance.Status.MarkInstallRollingOut(instance.Status.Rollout.Phase, instance.Status.Rollout.Waiting)
+			return nil
+		}
+		instance.Status.MarkInstallSucceeded()
+		instance.Status.Version = os.Getenv("KNATIVE_EVENTING_KAFKA_BROKER_VERSION")
+		instance.Status.ManifestHashes = hashes
+		return nil
 	}
-	instance.Status.MarkDeploymentsAvailable()
-	return nil
 }
 
-func (r *ReconcileKnativeKafka) checkStatefulSets(manifest *mf.Manifest, instance *serverlessoperatorv1alpha1.KnativeKafka) error {
-	log.Info("Checking statefulsets")
-	for _, u := range manifest.Filter(mf.ByKind("StatefulSet")).Resources() {
-		u := u // To avoid memory aliasing
-		resource, err := manifest.Client.Get(&u)
-		if err != nil {
-			instance.Status.MarkStatefulSetNotReady()
-			if apierrors.IsNotFound(err) {
-				return nil
+// unavailableWorkloads collects the names of the workloads that are not available yet,
+// keyed by the condition of the component they belong to.
+type unavailableWorkloads map[apis.ConditionType][]string
+
+func (r *ReconcileKnativeKafka) checkDeployments(unavailable unavailableWorkloads) stage {
+	return func(manifest *mf.Manifest, instance *serverlessoperatorv1alpha1.KnativeKafka) error {
+		log.Info("Checking deployments")
+		allAvailable := true
+		for _, u := range manifest.Filter(mf.ByKind("Deployment")).Resources() {
+			u := u // To avoid memory aliasing
+			resource, err := manifest.Client.Get(&u)
+			if err != nil {
+				if !apierrors.IsNotFound(err) {
+					instance.Status.MarkDeploymentsNotReady()
+					return err
+				}
+				allAvailable = false
+				r.recordUnavailable(unavailable, instance.Spec, &u)
+				continue
+			}
+			deployment := &appsv1.Deployment{}
+			if err := scheme.Scheme.Convert(resource, deployment, nil); err != nil {
+				return err
+			}
+			if !isDeploymentAvailable(deployment) {
+				allAvailable = false
+				r.recordUnavailable(unavailable, instance.Spec, &u)
 			}
-			return err
 		}
-		ss := &appsv1.StatefulSet{}
-		if err := scheme.Scheme.Convert(resource, ss, nil); err != nil {
-			return err
+		if !allAvailable {
+			instance.Status.MarkDeploymentsNotReady()
+			return nil
 		}
-		if !isStatefulSetAvailable(ss) {
+		instance.Status.MarkDeploymentsAvailable()
+		return nil
+	}
+}
+
+func (r *ReconcileKnativeKafka) checkStatefulSets(unavailable unavailableWorkloads) stage {
+	return func(manifest *mf.Manifest, instance *serverlessoperatorv1alpha1.KnativeKafka) error {
+		log.Info("Checking statefulsets")
+		allAvailable := true
+		for _, u := range manifest.Filter(mf.ByKind("StatefulSet")).Resources() {
+			u := u // To avoid memory aliasing
+			resource, err := manifest.Client.Get(&u)
+			if err != nil {
+				if !apierrors.IsNotFound(err) {
+					instance.Status.MarkStatefulSetNotReady()
+					return err
+				}
+				allAvailable = false
+				r.recordUnavailable(unavailable, instance.Spec, &u)
+				continue
+			}
+			ss := &appsv1.StatefulSet{}
+			if err := scheme.Scheme.Convert(resource, ss, nil); err != nil {
+				return err
+			}
+			if !isStatefulSetAvailable(ss) {
+				allAvailable = false
+				r.recordUnavailable(unavailable, instance.Spec, &u)
+			}
+		}
+		if !allAvailable {
 			instance.Status.MarkStatefulSetNotReady()
 			return nil
 		}
+		instance.Status.MarkStatefulSetsAvailable()
+		return nil
+	}
+}
+
+// checkComponents reports the availability of each component based on the workloads
+// collected by checkDeployments and checkStatefulSets.
+func (r *ReconcileKnativeKafka) checkComponents(unavailable unavailableWorkloads) stage {
+	return func(_ *mf.Manifest, instance *serverlessoperatorv1alpha1.KnativeKafka) error {
+		for _, c := range r.components(instance.Spec) {
+			switch {
+			case !c.enabled:
+				instance.Status.MarkComponentDisabled(c.condition)
+			case len(unavailable[c.condition]) > 0:
+				workloads := unavailable[c.condition]
+				sort.Strings(workloads)
+				instance.Status.MarkComponentNotReady(c.condition, workloads)
+			default:
+				instance.Status.MarkComponentReady(c.condition)
+			}
+		}
+		return nil
+	}
+}
+
+// recordUnavailable adds the given workload to the unavailable workloads of the component
+// whose manifest contains it.
+func (r *ReconcileKnativeKafka) recordUnavailable(unavailable unavailableWorkloads, spec serverlessoperatorv1alpha1.KnativeKafkaSpec, u *unstructured.Unstructured) {
+	for _, c := range r.components(spec) {
+		if len(c.manifest.Filter(mf.ByKind(u.GetKind()), mf.ByName(u.GetName())).Resources()) > 0 {
+			unavailable[c.condition] = append(unavailable[c.condition], u.GetName())
+			return
+		}
 	}
-	instance.Status.MarkStatefulSetsAvailable()
-	return nil
 }
 
 // Delete Knative Kafka resources
@@ -572,56 +784,88 @@ const (
 	manifestBuildAll
 )
 
+// kafkaComponent groups the resources that are installed for a KnativeKafka component.
+type kafkaComponent struct {
+	// name identifies the component in the manifest hashes.
+	name string
+	// condition is the status condition reporting the availability of the component workloads.
+	condition apis.ConditionType
+	enabled   bool
+	manifest  mf.Manifest
+	rbacProxy []monitoring.Component
+	// scaling configures the autoscalers of the component receivers, if any.
+	scaling *serverlessoperatorv1alpha1.Scaling
+}
+
+// components returns the KnativeKafka components in the order their manifests are built.
+func (r *ReconcileKnativeKafka) components(spec serverlessoperatorv1alpha1.KnativeKafkaSpec) []kafkaComponent {
+	return []kafkaComponent{{
+		name:      "channel",
+		condition: serverlessoperatorv1alpha1.ChannelReady,
+		enabled:   spec.Channel.Enabled,
+		manifest:  r.rawKafkaChannelManifest,
+		rbacProxy: []monitoring.Component{monitoring.KafkaChannelReceiver, monitoring.KafkaChannelDispatcher},
+		scaling:   spec.Channel.Scaling,
+	}, {
+		// Kafka Control Plane
+		name:      "controlPlane",
+		condition: serverlessoperatorv1alpha1.ControlPlaneReady,
+		enabled:   enableControlPlaneManifest(spec),
+		manifest:  r.rawKafkaControllerManifest,
+		rbacProxy: []monitoring.Component{monitoring.KafkaController, monitoring.KafkaWebhook},
+	}, {
+		// Kafka Source Data Plane
+		name:      "source",
+		condition: serverlessoperatorv1alpha1.SourceReady,
+		enabled:   spec.Source.Enabled,
+		manifest:  r.rawKafkaSourceManifest,
+		rbacProxy: []monitoring.Component{monitoring.KafkaSourceDispatcher},
+	}, {
+		// Kafka Broker Data Plane
+		name:      "broker",
+		condition: serverlessoperatorv1alpha1.BrokerReady,
+		enabled:   spec.Broker.Enabled,
+		manifest:  r.rawKafkaBrokerManifest,
+		rbacProxy: []monitoring.Component{monitoring.KafkaBrokerReceiver, monitoring.KafkaBrokerDispatcher},
+		scaling:   spec.Broker.Scaling,
+	}, {
+		// Kafka Sink Data Plane
+		name:      "sink",
+		condition: serverlessoperatorv1alpha1.SinkReady,
+		enabled:   spec.Sink.Enabled,
+		manifest:  r.rawKafkaSinkManifest,
+		rbacProxy: []monitoring.Component{monitoring.KafkaSinkReceiver},
+		scaling:   spec.Sink.Scaling,
+	}}
+}
+
+func (b manifestBuild) includes(c kafkaComponent) bool {
+	return b == manifestBuildAll || (b == manifestBuildEnabledOnly && c.enabled) || (b == manifestBuildDisabledOnly && !c.enabled)
+}
+
 func (r *ReconcileKnativeKafka) buildManifest(instance *serverlessoperatorv1alpha1.KnativeKafka, build manifestBuild) (*mf.Manifest, error) {
 	var resources []unstructured.Unstructured
 
-	if build == manifestBuildAll || (build == manifestBuildEnabledOnly && instance.Spec.Channel.Enabled) || (build == manifestBuildDisabledOnly && !instance.Spec.Channel.Enabled) {
-		rbacProxy, err := monitoring.AddRBACProxyToManifest(instance, monitoring.KafkaChannelReceiver, monitoring.KafkaChannelDispatcher)
-		if err != nil {
-			return nil, err
+	for _, c := range r.components(instance.Spec) {
+		if !build.includes(c) {
+			continue
 		}
-		resources = append(resources, rbacProxy.Resources()...)
-		resources = append(resources, r.rawKafkaChannelManifest.Resources()...)
-	}
-
-	// Kafka Control Plane
-	if build == manifestBuildAll || (build == manifestBuildEnabledOnly && enableControlPlaneManifest(instance.Spec)) || (build == manifestBuildDisabledOnly && !enableControlPlaneManifest(instance.Spec)) {
-		rbacProxy, err := monitoring.AddRBACProxyToManifest(instance, monitoring.KafkaController, monitoring.KafkaWebhook)
+		rbacProxy, err := monitoring.AddRBACProxyToManifest(instance, c.rbacProxy...)
 		if err != nil {
 			return nil, err
 		}
-		resources = append(resources, rbacProxy.Resources()...)
-		resources = append(resources, r.rawKafkaControllerManifest.Resources()...)
-	}
-
-	// Kafka Source Data Plane
-	if build == manifestBuildAll || (build == manifestBuildEnabledOnly && instance.Spec.Source.Enabled) || (build == manifestBuildDisabledOnly && !instance.Spec.Source.Enabled) {
-		sourceRBACProxy, err := monitoring.AddRBACProxyToManifest(instance, monitoring.KafkaSourceDispatcher)
-		if err != nil {
-			return nil, err
+		componentResources := append(rbacProxy.Resources(), c.manifest.Resources()...)
+		if c.condition == serverlessoperatorv1alpha1.ControlPlaneReady {
+			componentResources = append(componentResources, clusterProfileResources(c.manifest, instance.Spec.Clusters)...)
 		}
-		resources = append(resources, sourceRBACProxy.Resources()...)
-		resources = append(resources, r.rawKafkaSourceManifest.Resources()...)
-	}
-
-	// Kafka Broker Data Plane
-	if build == manifestBuildAll || (build == manifestBuildEnabledOnly && instance.Spec.Broker.Enabled) || (build == manifestBuildDisabledOnly && !instance.Spec.Broker.Enabled) {
-		rbacProxy, err := monitoring.AddRBACProxyToManifest(instance, monitoring.KafkaBrokerReceiver, monitoring.KafkaBrokerDispatcher)
-		if err != nil {
-			return nil, err
-		}
-		resources = append(resources, rbacProxy.Resources()...)
-		resources = append(resources, r.rawKafkaBrokerManifest.Resources()...)
-	}
-
-	// Kafka Sink Data Plan
-	if build == manifestBuildAll || (build == manifestBuildEnabledOnly && instance.Spec.Sink.Enabled) || (build == manifestBuildDisabledOnly && !instance.Spec.Sink.Enabled) {
-		rbacProxy, err := monitoring.AddRBACProxyToManifest(instance, monitoring.KafkaSinkReceiver)
-		if err != nil {
-			return nil, err
+		if c.enabled {
+			scalers, err := scalingResources(c.manifest, c.scaling)
+			if err != nil {
+				return nil, err
+			}
+			componentResources = append(componentResources, scalers...)
 		}
-		resources = append(resources, rbacProxy.Resources()...)
-		resources = append(resources, r.rawKafkaSinkManifest.Resources()...)
+		resources = append(resources, annotateComponent(componentResources, c.name)...)
 	}
 
 	manifest, err := mf.ManifestFrom(
@@ -680,6 +924,16 @@ func configureEventingKafka(spec serverlessoperatorv1alpha1.KnativeKafkaSpec) mf
 				deployment.Spec.Template.Spec.Containers[0].Args = []string{"--disable-controllers=" + disabledKafkaControllers.StringValues()}
 			}
 
+			// point the sinks to the broker config of their Kafka cluster profile
+			if spec.Sink.Cluster != "" {
+				container := &deployment.Spec.Template.Spec.Containers[0]
+				for i := range container.Env {
+					if container.Env[i].Name == sinkGeneralConfigMapEnv {
+						container.Env[i].Value = clusterConfigMapName(spec.Sink.Cluster)
+					}
+				}
+			}
+
 			return scheme.Scheme.Convert(deployment, u, nil)
 		}
 
@@ -693,33 +947,56 @@ func configureEventingKafka(spec serverlessoperatorv1alpha1.KnativeKafkaSpec) mf
 		}
 
 		// configure the broker itself
-		if u.GetKind() == "ConfigMap" && u.GetName() == "kafka-broker-config" {
+		if u.GetKind() == "ConfigMap" && u.GetName() == brokerConfigMapName {
 			log.Info("Found ConfigMap kafka-broker-config, updating it with values from spec")
 
 			kafkaBrokerDefaultConfig := spec.Broker.DefaultConfig
-			if err := unstructured.SetNestedField(u.Object, kafkaBrokerDefaultConfig.BootstrapServers, "data", "bootstrap.servers"); err != nil {
+			if err := configureBrokerConfig(u, kafkaBrokerDefaultConfig, spec.Cluster(kafkaBrokerDefaultConfig.Cluster)); err != nil {
 				return err
 			}
+		}
 
-			if err := unstructured.SetNestedField(u.Object, strconv.FormatInt(int64(kafkaBrokerDefaultConfig.NumPartitions), 10), "data", "default.topic.partitions"); err != nil {
-				return err
-			}
+		// configure the broker configs of the Kafka cluster profiles
+		if cluster, ok := u.GetLabels()[clusterLabelKey]; ok && u.GetKind() == "ConfigMap" {
+			log.Info("Found Kafka cluster profile ConfigMap, updating it with values from spec", "name", u.GetName())
 
-			if err := unstructured.SetNestedField(u.Object, strconv.FormatInt(int64(kafkaBrokerDefaultConfig.ReplicationFactor), 10), "data", "default.topic.replication.factor"); err != nil {
+			if err := configureBrokerConfig(u, spec.Broker.DefaultConfig, spec.Cluster(cluster)); err != nil {
 				return err
 			}
-			if kafkaBrokerDefaultConfig.AuthSecretName != "" {
-				if err := unstructured.SetNestedField(u.Object, kafkaBrokerDefaultConfig.AuthSecretName, "data", "auth.secret.ref.name"); err != nil {
+		}
+
+		// tune the data planes
+		if u.GetKind() == "ConfigMap" {
+			if cfg, prefix := dataPlaneConfigFor(spec, u.GetName()); cfg != nil {
+				log.Info("Found data plane ConfigMap, updating it with values from spec", "name", u.GetName())
+				if err := configureDataPlane(u, prefix, cfg); err != nil {
 					return err
 				}
 			}
 		}
 
+		// scale the dispatchers
+		if u.GetKind() == "ConfigMap" && u.GetName() == autoscalerConfigMapName && spec.DispatcherScaling != nil {
+			log.Info("Found ConfigMap config-kafka-autoscaler, updating it with values from spec")
+			if err := configureDispatcherScaling(u, spec.DispatcherScaling); err != nil {
+				return err
+			}
+		}
+
 		// configure the channel itself
-		if u.GetKind() == "ConfigMap" && u.GetName() == "kafka-channel-config" {
+		if u.GetKind() == "ConfigMap" && u.GetName() == channelConfigMapName {
 			log.Info("Found ConfigMap kafka-channel-config, updating it with values from spec")
 
 			kafkaChannelConfig := spec.Channel
+			if cluster := spec.Cluster(kafkaChannelConfig.Cluster); cluster != nil {
+				// The secrets of the profiles live next to the KnativeKafka, as the ConfigMap does.
+				kafkaChannelConfig.BootstrapServers = cluster.BootstrapServers
+				kafkaChannelConfig.AuthSecretName = cluster.AuthSecretName
+				kafkaChannelConfig.AuthSecretNamespace = ""
+				if cluster.AuthSecretName != "" {
+					kafkaChannelConfig.AuthSecretNamespace = u.GetNamespace()
+				}
+			}
 			if err := unstructured.SetNestedField(u.Object, kafkaChannelConfig.BootstrapServers, "data", "bootstrap.servers"); err != nil {
 				return err
 			}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	mf "github.com/manifestival/manifestival"
+	configv1 "github.com/openshift/api/config/v1"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"github.com/stretchr/testify/assert"
 	appsv1 "k8s.io/api/apps/v1"
@@ -26,6 +27,7 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 	"knative.dev/operator/pkg/apis/operator/base"
 	operatorv1beta1 "knative.dev/operator/pkg/apis/operator/v1beta1"
+	pkgapis "knative.dev/pkg/apis"
 	"knative.dev/pkg/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -35,6 +37,7 @@ import (
 	"github.com/openshift-knative/serverless-operator/knative-operator/pkg/apis"
 	"github.com/openshift-knative/serverless-operator/knative-operator/pkg/apis/operator/v1alpha1"
 	"github.com/openshift-knative/serverless-operator/knative-operator/pkg/monitoring"
+	socommon "github.com/openshift-knative/serverless-operator/openshift-knative-operator/pkg/common"
 	okomon "github.com/openshift-knative/serverless-operator/openshift-knative-operator/pkg/monitoring"
 )
 
@@ -132,15 +135,15 @@ func TestKnativeKafkaReconcile(t *testing.T) {
 
 			r := &ReconcileKnativeKafka{
 				client:                  cl,
+				apiReader:               cl,
 				scheme:                  scheme.Scheme,
 				rawKafkaChannelManifest: kafkaChannelManifest,
 				rawKafkaSourceManifest:  kafkaSourceManifest,
+				clusterProbe:            &fakeClusterProbe{},
 			}
 
-			// Reconcile to initialize
-			if _, err := r.Reconcile(context.Background(), defaultRequest); err != nil {
-				t.Fatalf("reconcile: (%v)", err)
-			}
+			// Reconcile to initialize, the workloads are applied phase by phase
+			reconcileRolledOut(t, r, cl)
 
 			// check if things that should exist is created
 			for _, d := range test.exists {
@@ -218,9 +221,7 @@ func TestKnativeKafkaReconcile(t *testing.T) {
 			}
 
 			// Reconcile again
-			if _, err := r.Reconcile(context.Background(), defaultRequest); err != nil {
-				t.Fatalf("reconcile: (%v)", err)
-			}
+			reconcileRolledOut(t, r, cl)
 
 			// check if things that should exist is created
 			for _, d := range test.exists {
@@ -233,6 +234,90 @@ func TestKnativeKafkaReconcile(t *testing.T) {
 	}
 }
 
+func TestKnativeKafkaComponentConditions(t *testing.T) {
+	t.Setenv("TEST_DEPRECATED_APIS_K8S_VERSION", "v1.24.0")
+
+	instance := makeCr(withChannelEnabled, withSourceEnabled)
+	cl := fake.NewClientBuilder().
+		WithObjects(instance, &operatorv1beta1.KnativeEventing{}).
+		WithObjects(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Namespace: defaultRequest.Namespace,
+			Name:      "config-features",
+		}}).
+		WithStatusSubresource(&v1alpha1.KnativeKafka{}).
+		Build()
+
+	kafkaChannelManifest, err := mf.ManifestFrom(mf.Path("testdata/channel/eventing-kafka-channel.yaml"))
+	if err != nil {
+		t.Fatalf("failed to load KafkaChannel manifest: %v", err)
+	}
+	kafkaSourceManifest, err := mf.ManifestFrom(mf.Path("testdata/source/eventing-kafka-source.yaml"))
+	if err != nil {
+		t.Fatalf("failed to load KafkaSource manifest: %v", err)
+	}
+
+	r := &ReconcileKnativeKafka{
+		client:                  cl,
+		apiReader:               cl,
+		scheme:                  scheme.Scheme,
+		rawKafkaChannelManifest: kafkaChannelManifest,
+		rawKafkaSourceManifest:  kafkaSourceManifest,
+		clusterProbe:            &fakeClusterProbe{},
+	}
+
+	// The first reconciles create the workloads, none of them are available yet.
+	if _, err := r.Reconcile(context.Background(), defaultRequest); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	markRolledOut(t, cl, "kafka-channel-receiver")
+	if _, err := r.Reconcile(context.Background(), defaultRequest); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+
+	// Make the source dispatcher available.
+	ss := &appsv1.StatefulSet{}
+	if err := cl.Get(context.Background(), types.NamespacedName{Namespace: "knative-eventing", Name: "kafka-source-dispatcher"}, ss); err != nil {
+		t.Fatalf("get: (%v)", err)
+	}
+	ss.Status.ReadyReplicas = 1
+	if err := cl.Status().Update(context.Background(), ss); err != nil {
+		t.Fatalf("update: (%v)", err)
+	}
+
+	kk := &v1alpha1.KnativeKafka{}
+	if err := cl.Get(context.Background(), defaultRequest.NamespacedName, kk); err != nil {
+		t.Fatalf("get: (%v)", err)
+	}
+	manifest, err := r.buildManifest(kk, manifestBuildEnabledOnly)
+	if err != nil {
+		t.Fatalf("buildManifest: (%v)", err)
+	}
+	unavailable := unavailableWorkloads{}
+	if err := executeStages(kk, manifest, []stage{
+		r.checkDeployments(unavailable),
+		r.checkStatefulSets(unavailable),
+		r.checkComponents(unavailable),
+	}); err != nil {
+		t.Fatalf("check stages: (%v)", err)
+	}
+
+	if c := kk.Status.GetCondition(v1alpha1.SourceReady); c == nil || !c.IsTrue() {
+		t.Errorf("SourceReady = %v, want true", c)
+	}
+	c := kk.Status.GetCondition(v1alpha1.ChannelReady)
+	if c == nil || !c.IsFalse() {
+		t.Fatalf("ChannelReady = %v, want false", c)
+	}
+	if want := "Waiting on workloads: kafka-channel-dispatcher, kafka-channel-receiver"; c.Message != want {
+		t.Errorf("ChannelReady message = %q, want %q", c.Message, want)
+	}
+	for _, cond := range []pkgapis.ConditionType{v1alpha1.BrokerReady, v1alpha1.SinkReady} {
+		if c := kk.Status.GetCondition(cond); c != nil {
+			t.Errorf("%s = %v, want nil for a disabled component", cond, c)
+		}
+	}
+}
+
 func getPodTemplateSpec(cl client.WithWatch, d types.NamespacedName) (client.Object, *corev1.PodTemplateSpec, error) {
 	deployment := &appsv1.Deployment{}
 	err := cl.Get(context.TODO(), d, deployment)
@@ -292,6 +377,46 @@ func TestBrokerCfg(t *testing.T) {
 				},
 			},
 		},
+	}, {
+		name: "Update kafka-broker-config with topic configuration",
+		obj: &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata": map[string]interface{}{
+					"name": "kafka-broker-config",
+				},
+			},
+		},
+		knativeKafka: v1alpha1.KnativeKafkaSpec{
+			Broker: v1alpha1.Broker{
+				DefaultConfig: v1alpha1.BrokerDefaultConfig{
+					NumPartitions:     12,
+					ReplicationFactor: 3,
+					BootstrapServers:  "example.com:1234",
+					Topic: &v1alpha1.TopicConfig{
+						RetentionMillis:   ptr.Int64(604800000),
+						MinInSyncReplicas: ptr.Int32(2),
+					},
+				},
+			},
+		},
+		expect: &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata": map[string]interface{}{
+					"name": "kafka-broker-config",
+				},
+				"data": map[string]interface{}{
+					"bootstrap.servers":                        "example.com:1234",
+					"default.topic.partitions":                 "12",
+					"default.topic.replication.factor":         "3",
+					"default.topic.config.retention.ms":        "604800000",
+					"default.topic.config.min.insync.replicas": "2",
+				},
+			},
+		},
 	}, {
 		name: "Update kafka-broker-config with bootstrap and topic settings",
 		obj: &unstructured.Unstructured{
@@ -649,6 +774,56 @@ func TestDisabledControllers(t *testing.T) {
 	}
 }
 
+func TestProxyCABundle(t *testing.T) {
+	t.Setenv("TEST_DEPRECATED_APIS_K8S_VERSION", "v1.24.0")
+
+	instance := makeCr(withChannelEnabled)
+	bundle := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: instance.Namespace, Name: socommon.TrustedCAConfigMapName},
+		Data:       map[string]string{socommon.TrustedCAKey: "bundle"},
+	}
+	cl := fake.NewClientBuilder().WithObjects(instance, &operatorv1beta1.KnativeEventing{}, bundle, &configv1.Proxy{
+		ObjectMeta: metav1.ObjectMeta{Name: socommon.ClusterProxyName},
+		Status:     configv1.ProxyStatus{HTTPProxy: "http://proxy:3128"},
+	}).Build()
+	r := &ReconcileKnativeKafka{client: cl, scheme: scheme.Scheme}
+
+	checksum := func() string {
+		d := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "kafka-channel-dispatcher", Namespace: instance.Namespace},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "kafka-channel-dispatcher"}}},
+				},
+			},
+		}
+		u := &unstructured.Unstructured{}
+		if err := scheme.Scheme.Convert(d, u, nil); err != nil {
+			t.Fatalf("Could not create unstructured Deployment: %v", err)
+		}
+		manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{*u}))
+		if err != nil {
+			t.Fatalf("failed to create manifest: %v", err)
+		}
+		if err := r.transform(&manifest, instance); err != nil {
+			t.Fatalf("transform: (%v)", err)
+		}
+		return extractDeployment(t, &manifest.Resources()[0]).Spec.Template.Annotations[socommon.ProxyChecksumAnnotation]
+	}
+
+	before := checksum()
+	if before == "" {
+		t.Fatal("Proxy checksum not injected")
+	}
+	bundle.Data[socommon.TrustedCAKey] = "rotated bundle"
+	if err := cl.Update(context.Background(), bundle); err != nil {
+		t.Fatalf("update: (%v)", err)
+	}
+	if after := checksum(); after == before {
+		t.Errorf("Proxy checksum = %q, want it changed with the contents of the trusted CA bundle", after)
+	}
+}
+
 func extractDeployment(t *testing.T, resource *unstructured.Unstructured) *appsv1.Deployment {
 	var deployment = &appsv1.Deployment{}
 	if err := scheme.Scheme.Convert(resource, deployment, nil); err != nil {
@@ -970,6 +1145,10 @@ func (m *MockManager) GetScheme() *runtime.Scheme {
 	return nil
 }
 
+func (m *MockManager) GetAPIReader() client.Reader {
+	return nil
+}
+
 func TestIsNoMatchError(t *testing.T) {
 
 	err := fmt.Errorf("failed to %w", &meta.NoKindMatchError{})
@@ -0,0 +1,131 @@
+package knativekafka
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+
+	mf "github.com/manifestival/manifestival"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"knative.dev/operator/pkg/apis/operator/base"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	serverlessoperatorv1alpha1 "github.com/openshift-knative/serverless-operator/knative-operator/pkg/apis/operator/v1alpha1"
+)
+
+// componentAnnotationKey annotates the resources with the name of the component they belong to.
+const componentAnnotationKey = "operator.serverless.openshift.io/kafka-component"
+
+// annotateComponent annotates the given resources with the name of their component.
+func annotateComponent(resources []unstructured.Unstructured, component string) []unstructured.Unstructured {
+	for i := range resources {
+		annotations := resources[i].GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[componentAnnotationKey] = component
+		resources[i].SetAnnotations(annotations)
+	}
+	return resources
+}
+
+func componentOf(u *unstructured.Unstructured) string {
+	return u.GetAnnotations()[componentAnnotationKey]
+}
+
+// manifestHashes returns the content hash of the resources of each component of the given
+// manifest, which is expected to be fully transformed.
+func manifestHashes(manifest mf.Manifest) (map[string]string, error) {
+	hashes := map[string]hash.Hash{}
+	for _, u := range manifest.Resources() {
+		h, ok := hashes[componentOf(&u)]
+		if !ok {
+			h = sha256.New()
+			hashes[componentOf(&u)] = h
+		}
+		// Maps are marshalled with sorted keys, the encoding is stable.
+		b, err := json.Marshal(u.Object)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash %s %s: %w", u.GetKind(), u.GetName(), err)
+		}
+		h.Write(b)
+	}
+
+	sums := make(map[string]string, len(hashes))
+	for component, h := range hashes {
+		sums[component] = hex.EncodeToString(h.Sum(nil))
+	}
+	return sums, nil
+}
+
+// upToDateComponents returns the components whose manifest doesn't need to be applied: the
+// previous install succeeded with the same manifest and none of its resources were deleted or
+// modified out of band since. Fields added out of band to the resources are kept, as they are
+// by an apply.
+func (r *ReconcileKnativeKafka) upToDateComponents(ctx context.Context, manifest mf.Manifest, instance *serverlessoperatorv1alpha1.KnativeKafka, hashes map[string]string) (sets.Set[string], error) {
+	upToDate := sets.New[string]()
+	if c := instance.Status.GetCondition(base.InstallSucceeded); c == nil || !c.IsTrue() {
+		return upToDate, nil
+	}
+	for component, hash := range hashes {
+		if instance.Status.ManifestHashes[component] == hash {
+			upToDate.Insert(component)
+		}
+	}
+
+	for _, u := range manifest.Resources() {
+		if !upToDate.Has(componentOf(&u)) {
+			continue
+		}
+		inSync, err := r.inSync(ctx, &u)
+		if err != nil {
+			return nil, err
+		}
+		if !inSync {
+			log.Info("Resource is missing or modified, applying its component", "component", componentOf(&u), "kind", u.GetKind(), "name", u.GetName())
+			upToDate.Delete(componentOf(&u))
+		}
+	}
+	return upToDate, nil
+}
+
+// inSync returns whether the given resource exists and still has the fields of the manifest.
+// The resource is read through the cached client, resources of kinds unknown to the scheme
+// are assumed to be in sync.
+func (r *ReconcileKnativeKafka) inSync(ctx context.Context, u *unstructured.Unstructured) (bool, error) {
+	obj, err := r.scheme.New(u.GroupVersionKind())
+	if err != nil {
+		return true, nil
+	}
+	o, ok := obj.(client.Object)
+	if !ok {
+		return true, nil
+	}
+	if err := r.client.Get(ctx, types.NamespacedName{Namespace: u.GetNamespace(), Name: u.GetName()}, o); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		if isNoMatchError(err) {
+			return true, nil
+		}
+		return false, fmt.Errorf("failed to get %s %s: %w", u.GetKind(), u.GetName(), err)
+	}
+	live, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+	if err != nil {
+		return false, fmt.Errorf("failed to convert %s %s: %w", u.GetKind(), u.GetName(), err)
+	}
+	// The type and status of the live resource aren't set by the manifest.
+	desired := u.DeepCopy().Object
+	delete(desired, "apiVersion")
+	delete(desired, "kind")
+	delete(desired, "status")
+	return equality.Semantic.DeepDerivative(desired, live), nil
+}
@@ -0,0 +1,209 @@
+package knativekafka
+
+import (
+	"context"
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"knative.dev/operator/pkg/apis/operator/base"
+	operatorv1beta1 "knative.dev/operator/pkg/apis/operator/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openshift-knative/serverless-operator/knative-operator/pkg/apis/operator/v1alpha1"
+	"github.com/openshift-knative/serverless-operator/knative-operator/pkg/monitoring"
+)
+
+func TestSkipUnchangedManifests(t *testing.T) {
+	t.Setenv("TEST_DEPRECATED_APIS_K8S_VERSION", "v1.24.0")
+
+	instance := makeCr(withChannelEnabled, withSourceEnabled)
+	cl := fake.NewClientBuilder().
+		WithObjects(instance, &operatorv1beta1.KnativeEventing{}).
+		WithObjects(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Namespace: defaultRequest.Namespace,
+			Name:      "config-features",
+		}}).
+		WithStatusSubresource(&v1alpha1.KnativeKafka{}).
+		Build()
+
+	r := &ReconcileKnativeKafka{
+		client:                  cl,
+		apiReader:               cl,
+		scheme:                  scheme.Scheme,
+		rawKafkaChannelManifest: mustManifestFrom(t, "testdata/channel/eventing-kafka-channel.yaml"),
+		rawKafkaSourceManifest:  mustManifestFrom(t, "testdata/source/eventing-kafka-source.yaml"),
+		clusterProbe:            &fakeClusterProbe{},
+	}
+
+	reconcileRolledOut(t, r, cl)
+	kk := getKnativeKafka(t, cl)
+	for _, component := range []string{"channel", "controlPlane", "source"} {
+		if kk.Status.ManifestHashes[component] == "" {
+			t.Errorf("ManifestHashes[%s] is empty, got %v", component, kk.Status.ManifestHashes)
+		}
+	}
+
+	// Nothing changed, nothing is applied.
+	channelSkipped := counterValue(t, "channel", "skipped")
+	receiver := getDeployment(t, cl, "kafka-channel-receiver")
+	if _, err := r.Reconcile(context.Background(), defaultRequest); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	if got := counterValue(t, "channel", "skipped"); got != channelSkipped+1 {
+		t.Errorf("channel skipped applies = %v, want %v", got, channelSkipped+1)
+	}
+	if got := getDeployment(t, cl, "kafka-channel-receiver"); got.ResourceVersion != receiver.ResourceVersion {
+		t.Errorf("kafka-channel-receiver was updated although its manifest is unchanged")
+	}
+
+	// Only the component whose manifest changed is applied.
+	kk = getKnativeKafka(t, cl)
+	kk.Spec.Workloads = []base.WorkloadOverride{{
+		Name:   "kafka-source-dispatcher",
+		Labels: map[string]string{"tuned": "true"},
+	}}
+	if err := cl.Update(context.Background(), kk); err != nil {
+		t.Fatalf("update: (%v)", err)
+	}
+	sourceHash := kk.Status.ManifestHashes["source"]
+	channelSkipped = counterValue(t, "channel", "skipped")
+	sourceApplied := counterValue(t, "source", "applied")
+	receiver = getDeployment(t, cl, "kafka-channel-receiver")
+	if _, err := r.Reconcile(context.Background(), defaultRequest); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	if got := counterValue(t, "channel", "skipped"); got != channelSkipped+1 {
+		t.Errorf("channel skipped applies = %v, want %v", got, channelSkipped+1)
+	}
+	if got := counterValue(t, "source", "applied"); got != sourceApplied+1 {
+		t.Errorf("source applies = %v, want %v", got, sourceApplied+1)
+	}
+	if got := getDeployment(t, cl, "kafka-channel-receiver"); got.ResourceVersion != receiver.ResourceVersion {
+		t.Errorf("kafka-channel-receiver was updated although its manifest is unchanged")
+	}
+
+	// The new hash is recorded once the rollout completes.
+	reconcileRolledOut(t, r, cl)
+	if got := getKnativeKafka(t, cl).Status.ManifestHashes["source"]; got == sourceHash {
+		t.Errorf("ManifestHashes[source] = %s, want a new hash", got)
+	}
+	ss := &appsv1.StatefulSet{}
+	if err := cl.Get(context.Background(), types.NamespacedName{Namespace: "knative-eventing", Name: "kafka-source-dispatcher"}, ss); err != nil {
+		t.Fatalf("get: (%v)", err)
+	}
+	if ss.Labels["tuned"] != "true" {
+		t.Errorf("kafka-source-dispatcher labels = %v, want the override applied", ss.Labels)
+	}
+}
+
+func TestRevertModifiedResources(t *testing.T) {
+	t.Setenv("TEST_DEPRECATED_APIS_K8S_VERSION", "v1.24.0")
+
+	instance := makeCr(withChannelEnabled)
+	cl := fake.NewClientBuilder().
+		WithObjects(instance, &operatorv1beta1.KnativeEventing{}).
+		WithObjects(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Namespace: defaultRequest.Namespace,
+			Name:      "config-features",
+		}}).
+		WithStatusSubresource(&v1alpha1.KnativeKafka{}).
+		Build()
+
+	r := &ReconcileKnativeKafka{
+		client:                  cl,
+		apiReader:               cl,
+		scheme:                  scheme.Scheme,
+		rawKafkaChannelManifest: mustManifestFrom(t, "testdata/channel/eventing-kafka-channel.yaml"),
+		clusterProbe:            &fakeClusterProbe{},
+	}
+	reconcileRolledOut(t, r, cl)
+
+	// The image of the receiver is changed out of band, its component is applied again
+	// although its manifest is unchanged.
+	receiver := getDeployment(t, cl, "kafka-channel-receiver")
+	image := receiver.Spec.Template.Spec.Containers[0].Image
+	receiver.Spec.Template.Spec.Containers[0].Image = "example.com/modified"
+	if err := cl.Update(context.Background(), receiver); err != nil {
+		t.Fatalf("update: (%v)", err)
+	}
+	channelApplied := counterValue(t, "channel", "applied")
+	reconcileRolledOut(t, r, cl)
+	if got := counterValue(t, "channel", "applied"); got <= channelApplied {
+		t.Errorf("channel applies = %v, want more than %v", got, channelApplied)
+	}
+	if got := getDeployment(t, cl, "kafka-channel-receiver").Spec.Template.Spec.Containers[0].Image; got != image {
+		t.Errorf("kafka-channel-receiver image = %s, want %s", got, image)
+	}
+
+	// Once reverted, the component is skipped again.
+	channelSkipped := counterValue(t, "channel", "skipped")
+	if _, err := r.Reconcile(context.Background(), defaultRequest); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	if got := counterValue(t, "channel", "skipped"); got != channelSkipped+1 {
+		t.Errorf("channel skipped applies = %v, want %v", got, channelSkipped+1)
+	}
+}
+
+func TestManifestHashes(t *testing.T) {
+	resources := annotateComponent(mustManifestFrom(t, "testdata/channel/eventing-kafka-channel.yaml").Resources(), "channel")
+	resources = append(resources, annotateComponent(mustManifestFrom(t, "testdata/source/eventing-kafka-source.yaml").Resources(), "source")...)
+	manifest := mustManifest(t, resources)
+
+	hashes, err := manifestHashes(manifest)
+	if err != nil {
+		t.Fatalf("manifestHashes() = %v", err)
+	}
+	again, err := manifestHashes(manifest)
+	if err != nil {
+		t.Fatalf("manifestHashes() = %v", err)
+	}
+	if len(hashes) != 2 || hashes["channel"] != again["channel"] || hashes["source"] != again["source"] {
+		t.Fatalf("manifestHashes() = %v then %v, want stable hashes of both components", hashes, again)
+	}
+
+	changed, err := manifest.Transform(func(u *unstructured.Unstructured) error {
+		if u.GetKind() == "StatefulSet" && u.GetName() == "kafka-source-dispatcher" {
+			u.SetLabels(map[string]string{"tuned": "true"})
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Transform() = %v", err)
+	}
+	changedHashes, err := manifestHashes(changed)
+	if err != nil {
+		t.Fatalf("manifestHashes() = %v", err)
+	}
+	if changedHashes["channel"] != hashes["channel"] {
+		t.Error("channel hash changed although only the source changed")
+	}
+	if changedHashes["source"] == hashes["source"] {
+		t.Error("source hash didn't change")
+	}
+}
+
+func getDeployment(t *testing.T, cl client.Client, name string) *appsv1.Deployment {
+	t.Helper()
+	d := &appsv1.Deployment{}
+	if err := cl.Get(context.Background(), types.NamespacedName{Namespace: "knative-eventing", Name: name}, d); err != nil {
+		t.Fatalf("get: (%v)", err)
+	}
+	return d
+}
+
+func counterValue(t *testing.T, component, result string) float64 {
+	t.Helper()
+	m := &dto.Metric{}
+	if err := monitoring.KnativeKafkaManifestApplies.WithLabelValues(component, result).Write(m); err != nil {
+		t.Fatalf("Write() = %v", err)
+	}
+	return m.GetCounter().GetValue()
+}
@@ -0,0 +1,62 @@
+package knativekafka
+
+import (
+	"context"
+	"fmt"
+
+	mf "github.com/manifestival/manifestival"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	serverlessoperatorv1alpha1 "github.com/openshift-knative/serverless-operator/knative-operator/pkg/apis/operator/v1alpha1"
+)
+
+// Render returns the resources the controller would apply for the given KnativeKafka, in
+// the order they would be applied, without applying anything. The Kafka manifests are
+// loaded from the same environment variables as the controller's, and the cluster state
+// the transformers depend on, e.g. KnativeEventing and config-features, is read through
+// the given client. Writes are never issued, the client is wrapped in a dry-run client.
+func Render(ctx context.Context, c client.Client, instance *serverlessoperatorv1alpha1.KnativeKafka) ([]unstructured.Unstructured, error) {
+	r, err := newReconcilerWithClient(client.NewDryRunClient(c), c.Scheme())
+	if err != nil {
+		return nil, err
+	}
+	return r.render(ctx, instance.DeepCopy())
+}
+
+// render runs the install stages that transform the manifest of the given KnativeKafka,
+// leaving out the ones writing to the cluster.
+func (r *ReconcileKnativeKafka) render(ctx context.Context, instance *serverlessoperatorv1alpha1.KnativeKafka) ([]unstructured.Unstructured, error) {
+	manifest, err := r.buildManifest(instance, manifestBuildEnabledOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load and build manifest: %w", err)
+	}
+
+	if err := executeStages(instance, manifest, r.installStages(ctx, true)); err != nil {
+		return nil, err
+	}
+
+	// Same order as in apply.
+	resources := manifest.Filter(role).Resources()
+	resources = append(resources, manifest.Filter(rolebinding).Resources()...)
+	resources = append(resources, manifest.Filter(mf.Not(roleOrRoleBinding), mf.Not(rolloutWorkload)).Resources()...)
+	for _, phase := range rolloutPhases {
+		resources = append(resources, manifest.Filter(phase.has).Resources()...)
+	}
+	return resources, nil
+}
+
+// filterTLSResources is the read-only counterpart of handleTLSResources, it leaves out the
+// TLS resources when Eventing TLS is disabled without deleting them.
+func (r *ReconcileKnativeKafka) filterTLSResources(ctx context.Context) stage {
+	return func(manifests *mf.Manifest, instance *serverlessoperatorv1alpha1.KnativeKafka) error {
+		enabled, err := r.isTLSEnabled(ctx, instance)
+		if err != nil {
+			return err
+		}
+		if !enabled {
+			*manifests = manifests.Filter(mf.Not(tlsResourcesPred))
+		}
+		return nil
+	}
+}
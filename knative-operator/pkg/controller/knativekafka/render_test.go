@@ -0,0 +1,151 @@
+package knativekafka
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	operatorv1beta1 "knative.dev/operator/pkg/apis/operator/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openshift-knative/serverless-operator/knative-operator/pkg/apis/operator/v1alpha1"
+)
+
+func TestRender(t *testing.T) {
+	setManifestPaths(t)
+
+	kk := &v1alpha1.KnativeKafka{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "knative-kafka",
+			Namespace: "knative-eventing",
+		},
+		Spec: v1alpha1.KnativeKafkaSpec{
+			Broker: v1alpha1.Broker{
+				Enabled: true,
+				DefaultConfig: v1alpha1.BrokerDefaultConfig{
+					BootstrapServers:  "my-cluster-kafka-bootstrap.kafka:9092",
+					NumPartitions:     10,
+					ReplicationFactor: 3,
+				},
+			},
+		},
+	}
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(kk, &operatorv1beta1.KnativeEventing{ObjectMeta: metav1.ObjectMeta{Namespace: "knative-eventing", Name: "knative-eventing"}}).
+		WithObjects(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "knative-eventing", Name: "config-features"}}).
+		Build()
+
+	resources, err := Render(context.Background(), cl, kk)
+	if err != nil {
+		t.Fatalf("Render() = %v", err)
+	}
+
+	names := map[string]bool{}
+	rbacDone := false
+	for i := range resources {
+		u := &resources[i]
+		names[u.GetKind()+"/"+u.GetName()] = true
+		if !roleOrRoleBinding(u) {
+			rbacDone = true
+		} else if rbacDone {
+			t.Errorf("%s/%s is rendered after non RBAC resources", u.GetKind(), u.GetName())
+		}
+	}
+	for _, want := range []string{"Deployment/kafka-controller", "ConfigMap/kafka-broker-config", "StatefulSet/kafka-broker-dispatcher"} {
+		if !names[want] {
+			t.Errorf("Render() is missing %s", want)
+		}
+	}
+	if names["Deployment/kafka-channel-receiver"] {
+		t.Error("Render() contains the resources of the disabled channel")
+	}
+
+	// Nothing is written, the finalizer is not added.
+	got := &v1alpha1.KnativeKafka{}
+	if err := cl.Get(context.Background(), types.NamespacedName{Namespace: "knative-eventing", Name: "knative-kafka"}, got); err != nil {
+		t.Fatalf("Get() = %v", err)
+	}
+	if len(got.Finalizers) != 0 {
+		t.Errorf("Finalizers = %v, want none", got.Finalizers)
+	}
+	configMaps := &corev1.ConfigMapList{}
+	if err := cl.List(context.Background(), configMaps); err != nil {
+		t.Fatalf("List() = %v", err)
+	}
+	if len(configMaps.Items) != 1 {
+		t.Errorf("ConfigMaps = %d, want only config-features", len(configMaps.Items))
+	}
+}
+
+// TestRenderClusterProfiles checks that the ConfigMaps of the Kafka cluster profiles don't
+// replace the ConfigMaps of the manifests, whatever the name of the profiles.
+func TestRenderClusterProfiles(t *testing.T) {
+	setManifestPaths(t)
+
+	kk := &v1alpha1.KnativeKafka{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "knative-kafka",
+			Namespace: "knative-eventing",
+		},
+		Spec: v1alpha1.KnativeKafkaSpec{
+			Broker: v1alpha1.Broker{
+				Enabled: true,
+				DefaultConfig: v1alpha1.BrokerDefaultConfig{
+					BootstrapServers:  "my-cluster-kafka-bootstrap.kafka:9092",
+					NumPartitions:     10,
+					ReplicationFactor: 3,
+				},
+			},
+			Clusters: []v1alpha1.KafkaCluster{
+				{Name: "logging", BootstrapServers: "logging.kafka:9092"},
+				{Name: "data-plane", BootstrapServers: "data-plane.kafka:9092"},
+			},
+		},
+	}
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(kk, &operatorv1beta1.KnativeEventing{ObjectMeta: metav1.ObjectMeta{Namespace: "knative-eventing", Name: "knative-eventing"}}).
+		WithObjects(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "knative-eventing", Name: "config-features"}}).
+		Build()
+
+	resources, err := Render(context.Background(), cl, kk)
+	if err != nil {
+		t.Fatalf("Render() = %v", err)
+	}
+
+	configMaps := map[string]int{}
+	var profiles []string
+	for i := range resources {
+		u := &resources[i]
+		if u.GetKind() != "ConfigMap" {
+			continue
+		}
+		configMaps[u.GetName()]++
+		if _, ok := u.GetLabels()[clusterLabelKey]; ok {
+			profiles = append(profiles, u.GetName())
+		}
+	}
+	for name, count := range configMaps {
+		if count > 1 {
+			t.Errorf("Render() has %d ConfigMaps %s, want 1", count, name)
+		}
+	}
+	wantProfiles := []string{clusterConfigMapName("logging"), clusterConfigMapName("data-plane")}
+	if !cmp.Equal(profiles, wantProfiles) {
+		t.Errorf("cluster profiles = %s", cmp.Diff(wantProfiles, profiles))
+	}
+}
+
+func setManifestPaths(t *testing.T) {
+	t.Setenv("KAFKACHANNEL_MANIFEST_PATH", "../../../deploy/resources/knativekafka/channel")
+	t.Setenv("KAFKASOURCE_MANIFEST_PATH", "../../../deploy/resources/knativekafka/source")
+	t.Setenv("KAFKACONTROLLER_MANIFEST_PATH", "../../../deploy/resources/knativekafka/controller")
+	t.Setenv("KAFKABROKER_MANIFEST_PATH", "../../../deploy/resources/knativekafka/broker")
+	t.Setenv("KAFKASINK_MANIFEST_PATH", "../../../deploy/resources/knativekafka/sink")
+	t.Setenv("TEST_DEPRECATED_APIS_K8S_VERSION", "v1.24.0")
+}
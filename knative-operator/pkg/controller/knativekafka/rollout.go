@@ -0,0 +1,143 @@
+package knativekafka
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	mf "github.com/manifestival/manifestival"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	serverlessoperatorv1alpha1 "github.com/openshift-knative/serverless-operator/knative-operator/pkg/apis/operator/v1alpha1"
+)
+
+// rolloutPhase is a set of workloads that are applied together. The workloads of a phase
+// are only applied once the workloads of the previous phases are rolled out, so that an
+// upgrade never runs new receivers or dispatchers against an old control plane.
+type rolloutPhase struct {
+	name      string
+	workloads sets.Set[string]
+}
+
+// rolloutPhases are the rollout phases, in rollout order. The workloads that aren't part of
+// any phase, e.g. the jobs, are applied along with the rest of the manifest.
+var rolloutPhases = []rolloutPhase{{
+	name:      "ControlPlane",
+	workloads: sets.New("kafka-controller"),
+}, {
+	name:      "Webhook",
+	workloads: sets.New("kafka-webhook-eventing"),
+}, {
+	name:      "Receivers",
+	workloads: sets.New("kafka-broker-receiver", "kafka-channel-receiver", "kafka-sink-receiver"),
+}, {
+	name:      "Dispatchers",
+	workloads: sets.New("kafka-broker-dispatcher", "kafka-channel-dispatcher", "kafka-source-dispatcher"),
+}}
+
+// has matches the Deployments and StatefulSets of the phase.
+func (p rolloutPhase) has(u *unstructured.Unstructured) bool {
+	return (u.GetKind() == "Deployment" || u.GetKind() == "StatefulSet") && p.workloads.Has(u.GetName())
+}
+
+// rolloutWorkload matches the workloads of all the rollout phases.
+func rolloutWorkload(u *unstructured.Unstructured) bool {
+	for _, phase := range rolloutPhases {
+		if phase.has(u) {
+			return true
+		}
+	}
+	return false
+}
+
+// rollout applies the workloads of the rollout phases in order, stopping at the first phase
+// whose workloads are not rolled out yet, and records the progress in the status. It returns
+// whether all the phases are rolled out.
+func (r *ReconcileKnativeKafka) rollout(ctx context.Context, manifest *mf.Manifest, instance *serverlessoperatorv1alpha1.KnativeKafka) (bool, error) {
+	status := &serverlessoperatorv1alpha1.KafkaRolloutStatus{}
+	instance.Status.Rollout = status
+
+	for _, phase := range rolloutPhases {
+		workloads := manifest.Filter(phase.has)
+		if len(workloads.Resources()) == 0 {
+			continue
+		}
+		if err := workloads.Apply(); err != nil {
+			return false, fmt.Errorf("failed to apply the %s workloads: %w", phase.name, err)
+		}
+
+		var waiting []string
+		for _, u := range workloads.Resources() {
+			done, err := r.isRolledOut(ctx, &u)
+			if err != nil {
+				return false, err
+			}
+			if !done {
+				waiting = append(waiting, u.GetName())
+			}
+		}
+		if len(waiting) > 0 {
+			log.Info("Waiting on rollout phase", "phase", phase.name, "workloads", waiting)
+			sort.Strings(waiting)
+			status.Phase = phase.name
+			status.Waiting = waiting
+			return false, nil
+		}
+		status.Completed = append(status.Completed, phase.name)
+	}
+	return true, nil
+}
+
+// isRolledOut returns whether all the replicas of the given workload run its latest
+// revision and are available. The workload is read from the API server rather than from
+// the cache, which might not have observed the update that was just applied.
+func (r *ReconcileKnativeKafka) isRolledOut(ctx context.Context, u *unstructured.Unstructured) (bool, error) {
+	key := types.NamespacedName{Namespace: u.GetNamespace(), Name: u.GetName()}
+	switch u.GetKind() {
+	case "Deployment":
+		d := &appsv1.Deployment{}
+		if err := r.apiReader.Get(ctx, key, d); err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, fmt.Errorf("failed to get Deployment %s: %w", key, err)
+		}
+		return isDeploymentRolledOut(d), nil
+	case "StatefulSet":
+		ss := &appsv1.StatefulSet{}
+		if err := r.apiReader.Get(ctx, key, ss); err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, fmt.Errorf("failed to get StatefulSet %s: %w", key, err)
+		}
+		return isStatefulSetRolledOut(ss), nil
+	}
+	return true, nil
+}
+
+func isDeploymentRolledOut(d *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+	return d.Status.ObservedGeneration >= d.Generation &&
+		d.Status.UpdatedReplicas == replicas &&
+		d.Status.Replicas == replicas &&
+		d.Status.AvailableReplicas >= replicas
+}
+
+func isStatefulSetRolledOut(ss *appsv1.StatefulSet) bool {
+	replicas := int32(1)
+	if ss.Spec.Replicas != nil {
+		replicas = *ss.Spec.Replicas
+	}
+	return ss.Status.ObservedGeneration >= ss.Generation &&
+		ss.Status.UpdatedReplicas == replicas &&
+		ss.Status.ReadyReplicas == replicas &&
+		ss.Status.CurrentRevision == ss.Status.UpdateRevision
+}
@@ -0,0 +1,241 @@
+package knativekafka
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	mf "github.com/manifestival/manifestival"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"knative.dev/operator/pkg/apis/operator/base"
+	operatorv1beta1 "knative.dev/operator/pkg/apis/operator/v1beta1"
+	"knative.dev/pkg/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openshift-knative/serverless-operator/knative-operator/pkg/apis/operator/v1alpha1"
+)
+
+func TestRollout(t *testing.T) {
+	t.Setenv("TEST_DEPRECATED_APIS_K8S_VERSION", "v1.24.0")
+
+	instance := makeCr(withChannelEnabled, withSourceEnabled)
+	cl := fake.NewClientBuilder().
+		WithObjects(instance, &operatorv1beta1.KnativeEventing{}).
+		WithObjects(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Namespace: defaultRequest.Namespace,
+			Name:      "config-features",
+		}}).
+		WithStatusSubresource(&v1alpha1.KnativeKafka{}).
+		Build()
+
+	kafkaChannelManifest, err := mf.ManifestFrom(mf.Path("testdata/channel/eventing-kafka-channel.yaml"))
+	if err != nil {
+		t.Fatalf("failed to load KafkaChannel manifest: %v", err)
+	}
+	kafkaSourceManifest, err := mf.ManifestFrom(mf.Path("testdata/source/eventing-kafka-source.yaml"))
+	if err != nil {
+		t.Fatalf("failed to load KafkaSource manifest: %v", err)
+	}
+
+	r := &ReconcileKnativeKafka{
+		client:                  cl,
+		apiReader:               cl,
+		scheme:                  scheme.Scheme,
+		rawKafkaChannelManifest: kafkaChannelManifest,
+		rawKafkaSourceManifest:  kafkaSourceManifest,
+		clusterProbe:            &fakeClusterProbe{},
+	}
+
+	// The receivers are applied first, the dispatchers wait for them.
+	if _, err := r.Reconcile(context.Background(), defaultRequest); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	kk := getKnativeKafka(t, cl)
+	want := &v1alpha1.KafkaRolloutStatus{
+		Phase:   "Receivers",
+		Waiting: []string{"kafka-channel-receiver"},
+	}
+	if !cmp.Equal(kk.Status.Rollout, want) {
+		t.Errorf("Rollout = %s", cmp.Diff(want, kk.Status.Rollout))
+	}
+	if c := kk.Status.GetCondition(base.InstallSucceeded); c == nil || !c.IsUnknown() || c.Reason != "RollingOut" {
+		t.Errorf("InstallSucceeded = %v, want Unknown with reason RollingOut", c)
+	}
+	if kk.Status.Version != "" {
+		t.Errorf("Version = %q, want none until the rollout completes", kk.Status.Version)
+	}
+	for _, name := range []string{"kafka-channel-dispatcher", "kafka-source-dispatcher"} {
+		if err := cl.Get(context.Background(), types.NamespacedName{Namespace: "knative-eventing", Name: name}, &appsv1.StatefulSet{}); !apierrors.IsNotFound(err) {
+			t.Errorf("Get(%s) = %v, want NotFound before the receivers are rolled out", name, err)
+		}
+	}
+
+	// Once the receivers are rolled out, the dispatchers are applied.
+	markRolledOut(t, cl, "kafka-channel-receiver")
+	if _, err := r.Reconcile(context.Background(), defaultRequest); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	kk = getKnativeKafka(t, cl)
+	want = &v1alpha1.KafkaRolloutStatus{
+		Phase:     "Dispatchers",
+		Completed: []string{"Receivers"},
+		Waiting:   []string{"kafka-channel-dispatcher", "kafka-source-dispatcher"},
+	}
+	if !cmp.Equal(kk.Status.Rollout, want) {
+		t.Errorf("Rollout = %s", cmp.Diff(want, kk.Status.Rollout))
+	}
+
+	markRolledOut(t, cl, "kafka-channel-dispatcher", "kafka-source-dispatcher")
+	if _, err := r.Reconcile(context.Background(), defaultRequest); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+	kk = getKnativeKafka(t, cl)
+	want = &v1alpha1.KafkaRolloutStatus{
+		Completed: []string{"Receivers", "Dispatchers"},
+	}
+	if !cmp.Equal(kk.Status.Rollout, want) {
+		t.Errorf("Rollout = %s", cmp.Diff(want, kk.Status.Rollout))
+	}
+	if c := kk.Status.GetCondition(base.InstallSucceeded); c == nil || !c.IsTrue() {
+		t.Errorf("InstallSucceeded = %v, want true", c)
+	}
+}
+
+func TestIsRolledOut(t *testing.T) {
+	tests := []struct {
+		name string
+		obj  client.Object
+		want bool
+	}{{
+		name: "deployment rolled out",
+		obj: &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Generation: 2},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.Int32(2)},
+			Status:     appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2},
+		},
+		want: true,
+	}, {
+		name: "deployment generation not observed",
+		obj: &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Generation: 3},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.Int32(2)},
+			Status:     appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2},
+		},
+	}, {
+		name: "deployment with old replicas",
+		obj: &appsv1.Deployment{
+			Spec:   appsv1.DeploymentSpec{Replicas: ptr.Int32(2)},
+			Status: appsv1.DeploymentStatus{Replicas: 3, UpdatedReplicas: 2, AvailableReplicas: 3},
+		},
+	}, {
+		name: "statefulset rolled out",
+		obj: &appsv1.StatefulSet{
+			Spec:   appsv1.StatefulSetSpec{Replicas: ptr.Int32(1)},
+			Status: appsv1.StatefulSetStatus{Replicas: 1, UpdatedReplicas: 1, ReadyReplicas: 1, CurrentRevision: "a", UpdateRevision: "a"},
+		},
+		want: true,
+	}, {
+		name: "statefulset revision not rolled out",
+		obj: &appsv1.StatefulSet{
+			Spec:   appsv1.StatefulSetSpec{Replicas: ptr.Int32(1)},
+			Status: appsv1.StatefulSetStatus{Replicas: 1, UpdatedReplicas: 1, ReadyReplicas: 1, CurrentRevision: "a", UpdateRevision: "b"},
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got bool
+			switch obj := test.obj.(type) {
+			case *appsv1.Deployment:
+				got = isDeploymentRolledOut(obj)
+			case *appsv1.StatefulSet:
+				got = isStatefulSetRolledOut(obj)
+			}
+			if got != test.want {
+				t.Errorf("rolled out = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// reconcileRolledOut reconciles until all the rollout phases are applied, marking the
+// workloads as rolled out in between.
+func reconcileRolledOut(t *testing.T, r *ReconcileKnativeKafka, cl client.Client) {
+	t.Helper()
+	for range len(rolloutPhases) + 1 {
+		if _, err := r.Reconcile(context.Background(), defaultRequest); err != nil {
+			t.Fatalf("reconcile: (%v)", err)
+		}
+		deployments := &appsv1.DeploymentList{}
+		if err := cl.List(context.Background(), deployments); err != nil {
+			t.Fatalf("list: (%v)", err)
+		}
+		statefulSets := &appsv1.StatefulSetList{}
+		if err := cl.List(context.Background(), statefulSets); err != nil {
+			t.Fatalf("list: (%v)", err)
+		}
+		var names []string
+		for _, d := range deployments.Items {
+			names = append(names, d.Name)
+		}
+		for _, ss := range statefulSets.Items {
+			names = append(names, ss.Name)
+		}
+		markRolledOut(t, cl, names...)
+	}
+}
+
+// markRolledOut sets the status of the given workloads as if all their replicas were
+// updated, leaving out the Available condition of the Deployments.
+func markRolledOut(t *testing.T, cl client.Client, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		key := types.NamespacedName{Namespace: "knative-eventing", Name: name}
+		d := &appsv1.Deployment{}
+		err := cl.Get(context.Background(), key, d)
+		if err == nil {
+			replicas := int32(1)
+			if d.Spec.Replicas != nil {
+				replicas = *d.Spec.Replicas
+			}
+			d.Status.ObservedGeneration = d.Generation
+			d.Status.Replicas, d.Status.UpdatedReplicas, d.Status.AvailableReplicas = replicas, replicas, replicas
+			if err := cl.Status().Update(context.Background(), d); err != nil {
+				t.Fatalf("update: (%v)", err)
+			}
+			continue
+		}
+		if !apierrors.IsNotFound(err) {
+			t.Fatalf("get: (%v)", err)
+		}
+		ss := &appsv1.StatefulSet{}
+		if err := cl.Get(context.Background(), key, ss); err != nil {
+			t.Fatalf("get: (%v)", err)
+		}
+		replicas := int32(1)
+		if ss.Spec.Replicas != nil {
+			replicas = *ss.Spec.Replicas
+		}
+		ss.Status.ObservedGeneration = ss.Generation
+		ss.Status.Replicas, ss.Status.UpdatedReplicas, ss.Status.ReadyReplicas = replicas, replicas, replicas
+		ss.Status.CurrentRevision, ss.Status.UpdateRevision = "current", "current"
+		if err := cl.Status().Update(context.Background(), ss); err != nil {
+			t.Fatalf("update: (%v)", err)
+		}
+	}
+}
+
+func getKnativeKafka(t *testing.T, cl client.Client) *v1alpha1.KnativeKafka {
+	t.Helper()
+	kk := &v1alpha1.KnativeKafka{}
+	if err := cl.Get(context.Background(), defaultRequest.NamespacedName, kk); err != nil {
+		t.Fatalf("get: (%v)", err)
+	}
+	return kk
+}
@@ -0,0 +1,172 @@
+package knativekafka
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	mf "github.com/manifestival/manifestival"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	serverlessoperatorv1alpha1 "github.com/openshift-knative/serverless-operator/knative-operator/pkg/apis/operator/v1alpha1"
+)
+
+const (
+	// scaledWorkloadLabelKey labels the generated autoscalers with the name of the workload they scale.
+	scaledWorkloadLabelKey = "operator.serverless.openshift.io/kafka-scaled-workload"
+
+	// autoscalerConfigMapName is the ConfigMap configuring the autoscaling of the dispatchers
+	// by the kafka-controller.
+	autoscalerConfigMapName   = "config-kafka-autoscaler"
+	autoscalerMinScaleKey     = "min-scale"
+	autoscalerMaxScaleKey     = "max-scale"
+	autoscalerLagThresholdKey = "lag-threshold"
+)
+
+var horizontalPodAutoscalerGVK = autoscalingv2.SchemeGroupVersion.WithKind("HorizontalPodAutoscaler")
+
+// scalingResources returns the autoscalers of the receivers of the given component manifest.
+// The dispatchers are left out, their StatefulSets are scaled by the kafka-controller, see
+// configureDispatcherScaling.
+func scalingResources(manifest mf.Manifest, scaling *serverlessoperatorv1alpha1.Scaling) ([]unstructured.Unstructured, error) {
+	if scaling == nil || scaling.Receiver == nil {
+		return nil, nil
+	}
+	var resources []unstructured.Unstructured
+	for _, u := range manifest.Filter(mf.ByKind("Deployment"), receiver).Resources() {
+		hpa, err := horizontalPodAutoscaler(&u, scaling.Receiver)
+		if err != nil {
+			return nil, err
+		}
+		hpa.SetNamespace(u.GetNamespace())
+		hpa.SetName(u.GetName())
+		hpa.SetLabels(map[string]string{scaledWorkloadLabelKey: u.GetName()})
+		resources = append(resources, *hpa)
+	}
+	return resources, nil
+}
+
+func receiver(u *unstructured.Unstructured) bool {
+	return strings.HasSuffix(u.GetName(), "-receiver")
+}
+
+// horizontalPodAutoscaler returns a HorizontalPodAutoscaler scaling the given workload on
+// its resource utilization.
+func horizontalPodAutoscaler(workload *unstructured.Unstructured, ws *serverlessoperatorv1alpha1.WorkloadScaling) (*unstructured.Unstructured, error) {
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: workload.GetAPIVersion(),
+				Kind:       workload.GetKind(),
+				Name:       workload.GetName(),
+			},
+			MinReplicas: ws.MinReplicas,
+			MaxReplicas: ws.MaxReplicas,
+		},
+	}
+	for _, m := range []struct {
+		name        corev1.ResourceName
+		utilization *int32
+	}{{corev1.ResourceCPU, ws.CPUUtilization}, {corev1.ResourceMemory, ws.MemoryUtilization}} {
+		if m.utilization == nil {
+			continue
+		}
+		hpa.Spec.Metrics = append(hpa.Spec.Metrics, autoscalingv2.MetricSpec{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricSource{
+				Name: m.name,
+				Target: autoscalingv2.MetricTarget{
+					Type:               autoscalingv2.UtilizationMetricType,
+					AverageUtilization: m.utilization,
+				},
+			},
+		})
+	}
+
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(hpa)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert HorizontalPodAutoscaler: %w", err)
+	}
+	u := &unstructured.Unstructured{Object: obj}
+	// The status is owned by the HorizontalPodAutoscaler controller.
+	unstructured.RemoveNestedField(u.Object, "status")
+	u.SetGroupVersionKind(horizontalPodAutoscalerGVK)
+	return u, nil
+}
+
+// scaledWorkloads returns the names of the workloads scaled by the autoscalers of the given manifest.
+func scaledWorkloads(manifest mf.Manifest) sets.Set[string] {
+	workloads := sets.New[string]()
+	for _, u := range manifest.Resources() {
+		if name, ok := u.GetLabels()[scaledWorkloadLabelKey]; ok {
+			workloads.Insert(name)
+		}
+	}
+	return workloads
+}
+
+// unsetScaledReplicas leaves the replicas of the scaled receivers to their autoscaler.
+func unsetScaledReplicas(workloads sets.Set[string]) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if u.GetKind() == "Deployment" && workloads.Has(u.GetName()) {
+			unstructured.RemoveNestedField(u.Object, "spec", "replicas")
+		}
+		return nil
+	}
+}
+
+// deleteStaleScalers deletes the autoscalers that are no longer part of the manifest, i.e.
+// the ones whose scaling or component has been removed from the spec.
+func (r *ReconcileKnativeKafka) deleteStaleScalers(ctx context.Context) stage {
+	return func(manifest *mf.Manifest, _ *serverlessoperatorv1alpha1.KnativeKafka) error {
+		hpas := &autoscalingv2.HorizontalPodAutoscalerList{}
+		if err := r.client.List(ctx, hpas, client.HasLabels{scaledWorkloadLabelKey}); err != nil {
+			return fmt.Errorf("failed to list HorizontalPodAutoscalers: %w", err)
+		}
+		for i := range hpas.Items {
+			hpa := &hpas.Items[i]
+			if len(manifest.Filter(mf.ByGVK(horizontalPodAutoscalerGVK), mf.ByName(hpa.Name), byNamespace(hpa.Namespace)).Resources()) > 0 {
+				continue
+			}
+			log.Info("Deleting stale HorizontalPodAutoscaler", "name", hpa.Name)
+			if err := r.client.Delete(ctx, hpa); err != nil && !apierrors.IsNotFound(err) {
+				return fmt.Errorf("failed to delete HorizontalPodAutoscaler %s: %w", hpa.Name, err)
+			}
+		}
+		return nil
+	}
+}
+
+// configureDispatcherScaling renders the autoscaling of the dispatchers into the
+// config-kafka-autoscaler ConfigMap of the kafka-controller.
+func configureDispatcherScaling(u *unstructured.Unstructured, scaling *serverlessoperatorv1alpha1.DispatcherScaling) error {
+	values := map[string]string{}
+	if scaling.MinScale != nil {
+		values[autoscalerMinScaleKey] = strconv.FormatInt(int64(*scaling.MinScale), 10)
+	}
+	if scaling.MaxScale != nil {
+		values[autoscalerMaxScaleKey] = strconv.FormatInt(int64(*scaling.MaxScale), 10)
+	}
+	if scaling.LagThreshold != nil {
+		values[autoscalerLagThresholdKey] = strconv.FormatInt(*scaling.LagThreshold, 10)
+	}
+	for key, value := range values {
+		if err := unstructured.SetNestedField(u.Object, value, "data", key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func byNamespace(namespace string) mf.Predicate {
+	return func(u *unstructured.Unstructured) bool {
+		return u.GetNamespace() == namespace
+	}
+}
@@ -0,0 +1,175 @@
+package knativekafka
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	mf "github.com/manifestival/manifestival"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"knative.dev/pkg/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openshift-knative/serverless-operator/knative-operator/pkg/apis/operator/v1alpha1"
+)
+
+func TestScalingResources(t *testing.T) {
+	manifest := mustManifestFrom(t, "testdata/channel/eventing-kafka-channel.yaml")
+	resources, err := scalingResources(manifest, &v1alpha1.Scaling{
+		Receiver: &v1alpha1.WorkloadScaling{
+			MinReplicas:    ptr.Int32(2),
+			MaxReplicas:    5,
+			CPUUtilization: ptr.Int32(70),
+		},
+	})
+	if err != nil {
+		t.Fatalf("scalingResources() = %v", err)
+	}
+	// Only the receiver is scaled, the dispatcher is left to the kafka-controller.
+	scalers := mustManifest(t, resources)
+	hpas := scalers.Filter(mf.ByGVK(horizontalPodAutoscalerGVK)).Resources()
+	if len(resources) != 1 || len(hpas) != 1 {
+		t.Fatalf("scalingResources() returned %d resources and %d HorizontalPodAutoscalers, want 1", len(resources), len(hpas))
+	}
+
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(hpas[0].Object, hpa); err != nil {
+		t.Fatalf("Failed to convert HorizontalPodAutoscaler: %v", err)
+	}
+	wantHPA := &autoscalingv2.HorizontalPodAutoscaler{
+		TypeMeta: metav1.TypeMeta{APIVersion: "autoscaling/v2", Kind: "HorizontalPodAutoscaler"},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "knative-eventing",
+			Name:      "kafka-channel-receiver",
+			Labels:    map[string]string{scaledWorkloadLabelKey: "kafka-channel-receiver"},
+		},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "kafka-channel-receiver"},
+			MinReplicas:    ptr.Int32(2),
+			MaxReplicas:    5,
+			Metrics: []autoscalingv2.MetricSpec{{
+				Type: autoscalingv2.ResourceMetricSourceType,
+				Resource: &autoscalingv2.ResourceMetricSource{
+					Name:   "cpu",
+					Target: autoscalingv2.MetricTarget{Type: autoscalingv2.UtilizationMetricType, AverageUtilization: ptr.Int32(70)},
+				},
+			}},
+		},
+	}
+	if !cmp.Equal(hpa, wantHPA) {
+		t.Errorf("HorizontalPodAutoscaler = %s", cmp.Diff(wantHPA, hpa))
+	}
+
+	// The replicas of the scaled receiver are left to its autoscaler, the dispatcher keeps its own.
+	manifest = manifest.Append(scalers)
+	setReplicas := func(u *unstructured.Unstructured) error {
+		if u.GetKind() == "Deployment" || u.GetKind() == "StatefulSet" {
+			return unstructured.SetNestedField(u.Object, int64(1), "spec", "replicas")
+		}
+		return nil
+	}
+	transformed, err := manifest.Transform(setReplicas, unsetScaledReplicas(scaledWorkloads(manifest)))
+	if err != nil {
+		t.Fatalf("Transform() = %v", err)
+	}
+	for _, u := range transformed.Filter(mf.Any(mf.ByKind("Deployment"), mf.ByKind("StatefulSet"))).Resources() {
+		_, found, _ := unstructured.NestedFieldNoCopy(u.Object, "spec", "replicas")
+		if want := u.GetName() != "kafka-channel-receiver"; found != want {
+			t.Errorf("%s %s has spec.replicas set: %v, want %v", u.GetKind(), u.GetName(), found, want)
+		}
+	}
+}
+
+func TestConfigureDispatcherScaling(t *testing.T) {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("v1")
+	u.SetKind("ConfigMap")
+	u.SetName(autoscalerConfigMapName)
+	if err := unstructured.SetNestedStringMap(u.Object, map[string]string{
+		"class":         "keda.autoscaling.knative.dev",
+		"min-scale":     "0",
+		"max-scale":     "50",
+		"lag-threshold": "100",
+	}, "data"); err != nil {
+		t.Fatalf("Failed to set data: %v", err)
+	}
+
+	if err := configureDispatcherScaling(u, &v1alpha1.DispatcherScaling{
+		MaxScale:     ptr.Int32(10),
+		LagThreshold: ptr.Int64(20),
+	}); err != nil {
+		t.Fatalf("configureDispatcherScaling() = %v", err)
+	}
+
+	got, _, _ := unstructured.NestedStringMap(u.Object, "data")
+	want := map[string]string{
+		"class":         "keda.autoscaling.knative.dev",
+		"min-scale":     "0",
+		"max-scale":     "10",
+		"lag-threshold": "20",
+	}
+	if !cmp.Equal(got, want) {
+		t.Errorf("data = %s", cmp.Diff(want, got))
+	}
+}
+
+func TestDeleteStaleScalers(t *testing.T) {
+	hpa := func(name string) *autoscalingv2.HorizontalPodAutoscaler {
+		return &autoscalingv2.HorizontalPodAutoscaler{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "knative-eventing",
+			Name:      name,
+			Labels:    map[string]string{scaledWorkloadLabelKey: name},
+		}}
+	}
+	cl := fake.NewClientBuilder().WithObjects(hpa("kafka-broker-receiver"), hpa("kafka-channel-receiver")).Build()
+	r := &ReconcileKnativeKafka{client: cl}
+
+	resources, err := scalingResources(mustManifestFrom(t, "testdata/channel/eventing-kafka-channel.yaml"), &v1alpha1.Scaling{
+		Receiver: &v1alpha1.WorkloadScaling{MaxReplicas: 5, CPUUtilization: ptr.Int32(70)},
+	})
+	if err != nil {
+		t.Fatalf("scalingResources() = %v", err)
+	}
+	manifest := mustManifest(t, resources)
+
+	if err := r.deleteStaleScalers(context.Background())(&manifest, makeCr(withChannelEnabled)); err != nil {
+		t.Fatalf("deleteStaleScalers() = %v", err)
+	}
+
+	remaining := sets.New[string]()
+	for _, name := range []string{"kafka-broker-receiver", "kafka-channel-receiver"} {
+		err := cl.Get(context.Background(), types.NamespacedName{Namespace: "knative-eventing", Name: name}, &autoscalingv2.HorizontalPodAutoscaler{})
+		if err == nil {
+			remaining.Insert(name)
+		} else if !apierrors.IsNotFound(err) {
+			t.Fatalf("Get(%s) = %v", name, err)
+		}
+	}
+	if want := sets.New("kafka-channel-receiver"); !remaining.Equal(want) {
+		t.Errorf("remaining HorizontalPodAutoscalers = %v, want %v", sets.List(remaining), sets.List(want))
+	}
+}
+
+func mustManifest(t *testing.T, resources []unstructured.Unstructured) mf.Manifest {
+	t.Helper()
+	manifest, err := mf.ManifestFrom(mf.Slice(resources))
+	if err != nil {
+		t.Fatalf("Failed to build manifest: %v", err)
+	}
+	return manifest
+}
+
+func mustManifestFrom(t *testing.T, path string) mf.Manifest {
+	t.Helper()
+	manifest, err := mf.ManifestFrom(mf.Path(path))
+	if err != nil {
+		t.Fatalf("Failed to load manifest %s: %v", path, err)
+	}
+	return manifest
+}
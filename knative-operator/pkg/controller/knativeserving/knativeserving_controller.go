@@ -28,6 +28,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"knative.dev/operator/pkg/apis/operator/base"
 	operatorv1beta1 "knative.dev/operator/pkg/apis/operator/v1beta1"
+	servingreconciler "knative.dev/operator/pkg/reconciler/knativeserving"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -296,7 +297,10 @@ func (r *ReconcileKnativeServing) ensureCustomCertsConfigMap(instance *operatorv
 		combinedContents[key] = value
 	}
 
-	combinedCM, err := r.reconcileConfigMap(instance, certs.Name, nil, nil, combinedContents)
+	// The label lets the Knative Serving reconciler pick up the changes of the combined bundle, which
+	// roll the controller making outbound calls through the cluster proxy.
+	combinedCM, err := r.reconcileConfigMap(instance, certs.Name, nil,
+		map[string]string{servingreconciler.SelectorKey: servingreconciler.SelectorValue}, combinedContents)
 	if err != nil {
 		return fmt.Errorf("error reconciling custom certs CM: %w", err)
 	}
@@ -227,6 +227,7 @@ func TestCustomCertsConfigMap(t *testing.T) {
 	serviceCAAnnotations := map[string]string{serviceCAKey: "true"}
 	trustedCALabels := map[string]string{trustedCAKey: "true"}
 	trustedCAAnnotations := map[string]string{trustedCAOwningAnnotationKey: trustedCAOwningAnnotationValue}
+	combinedLabels := map[string]string{"app.kubernetes.io/name": "knative-serving"}
 
 	tests := []struct {
 		name    string
@@ -236,7 +237,7 @@ func TestCustomCertsConfigMap(t *testing.T) {
 	}{{
 		name: "plain field",
 		out: []*corev1.ConfigMap{
-			cm("test-cm", nil, nil, nil, "1"),
+			cm("test-cm", combinedLabels, nil, nil, "1"),
 			cm("test-cm-service-ca", nil, serviceCAAnnotations, nil, "1"),
 			cm("test-cm-trusted-ca", trustedCALabels, trustedCAAnnotations, nil, "1"),
 		},
@@ -247,7 +248,7 @@ func TestCustomCertsConfigMap(t *testing.T) {
 			cm("test-cm", nil, serviceCAAnnotations, map[string]string{"test": "foo"}, "1"),
 		},
 		out: []*corev1.ConfigMap{
-			cm("test-cm", nil, nil, nil, "2"), // TODO: maybe we shouldn't stomp, retaining current behavior though.
+			cm("test-cm", combinedLabels, nil, nil, "2"), // TODO: maybe we shouldn't stomp, retaining current behavior though.
 			cm("test-cm-service-ca", nil, serviceCAAnnotations, nil, "1"),
 			cm("test-cm-trusted-ca", trustedCALabels, trustedCAAnnotations, nil, "1"),
 		},
@@ -261,7 +262,7 @@ func TestCustomCertsConfigMap(t *testing.T) {
 			cm("test-cm-trusted-ca", trustedCALabels, trustedCAAnnotations, map[string]string{"trustedCA": "baz"}, "1"),
 		},
 		out: []*corev1.ConfigMap{
-			cm("test-cm", nil, nil, map[string]string{"trustedCA": "baz"}, "4"),
+			cm("test-cm", combinedLabels, nil, map[string]string{"trustedCA": "baz"}, "4"),
 			cm("test-cm-service-ca", nil, serviceCAAnnotations, nil, "1"),
 			cm("test-cm-trusted-ca", trustedCALabels, trustedCAAnnotations, map[string]string{"trustedCA": "baz"}, "1"),
 		},
@@ -275,7 +276,7 @@ func TestCustomCertsConfigMap(t *testing.T) {
 			cm("test-cm-trusted-ca", trustedCALabels, trustedCAAnnotations, map[string]string{"trustedCA": "baz"}, "1"),
 		},
 		out: []*corev1.ConfigMap{
-			cm("test-cm", nil, nil, map[string]string{"serviceCA": "bar", "trustedCA": "baz"}, "2"),
+			cm("test-cm", combinedLabels, nil, map[string]string{"serviceCA": "bar", "trustedCA": "baz"}, "2"),
 			cm("test-cm-service-ca", nil, serviceCAAnnotations, map[string]string{"serviceCA": "bar"}, "1"),
 			cm("test-cm-trusted-ca", trustedCALabels, trustedCAAnnotations, map[string]string{"trustedCA": "baz"}, "1"),
 		},
@@ -289,7 +290,7 @@ func TestCustomCertsConfigMap(t *testing.T) {
 			cm("test-cm-trusted-ca", trustedCALabels, trustedCAAnnotations, map[string]string{"trustedCA": "baz2"}, "1"),
 		},
 		out: []*corev1.ConfigMap{
-			cm("test-cm", nil, nil, map[string]string{"serviceCA": "bar", "trustedCA": "baz2"}, "101"),
+			cm("test-cm", combinedLabels, nil, map[string]string{"serviceCA": "bar", "trustedCA": "baz2"}, "101"),
 			cm("test-cm-service-ca", nil, serviceCAAnnotations, map[string]string{"serviceCA": "bar"}, "1"),
 			cm("test-cm-trusted-ca", trustedCALabels, trustedCAAnnotations, map[string]string{"trustedCA": "baz2"}, "1"),
 		},
@@ -13,6 +13,21 @@ var (
 		},
 		[]string{"type"},
 	)
+	KnativeKafkaReconcileDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "knative_kafka_reconcile_duration_seconds",
+			Help:    "Duration of the KnativeKafka reconciliations",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"result"},
+	)
+	KnativeKafkaManifestApplies = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "knative_kafka_manifest_applies_total",
+			Help: "Number of KnativeKafka component manifests applied, skipped as unchanged, or failed to apply",
+		},
+		[]string{"component", "result"},
+	)
 	KnativeServingUpG  prometheus.Gauge
 	KnativeEventingUpG prometheus.Gauge
 	KnativeKafkaUpG    prometheus.Gauge
@@ -20,5 +35,5 @@ var (
 
 func init() {
 	// Register custom metrics with the global prometheus registry
-	metrics.Registry.MustRegister(KnativeUp)
+	metrics.Registry.MustRegister(KnativeUp, KnativeKafkaReconcileDuration, KnativeKafkaManifestApplies)
 }
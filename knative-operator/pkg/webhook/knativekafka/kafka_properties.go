@@ -0,0 +1,86 @@
+package knativekafka
+
+import "k8s.io/apimachinery/pkg/util/sets"
+
+// managedProperties are Kafka client properties owned by the data plane or rendered from the
+// auth secrets, overriding them would break the data plane.
+var managedProperties = sets.New[string](
+	"bootstrap.servers",
+	"key.serializer",
+	"value.serializer",
+	"key.deserializer",
+	"value.deserializer",
+	"security.protocol",
+	"sasl.mechanism",
+	"sasl.jaas.config",
+	"ssl.keystore.location",
+	"ssl.keystore.password",
+	"ssl.key.password",
+	"ssl.truststore.location",
+	"ssl.truststore.password",
+	"enable.auto.commit",
+	"group.id",
+	"client.id",
+)
+
+// commonProperties are the properties supported by both Kafka producers and consumers.
+var commonProperties = sets.New[string](
+	"client.dns.lookup",
+	"client.rack",
+	"connections.max.idle.ms",
+	"interceptor.classes",
+	"metadata.max.age.ms",
+	"metric.reporters",
+	"metrics.num.samples",
+	"metrics.recording.level",
+	"metrics.sample.window.ms",
+	"receive.buffer.bytes",
+	"reconnect.backoff.max.ms",
+	"reconnect.backoff.ms",
+	"request.timeout.ms",
+	"retry.backoff.ms",
+	"send.buffer.bytes",
+	"socket.connection.setup.timeout.max.ms",
+	"socket.connection.setup.timeout.ms",
+	"ssl.enabled.protocols",
+	"ssl.endpoint.identification.algorithm",
+	"ssl.protocol",
+)
+
+// producerProperties are the Kafka producer properties that can be overridden.
+var producerProperties = commonProperties.Clone().Insert(
+	"acks",
+	"batch.size",
+	"buffer.memory",
+	"compression.type",
+	"delivery.timeout.ms",
+	"enable.idempotence",
+	"linger.ms",
+	"max.block.ms",
+	"max.in.flight.requests.per.connection",
+	"max.request.size",
+	"partitioner.class",
+	"retries",
+	"transaction.timeout.ms",
+)
+
+// consumerProperties are the Kafka consumer properties that can be overridden.
+var consumerProperties = commonProperties.Clone().Insert(
+	"allow.auto.create.topics",
+	"auto.commit.interval.ms",
+	"auto.offset.reset",
+	"check.crcs",
+	"default.api.timeout.ms",
+	"exclude.internal.topics",
+	"fetch.max.bytes",
+	"fetch.max.wait.ms",
+	"fetch.min.bytes",
+	"group.instance.id",
+	"heartbeat.interval.ms",
+	"isolation.level",
+	"max.partition.fetch.bytes",
+	"max.poll.interval.ms",
+	"max.poll.records",
+	"partition.assignment.strategy",
+	"session.timeout.ms",
+)
@@ -3,11 +3,21 @@ package knativekafka
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 
 	serverlessoperatorv1alpha1 "github.com/openshift-knative/serverless-operator/knative-operator/pkg/apis/operator/v1alpha1"
 	"github.com/openshift-knative/serverless-operator/knative-operator/pkg/common"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"knative.dev/eventing-kafka-broker/control-plane/pkg/kafka"
+	"knative.dev/eventing-kafka-broker/control-plane/pkg/security"
 	operatorv1beta1 "knative.dev/operator/pkg/apis/operator/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -15,15 +25,20 @@ import (
 
 // Validator validates KnativeKafka CR's
 type Validator struct {
-	client  client.Client
-	decoder admission.Decoder
+	client client.Client
+	// secretReader reads the auth secrets from the API server, sparing the operator a cache of
+	// all the secrets of the cluster.
+	secretReader client.Reader
+	decoder      admission.Decoder
 }
 
-// NewValidator creates a new Valicator instance to validate KnativeKafka CRs.
-func NewValidator(client client.Client, decoder admission.Decoder) *Validator {
+// NewValidator creates a new Valicator instance to validate KnativeKafka CRs, reading the
+// referenced auth secrets with the given uncached reader.
+func NewValidator(client client.Client, apiReader client.Reader, decoder admission.Decoder) *Validator {
 	return &Validator{
-		client:  client,
-		decoder: decoder,
+		client:       client,
+		secretReader: apiReader,
+		decoder:      decoder,
 	}
 }
 
@@ -39,15 +54,15 @@ func (v *Validator) Handle(ctx context.Context, req admission.Request) admission
 		return admission.Errored(http.StatusBadRequest, err)
 	}
 
-	allowed, reason, err := v.validate(ctx, ke)
+	allowed, reason, warnings, err := v.validate(ctx, ke)
 	if err != nil {
 		return admission.Errored(http.StatusInternalServerError, err)
 	}
-	return admission.ValidationResponse(allowed, reason)
+	return admission.ValidationResponse(allowed, reason).WithWarnings(warnings...)
 }
 
 // Validator checks for a minimum OpenShift version
-func (v *Validator) validate(ctx context.Context, ke *serverlessoperatorv1alpha1.KnativeKafka) (allowed bool, reason string, err error) {
+func (v *Validator) validate(ctx context.Context, ke *serverlessoperatorv1alpha1.KnativeKafka) (allowed bool, reason string, warnings []string, err error) {
 	log := common.Log.WithName("validate")
 	stages := []func(context.Context, *serverlessoperatorv1alpha1.KnativeKafka) (bool, string, error){
 		v.validateNamespace,
@@ -68,6 +83,16 @@ func (v *Validator) validate(ctx context.Context, ke *serverlessoperatorv1alpha1
 			return
 		}
 	}
+
+	// Auth secrets are validated last as missing secrets only result in warnings.
+	allowed, reason, warnings, err = v.validateAuthSecrets(ctx, ke)
+	if len(reason) > 0 {
+		if err != nil {
+			log.Error(err, reason)
+		} else {
+			log.Info(reason)
+		}
+	}
 	return
 }
 
@@ -96,7 +121,7 @@ func (v *Validator) validateLoneliness(ctx context.Context, ke *serverlessoperat
 
 // validate the shape of the CR
 func (v *Validator) validateShape(_ context.Context, ke *serverlessoperatorv1alpha1.KnativeKafka) (bool, string, error) {
-	if ke.Spec.Channel.Enabled && ke.Spec.Channel.BootstrapServers == "" {
+	if ke.Spec.Channel.Enabled && ke.Spec.Channel.BootstrapServers == "" && ke.Spec.Channel.Cluster == "" {
 		return false, "spec.channel.bootStrapServers is a required detail when spec.channel.enabled is true", nil
 	}
 	if ke.Spec.Channel.AuthSecretName != "" && ke.Spec.Channel.AuthSecretNamespace == "" {
@@ -105,9 +130,333 @@ func (v *Validator) validateShape(_ context.Context, ke *serverlessoperatorv1alp
 	if ke.Spec.Channel.AuthSecretNamespace != "" && ke.Spec.Channel.AuthSecretName == "" {
 		return false, "spec.channel.authSecretName is required when spec.channel.authSecretNamespace is defined", nil
 	}
+	if servers := ke.Spec.Channel.BootstrapServers; servers != "" {
+		if reason := validateBootstrapServers("spec.channel.bootstrapServers", servers); reason != "" {
+			return false, reason, nil
+		}
+	}
+	if servers := ke.Spec.Broker.DefaultConfig.BootstrapServers; servers != "" {
+		if reason := validateBootstrapServers("spec.broker.defaultConfig.bootstrapServers", servers); reason != "" {
+			return false, reason, nil
+		}
+	}
+	if reason := validateTopic(ke.Spec.Broker.DefaultConfig); reason != "" {
+		return false, reason, nil
+	}
+	if reason := validateClusters(ke.Spec); reason != "" {
+		return false, reason, nil
+	}
+	dataPlanes := []struct {
+		path      string
+		cfg       *serverlessoperatorv1alpha1.DataPlaneConfig
+		consuming bool
+	}{
+		{path: "spec.broker.dataPlane", cfg: ke.Spec.Broker.DataPlane, consuming: true},
+		{path: "spec.channel.dataPlane", cfg: ke.Spec.Channel.DataPlane, consuming: true},
+		{path: "spec.source.dataPlane", cfg: ke.Spec.Source.DataPlane, consuming: true},
+		{path: "spec.sink.dataPlane", cfg: ke.Spec.Sink.DataPlane, consuming: false},
+	}
+	for _, dp := range dataPlanes {
+		if reason := validateDataPlane(dp.path, dp.cfg, dp.consuming); reason != "" {
+			return false, reason, nil
+		}
+	}
+	scalings := []struct {
+		path    string
+		scaling *serverlessoperatorv1alpha1.Scaling
+	}{
+		{path: "spec.broker.scaling", scaling: ke.Spec.Broker.Scaling},
+		{path: "spec.channel.scaling", scaling: ke.Spec.Channel.Scaling},
+		{path: "spec.sink.scaling", scaling: ke.Spec.Sink.Scaling},
+	}
+	for _, sc := range scalings {
+		if sc.scaling == nil {
+			continue
+		}
+		if reason := validateWorkloadScaling(sc.path+".receiver", sc.scaling.Receiver); reason != "" {
+			return false, reason, nil
+		}
+	}
+	if reason := validateDispatcherScaling("spec.dispatcherScaling", ke.Spec.DispatcherScaling); reason != "" {
+		return false, reason, nil
+	}
 	return true, "", nil
 }
 
+// validateWorkloadScaling returns the reason why the given workload scaling is invalid, if any.
+func validateWorkloadScaling(path string, ws *serverlessoperatorv1alpha1.WorkloadScaling) string {
+	if ws == nil {
+		return ""
+	}
+	if ws.MaxReplicas < 1 {
+		return fmt.Sprintf("%s.maxReplicas must be at least 1, got %d", path, ws.MaxReplicas)
+	}
+	if m := ws.MinReplicas; m != nil {
+		if *m < 1 {
+			return fmt.Sprintf("%s.minReplicas must be at least 1, got %d", path, *m)
+		}
+		if *m > ws.MaxReplicas {
+			return fmt.Sprintf("%s.minReplicas (%d) must not exceed %s.maxReplicas (%d)", path, *m, path, ws.MaxReplicas)
+		}
+	}
+	if ws.CPUUtilization == nil && ws.MemoryUtilization == nil {
+		return path + " requires at least one of cpuUtilization and memoryUtilization"
+	}
+	if u := ws.CPUUtilization; u != nil && *u < 1 {
+		return fmt.Sprintf("%s.cpuUtilization must be at least 1, got %d", path, *u)
+	}
+	if u := ws.MemoryUtilization; u != nil && *u < 1 {
+		return fmt.Sprintf("%s.memoryUtilization must be at least 1, got %d", path, *u)
+	}
+	return ""
+}
+
+// validateDispatcherScaling returns the reason why the given dispatcher scaling is invalid, if any.
+func validateDispatcherScaling(path string, ds *serverlessoperatorv1alpha1.DispatcherScaling) string {
+	if ds == nil {
+		return ""
+	}
+	if m := ds.MinScale; m != nil && *m < 0 {
+		return fmt.Sprintf("%s.minScale must not be negative, got %d", path, *m)
+	}
+	if m := ds.MaxScale; m != nil {
+		if *m < 1 {
+			return fmt.Sprintf("%s.maxScale must be at least 1, got %d", path, *m)
+		}
+		if ds.MinScale != nil && *ds.MinScale > *m {
+			return fmt.Sprintf("%s.minScale (%d) must not exceed %s.maxScale (%d)", path, *ds.MinScale, path, *m)
+		}
+	}
+	if t := ds.LagThreshold; t != nil && *t < 1 {
+		return fmt.Sprintf("%s.lagThreshold must be at least 1, got %d", path, *t)
+	}
+	return ""
+}
+
+// validateTopic returns the reason why the broker topic configuration is invalid, if any.
+func validateTopic(cfg serverlessoperatorv1alpha1.BrokerDefaultConfig) string {
+	if cfg.Topic == nil {
+		return ""
+	}
+	if r := cfg.Topic.RetentionMillis; r != nil && *r != -1 && *r <= 0 {
+		return fmt.Sprintf("spec.broker.defaultConfig.topic.retentionMillis must be positive or -1, got %d", *r)
+	}
+	if m := cfg.Topic.MinInSyncReplicas; m != nil {
+		if *m < 1 {
+			return fmt.Sprintf("spec.broker.defaultConfig.topic.minInSyncReplicas must be at least 1, got %d", *m)
+		}
+		if cfg.ReplicationFactor > 0 && *m > int32(cfg.ReplicationFactor) {
+			return fmt.Sprintf("spec.broker.defaultConfig.topic.minInSyncReplicas (%d) must not exceed spec.broker.defaultConfig.replicationFactor (%d)", *m, cfg.ReplicationFactor)
+		}
+	}
+	return ""
+}
+
+// validateClusters returns the reason why the Kafka cluster profiles or the references to
+// them are invalid, if any.
+func validateClusters(spec serverlessoperatorv1alpha1.KnativeKafkaSpec) string {
+	names := sets.New[string]()
+	for i, cluster := range spec.Clusters {
+		path := fmt.Sprintf("spec.clusters[%d]", i)
+		if errs := validation.IsDNS1123Label(cluster.Name); len(errs) > 0 {
+			return fmt.Sprintf("%s.name %q is invalid: %s", path, cluster.Name, strings.Join(errs, ", "))
+		}
+		if names.Has(cluster.Name) {
+			return fmt.Sprintf("%s.name %q is already used by another cluster profile", path, cluster.Name)
+		}
+		names.Insert(cluster.Name)
+		if cluster.BootstrapServers == "" {
+			return path + ".bootstrapServers is required"
+		}
+		if reason := validateBootstrapServers(path+".bootstrapServers", cluster.BootstrapServers); reason != "" {
+			return reason
+		}
+	}
+
+	refs := []struct {
+		path      string
+		cluster   string
+		exclusive map[string]string
+	}{{
+		path:    "spec.broker.defaultConfig.cluster",
+		cluster: spec.Broker.DefaultConfig.Cluster,
+		exclusive: map[string]string{
+			"spec.broker.defaultConfig.bootstrapServers": spec.Broker.DefaultConfig.BootstrapServers,
+			"spec.broker.defaultConfig.authSecretName":   spec.Broker.DefaultConfig.AuthSecretName,
+		},
+	}, {
+		path:    "spec.channel.cluster",
+		cluster: spec.Channel.Cluster,
+		exclusive: map[string]string{
+			"spec.channel.bootstrapServers":    spec.Channel.BootstrapServers,
+			"spec.channel.authSecretNamespace": spec.Channel.AuthSecretNamespace,
+			"spec.channel.authSecretName":      spec.Channel.AuthSecretName,
+		},
+	}, {
+		path:    "spec.sink.cluster",
+		cluster: spec.Sink.Cluster,
+	}}
+	for _, ref := range refs {
+		if ref.cluster == "" {
+			continue
+		}
+		if !names.Has(ref.cluster) {
+			return fmt.Sprintf("%s references the cluster profile %q which is not defined in spec.clusters", ref.path, ref.cluster)
+		}
+		for _, path := range sets.List(sets.KeySet(ref.exclusive)) {
+			if ref.exclusive[path] != "" {
+				return fmt.Sprintf("%s and %s are mutually exclusive", ref.path, path)
+			}
+		}
+	}
+	return ""
+}
+
+// validateDataPlane returns the reason why the given data plane configuration is invalid, if any.
+func validateDataPlane(path string, cfg *serverlessoperatorv1alpha1.DataPlaneConfig, consuming bool) string {
+	if cfg == nil {
+		return ""
+	}
+	if reason := validateProperties(path+".producer", cfg.Producer, producerProperties); reason != "" {
+		return reason
+	}
+	if !consuming && len(cfg.Consumer) > 0 {
+		return path + ".consumer is not supported"
+	}
+	if reason := validateProperties(path+".consumer", cfg.Consumer, consumerProperties); reason != "" {
+		return reason
+	}
+	if cfg.Dispatcher == nil {
+		return ""
+	}
+	if !consuming {
+		return path + ".dispatcher is not supported"
+	}
+	if _, ok := cfg.Consumer["max.poll.records"]; ok && cfg.Dispatcher.MaxPollRecords != nil {
+		return fmt.Sprintf("%s.consumer[max.poll.records] and %s.dispatcher.maxPollRecords are mutually exclusive", path, path)
+	}
+	if n := cfg.Dispatcher.MaxPollRecords; n != nil && *n < 1 {
+		return fmt.Sprintf("%s.dispatcher.maxPollRecords must be at least 1, got %d", path, *n)
+	}
+	if n := cfg.Dispatcher.MaxPoolSize; n != nil && *n < 1 {
+		return fmt.Sprintf("%s.dispatcher.maxPoolSize must be at least 1, got %d", path, *n)
+	}
+	switch order := cfg.Dispatcher.DeliveryOrder; order {
+	case "", serverlessoperatorv1alpha1.DeliveryOrderOrdered, serverlessoperatorv1alpha1.DeliveryOrderUnordered:
+	default:
+		return fmt.Sprintf("%s.dispatcher.deliveryOrder must be %q or %q, got %q", path,
+			serverlessoperatorv1alpha1.DeliveryOrderOrdered, serverlessoperatorv1alpha1.DeliveryOrderUnordered, order)
+	}
+	return ""
+}
+
+// validateProperties returns the reason why the given Kafka client properties are invalid, if any.
+func validateProperties(path string, properties map[string]string, known sets.Set[string]) string {
+	for _, key := range sets.List(sets.KeySet(properties)) {
+		switch {
+		case managedProperties.Has(key):
+			return fmt.Sprintf("%s[%s] is managed by the operator and cannot be overridden", path, key)
+		case !known.Has(key):
+			return fmt.Sprintf("%s[%s] is not a supported property", path, key)
+		case strings.TrimSpace(properties[key]) == "" || strings.ContainsAny(properties[key], "\n\r"):
+			return fmt.Sprintf("%s[%s] must be a non-empty single line value", path, key)
+		}
+	}
+	return ""
+}
+
+// validateBootstrapServers returns the reason why the given comma-separated list of
+// bootstrap servers is invalid, if any.
+func validateBootstrapServers(path string, servers string) string {
+	for _, server := range strings.Split(servers, ",") {
+		server = strings.TrimSpace(server)
+		if server == "" {
+			return fmt.Sprintf("%s must not contain empty entries, got %q", path, servers)
+		}
+		if strings.Contains(server, "://") {
+			return fmt.Sprintf("%s entries must be host:port pairs without a scheme, got %q", path, server)
+		}
+		host, port, err := net.SplitHostPort(server)
+		if err != nil {
+			return fmt.Sprintf("%s entries must be host:port pairs, got %q: %v", path, server, err)
+		}
+		if host == "" {
+			return fmt.Sprintf("%s entries must have a host, got %q", path, server)
+		}
+		if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+			return fmt.Sprintf("%s entries must have a port between 1 and 65535, got %q", path, server)
+		}
+	}
+	return ""
+}
+
+// authSecretRef is a reference to a Kafka auth secret in the KnativeKafka spec.
+type authSecretRef struct {
+	path string
+	key  types.NamespacedName
+}
+
+// authSecretRefs returns the auth secrets referenced by the enabled components.
+func authSecretRefs(ke *serverlessoperatorv1alpha1.KnativeKafka) []authSecretRef {
+	var refs []authSecretRef
+	if ke.Spec.Broker.Enabled && ke.Spec.Broker.DefaultConfig.AuthSecretName != "" {
+		// The broker config lives next to the data plane, so does its secret.
+		refs = append(refs, authSecretRef{
+			path: "spec.broker.defaultConfig.authSecretName",
+			key:  types.NamespacedName{Namespace: ke.Namespace, Name: ke.Spec.Broker.DefaultConfig.AuthSecretName},
+		})
+	}
+	if ke.Spec.Channel.Enabled && ke.Spec.Channel.AuthSecretName != "" {
+		refs = append(refs, authSecretRef{
+			path: "spec.channel.authSecretName",
+			key:  types.NamespacedName{Namespace: ke.Spec.Channel.AuthSecretNamespace, Name: ke.Spec.Channel.AuthSecretName},
+		})
+	}
+	for i, cluster := range ke.Spec.Clusters {
+		if cluster.AuthSecretName != "" {
+			refs = append(refs, authSecretRef{
+				path: fmt.Sprintf("spec.clusters[%d].authSecretName", i),
+				key:  types.NamespacedName{Namespace: ke.Namespace, Name: cluster.AuthSecretName},
+			})
+		}
+	}
+	return refs
+}
+
+// validate that the referenced auth secrets have a coherent set of keys for their protocol,
+// missing secrets are reported as warnings as they might be created after the KnativeKafka.
+func (v *Validator) validateAuthSecrets(ctx context.Context, ke *serverlessoperatorv1alpha1.KnativeKafka) (bool, string, []string, error) {
+	var warnings []string
+	for _, ref := range authSecretRefs(ke) {
+		secret := &corev1.Secret{}
+		if err := v.secretReader.Get(ctx, ref.key, secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				warnings = append(warnings, fmt.Sprintf("%s references the secret %s which does not exist, Kafka clients will fail to connect until it is created", ref.path, ref.key))
+				continue
+			}
+			return false, fmt.Sprintf("Unable to get secret %s", ref.key), nil, err
+		}
+		if err := validateAuthSecret(secret); err != nil {
+			return false, fmt.Sprintf("%s references the invalid secret %s: %v", ref.path, ref.key, err), nil, nil
+		}
+	}
+	return true, "", warnings, nil
+}
+
+// validateAuthSecret checks the secret the same way the Kafka control plane does when
+// building its clients.
+func validateAuthSecret(secret *corev1.Secret) error {
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	opt, err := security.NewSaramaSecurityOptionFromSecret(secret)
+	if err != nil {
+		return err
+	}
+	_, err = kafka.GetSaramaConfig(opt)
+	return err
+}
+
 // validate that KnativeEventing is installed as a hard dep
 func (v *Validator) validateDependencies(ctx context.Context, ke *serverlessoperatorv1alpha1.KnativeKafka) (bool, string, error) {
 	// skip check if in deletion phase as Eventing maybe already deleted
@@ -8,9 +8,12 @@ import (
 	"github.com/openshift-knative/serverless-operator/knative-operator/pkg/apis"
 	serverlessoperatorv1alpha1 "github.com/openshift-knative/serverless-operator/knative-operator/pkg/apis/operator/v1alpha1"
 	"github.com/openshift-knative/serverless-operator/knative-operator/pkg/webhook/testutil"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
 	operatorv1beta1 "knative.dev/operator/pkg/apis/operator/v1beta1"
+	"knative.dev/pkg/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
@@ -128,7 +131,7 @@ func TestHappy(t *testing.T) {
 	os.Clearenv()
 	os.Setenv("REQUIRED_KAFKA_NAMESPACE", "knative-eventing")
 
-	validator := NewValidator(
+	validator := newValidator(
 		fake.NewClientBuilder().WithObjects(validKnativeEventingCR).Build(),
 		decoder)
 
@@ -147,7 +150,7 @@ func TestInvalidNamespace(t *testing.T) {
 	os.Clearenv()
 	os.Setenv("REQUIRED_KAFKA_NAMESPACE", "knative-eventing")
 
-	validator := NewValidator(
+	validator := newValidator(
 		fake.NewClientBuilder().WithObjects(validKnativeEventingCR).Build(),
 		decoder)
 
@@ -166,7 +169,7 @@ func TestLoneliness(t *testing.T) {
 	os.Clearenv()
 	os.Setenv("REQUIRED_KAFKA_NAMESPACE", "knative-eventing")
 
-	validator := NewValidator(
+	validator := newValidator(
 		fake.NewClientBuilder().WithObjects(duplicateCR, validKnativeEventingCR).Build(),
 		decoder)
 
@@ -185,7 +188,7 @@ func TestInvalidShape(t *testing.T) {
 	os.Clearenv()
 	os.Setenv("REQUIRED_KAFKA_NAMESPACE", "knative-eventing")
 
-	validator := NewValidator(
+	validator := newValidator(
 		fake.NewClientBuilder().WithObjects(duplicateCR, validKnativeEventingCR).Build(),
 		decoder)
 
@@ -206,7 +209,7 @@ func TestValidateDeps(t *testing.T) {
 	os.Clearenv()
 	os.Setenv("REQUIRED_KAFKA_NAMESPACE", "knative-eventing")
 
-	validator := NewValidator(fake.NewClientBuilder().Build(), decoder)
+	validator := newValidator(fake.NewClientBuilder().Build(), decoder)
 
 	req, err := testutil.RequestFor(defaultCR)
 	if err != nil {
@@ -218,3 +221,539 @@ func TestValidateDeps(t *testing.T) {
 		t.Error("No KnativeEventing instance install, but request allowed")
 	}
 }
+
+func TestTuningShape(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("REQUIRED_KAFKA_NAMESPACE", "knative-eventing")
+
+	tests := []struct {
+		name    string
+		spec    serverlessoperatorv1alpha1.KnativeKafkaSpec
+		allowed bool
+	}{{
+		name: "valid tuning",
+		spec: serverlessoperatorv1alpha1.KnativeKafkaSpec{
+			Broker: serverlessoperatorv1alpha1.Broker{
+				DefaultConfig: serverlessoperatorv1alpha1.BrokerDefaultConfig{
+					ReplicationFactor: 3,
+					Topic: &serverlessoperatorv1alpha1.TopicConfig{
+						RetentionMillis:   ptr.Int64(-1),
+						MinInSyncReplicas: ptr.Int32(2),
+					},
+				},
+				DataPlane: &serverlessoperatorv1alpha1.DataPlaneConfig{
+					Producer:   map[string]string{"linger.ms": "5"},
+					Consumer:   map[string]string{"fetch.min.bytes": "1024"},
+					Dispatcher: &serverlessoperatorv1alpha1.DispatcherConfig{MaxPollRecords: ptr.Int32(100)},
+				},
+			},
+			Sink: serverlessoperatorv1alpha1.Sink{
+				DataPlane: &serverlessoperatorv1alpha1.DataPlaneConfig{
+					Producer: map[string]string{"compression.type": "lz4"},
+				},
+			},
+		},
+		allowed: true,
+	}, {
+		name: "invalid retention",
+		spec: serverlessoperatorv1alpha1.KnativeKafkaSpec{
+			Broker: serverlessoperatorv1alpha1.Broker{
+				DefaultConfig: serverlessoperatorv1alpha1.BrokerDefaultConfig{
+					Topic: &serverlessoperatorv1alpha1.TopicConfig{RetentionMillis: ptr.Int64(0)},
+				},
+			},
+		},
+	}, {
+		name: "min.insync.replicas exceeds the replication factor",
+		spec: serverlessoperatorv1alpha1.KnativeKafkaSpec{
+			Broker: serverlessoperatorv1alpha1.Broker{
+				DefaultConfig: serverlessoperatorv1alpha1.BrokerDefaultConfig{
+					ReplicationFactor: 1,
+					Topic:             &serverlessoperatorv1alpha1.TopicConfig{MinInSyncReplicas: ptr.Int32(2)},
+				},
+			},
+		},
+	}, {
+		name: "typo in producer property",
+		spec: serverlessoperatorv1alpha1.KnativeKafkaSpec{
+			Channel: serverlessoperatorv1alpha1.Channel{
+				DataPlane: &serverlessoperatorv1alpha1.DataPlaneConfig{
+					Producer: map[string]string{"linger.msx": "5"},
+				},
+			},
+		},
+	}, {
+		name: "consumer property used for the producer",
+		spec: serverlessoperatorv1alpha1.KnativeKafkaSpec{
+			Source: serverlessoperatorv1alpha1.Source{
+				DataPlane: &serverlessoperatorv1alpha1.DataPlaneConfig{
+					Producer: map[string]string{"fetch.min.bytes": "1024"},
+				},
+			},
+		},
+	}, {
+		name: "managed property",
+		spec: serverlessoperatorv1alpha1.KnativeKafkaSpec{
+			Broker: serverlessoperatorv1alpha1.Broker{
+				DataPlane: &serverlessoperatorv1alpha1.DataPlaneConfig{
+					Consumer: map[string]string{"value.deserializer": "foo"},
+				},
+			},
+		},
+	}, {
+		name: "empty property value",
+		spec: serverlessoperatorv1alpha1.KnativeKafkaSpec{
+			Broker: serverlessoperatorv1alpha1.Broker{
+				DataPlane: &serverlessoperatorv1alpha1.DataPlaneConfig{
+					Producer: map[string]string{"acks": " "},
+				},
+			},
+		},
+	}, {
+		name: "consumer configuration for the sink",
+		spec: serverlessoperatorv1alpha1.KnativeKafkaSpec{
+			Sink: serverlessoperatorv1alpha1.Sink{
+				DataPlane: &serverlessoperatorv1alpha1.DataPlaneConfig{
+					Consumer: map[string]string{"fetch.min.bytes": "1024"},
+				},
+			},
+		},
+	}, {
+		name: "dispatcher configuration for the sink",
+		spec: serverlessoperatorv1alpha1.KnativeKafkaSpec{
+			Sink: serverlessoperatorv1alpha1.Sink{
+				DataPlane: &serverlessoperatorv1alpha1.DataPlaneConfig{
+					Dispatcher: &serverlessoperatorv1alpha1.DispatcherConfig{MaxPoolSize: ptr.Int32(10)},
+				},
+			},
+		},
+	}, {
+		name: "conflicting max.poll.records",
+		spec: serverlessoperatorv1alpha1.KnativeKafkaSpec{
+			Broker: serverlessoperatorv1alpha1.Broker{
+				DataPlane: &serverlessoperatorv1alpha1.DataPlaneConfig{
+					Consumer:   map[string]string{"max.poll.records": "10"},
+					Dispatcher: &serverlessoperatorv1alpha1.DispatcherConfig{MaxPollRecords: ptr.Int32(100)},
+				},
+			},
+		},
+	}, {
+		name: "invalid dispatcher pool size",
+		spec: serverlessoperatorv1alpha1.KnativeKafkaSpec{
+			Source: serverlessoperatorv1alpha1.Source{
+				DataPlane: &serverlessoperatorv1alpha1.DataPlaneConfig{
+					Dispatcher: &serverlessoperatorv1alpha1.DispatcherConfig{MaxPoolSize: ptr.Int32(0)},
+				},
+			},
+		},
+	}, {
+		name:    "delivery order",
+		allowed: true,
+		spec: serverlessoperatorv1alpha1.KnativeKafkaSpec{
+			Channel: serverlessoperatorv1alpha1.Channel{
+				DataPlane: &serverlessoperatorv1alpha1.DataPlaneConfig{
+					Dispatcher: &serverlessoperatorv1alpha1.DispatcherConfig{DeliveryOrder: serverlessoperatorv1alpha1.DeliveryOrderUnordered},
+				},
+			},
+		},
+	}, {
+		name: "invalid delivery order",
+		spec: serverlessoperatorv1alpha1.KnativeKafkaSpec{
+			Broker: serverlessoperatorv1alpha1.Broker{
+				DataPlane: &serverlessoperatorv1alpha1.DataPlaneConfig{
+					Dispatcher: &serverlessoperatorv1alpha1.DispatcherConfig{DeliveryOrder: "Ordered"},
+				},
+			},
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			validator := newValidator(
+				fake.NewClientBuilder().WithObjects(validKnativeEventingCR).Build(),
+				decoder)
+
+			cr := defaultCR.DeepCopy()
+			cr.Spec = test.spec
+
+			req, err := testutil.RequestFor(cr)
+			if err != nil {
+				t.Fatalf("Failed to generate a request for %v: %v", cr, err)
+			}
+
+			result := validator.Handle(context.Background(), req)
+			if result.Allowed != test.allowed {
+				t.Errorf("Allowed = %v, want: %v (%v)", result.Allowed, test.allowed, result.Result)
+			}
+		})
+	}
+}
+
+func TestBootstrapServers(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("REQUIRED_KAFKA_NAMESPACE", "knative-eventing")
+
+	tests := []struct {
+		name    string
+		servers string
+		allowed bool
+	}{{
+		name:    "single server",
+		servers: "my-cluster-kafka-bootstrap.kafka:9092",
+		allowed: true,
+	}, {
+		name:    "multiple servers",
+		servers: "broker-0.kafka:9093, broker-1.kafka:9093,[::1]:9093",
+		allowed: true,
+	}, {
+		name:    "missing port",
+		servers: "my-cluster-kafka-bootstrap.kafka",
+	}, {
+		name:    "scheme",
+		servers: "PLAINTEXT://my-cluster-kafka-bootstrap.kafka:9092",
+	}, {
+		name:    "invalid port",
+		servers: "my-cluster-kafka-bootstrap.kafka:90920",
+	}, {
+		name:    "missing host",
+		servers: ":9092",
+	}, {
+		name:    "empty entry",
+		servers: "broker-0.kafka:9093,,broker-1.kafka:9093",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			validator := newValidator(
+				fake.NewClientBuilder().WithObjects(validKnativeEventingCR).Build(),
+				decoder)
+
+			for _, cr := range []*serverlessoperatorv1alpha1.KnativeKafka{
+				{ObjectMeta: defaultCR.ObjectMeta, Spec: serverlessoperatorv1alpha1.KnativeKafkaSpec{
+					Channel: serverlessoperatorv1alpha1.Channel{Enabled: true, BootstrapServers: test.servers},
+				}},
+				{ObjectMeta: defaultCR.ObjectMeta, Spec: serverlessoperatorv1alpha1.KnativeKafkaSpec{
+					Broker: serverlessoperatorv1alpha1.Broker{Enabled: true, DefaultConfig: serverlessoperatorv1alpha1.BrokerDefaultConfig{BootstrapServers: test.servers}},
+				}},
+			} {
+				req, err := testutil.RequestFor(cr)
+				if err != nil {
+					t.Fatalf("Failed to generate a request for %v: %v", cr, err)
+				}
+
+				result := validator.Handle(context.Background(), req)
+				if result.Allowed != test.allowed {
+					t.Errorf("Allowed = %v, want: %v (%v)", result.Allowed, test.allowed, result.Result)
+				}
+			}
+		})
+	}
+}
+
+func TestAuthSecrets(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("REQUIRED_KAFKA_NAMESPACE", "knative-eventing")
+
+	secret := func(data map[string]string) *corev1.Secret {
+		s := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "knative-eventing", Name: "my-secret"},
+			Data:       map[string][]byte{},
+		}
+		for k, v := range data {
+			s.Data[k] = []byte(v)
+		}
+		return s
+	}
+	broker := &serverlessoperatorv1alpha1.KnativeKafka{
+		ObjectMeta: defaultCR.ObjectMeta,
+		Spec: serverlessoperatorv1alpha1.KnativeKafkaSpec{
+			Broker: serverlessoperatorv1alpha1.Broker{
+				Enabled: true,
+				DefaultConfig: serverlessoperatorv1alpha1.BrokerDefaultConfig{
+					BootstrapServers: "my-cluster-kafka-bootstrap.kafka:9092",
+					AuthSecretName:   "my-secret",
+				},
+			},
+		},
+	}
+	channel := &serverlessoperatorv1alpha1.KnativeKafka{
+		ObjectMeta: defaultCR.ObjectMeta,
+		Spec: serverlessoperatorv1alpha1.KnativeKafkaSpec{
+			Channel: serverlessoperatorv1alpha1.Channel{
+				Enabled:             true,
+				BootstrapServers:    "my-cluster-kafka-bootstrap.kafka:9092",
+				AuthSecretName:      "my-secret",
+				AuthSecretNamespace: "knative-eventing",
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		cr       *serverlessoperatorv1alpha1.KnativeKafka
+		secret   *corev1.Secret
+		allowed  bool
+		warnings int
+	}{{
+		name:     "missing secret",
+		cr:       broker,
+		allowed:  true,
+		warnings: 1,
+	}, {
+		name:    "plaintext",
+		cr:      broker,
+		secret:  secret(map[string]string{"protocol": "PLAINTEXT"}),
+		allowed: true,
+	}, {
+		name: "SASL with user and password",
+		cr:   broker,
+		secret: secret(map[string]string{
+			"protocol":       "SASL_PLAINTEXT",
+			"sasl.mechanism": "SCRAM-SHA-512",
+			"user":           "user",
+			"password":       "password",
+		}),
+		allowed: true,
+	}, {
+		name: "SASL without password",
+		cr:   broker,
+		secret: secret(map[string]string{
+			"protocol": "SASL_PLAINTEXT",
+			"user":     "user",
+		}),
+	}, {
+		name: "SASL with unsupported mechanism",
+		cr:   broker,
+		secret: secret(map[string]string{
+			"protocol":       "SASL_PLAINTEXT",
+			"sasl.mechanism": "GSSAPI",
+			"user":           "user",
+			"password":       "password",
+		}),
+	}, {
+		name:   "unsupported protocol",
+		cr:     broker,
+		secret: secret(map[string]string{"protocol": "TLS"}),
+	}, {
+		name: "SSL without client certificate",
+		cr:   channel,
+		secret: secret(map[string]string{
+			"protocol": "SSL",
+		}),
+	}, {
+		name: "SSL with client auth disabled",
+		cr:   channel,
+		secret: secret(map[string]string{
+			"protocol":  "SSL",
+			"user.skip": "true",
+		}),
+		allowed: true,
+	}, {
+		name: "SSL with an invalid CA certificate",
+		cr:   channel,
+		secret: secret(map[string]string{
+			"protocol":  "SSL",
+			"user.skip": "true",
+			"ca.crt":    "not a certificate",
+		}),
+	}, {
+		name: "legacy SASL secret",
+		cr:   channel,
+		secret: secret(map[string]string{
+			"saslType": "PLAIN",
+			"username": "user",
+			"password": "password",
+		}),
+		allowed: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithObjects(validKnativeEventingCR)
+			if test.secret != nil {
+				builder = builder.WithObjects(test.secret)
+			}
+			validator := newValidator(builder.Build(), decoder)
+
+			req, err := testutil.RequestFor(test.cr)
+			if err != nil {
+				t.Fatalf("Failed to generate a request for %v: %v", test.cr, err)
+			}
+
+			result := validator.Handle(context.Background(), req)
+			if result.Allowed != test.allowed {
+				t.Errorf("Allowed = %v, want: %v (%v)", result.Allowed, test.allowed, result.Result)
+			}
+			if len(result.Warnings) != test.warnings {
+				t.Errorf("Warnings = %v, want %d warnings", result.Warnings, test.warnings)
+			}
+		})
+	}
+}
+
+func TestClusters(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("REQUIRED_KAFKA_NAMESPACE", "knative-eventing")
+
+	tenantSecret := func(protocol string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "knative-eventing", Name: "tenant-auth"},
+			Data: map[string][]byte{
+				"protocol":  []byte(protocol),
+				"user.skip": []byte("true"),
+			},
+		}
+	}
+	profiles := func(clusters ...serverlessoperatorv1alpha1.KafkaCluster) serverlessoperatorv1alpha1.KnativeKafkaSpec {
+		return serverlessoperatorv1alpha1.KnativeKafkaSpec{Clusters: clusters}
+	}
+	tenantA := serverlessoperatorv1alpha1.KafkaCluster{Name: "tenant-a", BootstrapServers: "a.kafka:9092"}
+
+	tests := []struct {
+		name    string
+		spec    serverlessoperatorv1alpha1.KnativeKafkaSpec
+		secret  *corev1.Secret
+		allowed bool
+	}{{
+		name:    "profiles referenced by the broker, channel and sink",
+		allowed: true,
+		spec: serverlessoperatorv1alpha1.KnativeKafkaSpec{
+			Broker:   serverlessoperatorv1alpha1.Broker{Enabled: true, DefaultConfig: serverlessoperatorv1alpha1.BrokerDefaultConfig{Cluster: "tenant-a"}},
+			Channel:  serverlessoperatorv1alpha1.Channel{Enabled: true, Cluster: "tenant-a"},
+			Sink:     serverlessoperatorv1alpha1.Sink{Enabled: true, Cluster: "tenant-a"},
+			Clusters: []serverlessoperatorv1alpha1.KafkaCluster{tenantA},
+		},
+	}, {
+		name: "invalid name",
+		spec: profiles(serverlessoperatorv1alpha1.KafkaCluster{Name: "Tenant_A", BootstrapServers: "a.kafka:9092"}),
+	}, {
+		name: "duplicate name",
+		spec: profiles(tenantA, tenantA),
+	}, {
+		name: "missing bootstrap servers",
+		spec: profiles(serverlessoperatorv1alpha1.KafkaCluster{Name: "tenant-a"}),
+	}, {
+		name: "invalid bootstrap servers",
+		spec: profiles(serverlessoperatorv1alpha1.KafkaCluster{Name: "tenant-a", BootstrapServers: "a.kafka"}),
+	}, {
+		name: "undefined profile",
+		spec: serverlessoperatorv1alpha1.KnativeKafkaSpec{
+			Sink: serverlessoperatorv1alpha1.Sink{Enabled: true, Cluster: "tenant-b"},
+		},
+	}, {
+		name: "profile and bootstrap servers",
+		spec: serverlessoperatorv1alpha1.KnativeKafkaSpec{
+			Channel:  serverlessoperatorv1alpha1.Channel{Enabled: true, Cluster: "tenant-a", BootstrapServers: "b.kafka:9092"},
+			Clusters: []serverlessoperatorv1alpha1.KafkaCluster{tenantA},
+		},
+	}, {
+		name:    "profile with auth secret",
+		secret:  tenantSecret("SSL"),
+		allowed: true,
+		spec: profiles(serverlessoperatorv1alpha1.KafkaCluster{
+			Name: "tenant-a", BootstrapServers: "a.kafka:9093", AuthSecretName: "tenant-auth",
+		}),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithObjects(validKnativeEventingCR)
+			if test.secret != nil {
+				builder = builder.WithObjects(test.secret)
+			}
+			validator := newValidator(builder.Build(), decoder)
+
+			cr := &serverlessoperatorv1alpha1.KnativeKafka{ObjectMeta: defaultCR.ObjectMeta, Spec: test.spec}
+			req, err := testutil.RequestFor(cr)
+			if err != nil {
+				t.Fatalf("Failed to generate a request for %v: %v", cr, err)
+			}
+
+			result := validator.Handle(context.Background(), req)
+			if result.Allowed != test.allowed {
+				t.Errorf("Allowed = %v, want: %v (%v)", result.Allowed, test.allowed, result.Result)
+			}
+		})
+	}
+}
+
+func TestScaling(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("REQUIRED_KAFKA_NAMESPACE", "knative-eventing")
+
+	broker := func(scaling *serverlessoperatorv1alpha1.Scaling) serverlessoperatorv1alpha1.KnativeKafkaSpec {
+		return serverlessoperatorv1alpha1.KnativeKafkaSpec{Broker: serverlessoperatorv1alpha1.Broker{
+			Enabled:       true,
+			DefaultConfig: serverlessoperatorv1alpha1.BrokerDefaultConfig{BootstrapServers: "my-cluster-kafka-bootstrap.kafka:9092"},
+			Scaling:       scaling,
+		}}
+	}
+
+	tests := []struct {
+		name    string
+		spec    serverlessoperatorv1alpha1.KnativeKafkaSpec
+		allowed bool
+	}{{
+		name:    "receiver on CPU",
+		allowed: true,
+		spec: broker(&serverlessoperatorv1alpha1.Scaling{
+			Receiver: &serverlessoperatorv1alpha1.WorkloadScaling{MaxReplicas: 5, CPUUtilization: ptr.Int32(70)},
+		}),
+	}, {
+		name: "no metric",
+		spec: broker(&serverlessoperatorv1alpha1.Scaling{
+			Receiver: &serverlessoperatorv1alpha1.WorkloadScaling{MaxReplicas: 5},
+		}),
+	}, {
+		name: "min replicas above max replicas",
+		spec: broker(&serverlessoperatorv1alpha1.Scaling{
+			Receiver: &serverlessoperatorv1alpha1.WorkloadScaling{MinReplicas: ptr.Int32(6), MaxReplicas: 5, CPUUtilization: ptr.Int32(70)},
+		}),
+	}, {
+		name: "receiver scaled to zero",
+		spec: broker(&serverlessoperatorv1alpha1.Scaling{
+			Receiver: &serverlessoperatorv1alpha1.WorkloadScaling{MinReplicas: ptr.Int32(0), MaxReplicas: 5, CPUUtilization: ptr.Int32(70)},
+		}),
+	}, {
+		name:    "dispatchers",
+		allowed: true,
+		spec: serverlessoperatorv1alpha1.KnativeKafkaSpec{DispatcherScaling: &serverlessoperatorv1alpha1.DispatcherScaling{
+			MinScale:     ptr.Int32(0),
+			MaxScale:     ptr.Int32(10),
+			LagThreshold: ptr.Int64(20),
+		}},
+	}, {
+		name: "dispatchers min scale above max scale",
+		spec: serverlessoperatorv1alpha1.KnativeKafkaSpec{DispatcherScaling: &serverlessoperatorv1alpha1.DispatcherScaling{
+			MinScale: ptr.Int32(11),
+			MaxScale: ptr.Int32(10),
+		}},
+	}, {
+		name: "dispatchers lag threshold",
+		spec: serverlessoperatorv1alpha1.KnativeKafkaSpec{DispatcherScaling: &serverlessoperatorv1alpha1.DispatcherScaling{
+			LagThreshold: ptr.Int64(0),
+		}},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			validator := newValidator(fake.NewClientBuilder().WithObjects(validKnativeEventingCR).Build(), decoder)
+
+			cr := &serverlessoperatorv1alpha1.KnativeKafka{ObjectMeta: defaultCR.ObjectMeta, Spec: test.spec}
+			req, err := testutil.RequestFor(cr)
+			if err != nil {
+				t.Fatalf("Failed to generate a request for %v: %v", cr, err)
+			}
+
+			result := validator.Handle(context.Background(), req)
+			if result.Allowed != test.allowed {
+				t.Errorf("Allowed = %v, want: %v (%v)", result.Allowed, test.allowed, result.Result)
+			}
+		})
+	}
+}
+
+// newValidator creates a Validator reading the auth secrets through the given fake client.
+func newValidator(client client.Client, decoder admission.Decoder) *Validator {
+	return NewValidator(client, client, decoder)
+}
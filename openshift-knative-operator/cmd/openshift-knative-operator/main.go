@@ -20,6 +20,8 @@ import (
 	"knative.dev/pkg/webhook/resourcesemantics/conversion"
 
 	"github.com/openshift-knative/serverless-operator/knative-operator/pkg/apis"
+	serverlessoperatorv1alpha1 "github.com/openshift-knative/serverless-operator/knative-operator/pkg/apis/operator/v1alpha1"
+	serverlessoperatorv1beta1 "github.com/openshift-knative/serverless-operator/knative-operator/pkg/apis/operator/v1beta1"
 	"github.com/openshift-knative/serverless-operator/openshift-knative-operator/pkg/eventing"
 	"github.com/openshift-knative/serverless-operator/openshift-knative-operator/pkg/serving"
 
@@ -55,6 +57,7 @@ func main() {
 }
 
 func newConversionController(ctx context.Context, _ configmap.Watcher) *controller.Impl {
+	var v1alpha1 = serverlessoperatorv1alpha1.SchemeGroupVersion.Version
 	var v1beta1 = operatorv1beta1.SchemeGroupVersion.Version
 
 	return conversion.NewConversionController(ctx,
@@ -77,6 +80,14 @@ func newConversionController(ctx context.Context, _ configmap.Watcher) *controll
 					v1beta1: &operatorv1beta1.KnativeEventing{},
 				},
 			},
+			serverlessoperatorv1beta1.Kind("KnativeKafka"): {
+				DefinitionName: "knativekafkas." + serverlessoperatorv1beta1.SchemeGroupVersion.Group,
+				HubVersion:     v1beta1,
+				Zygotes: map[string]conversion.ConvertibleObject{
+					v1alpha1: &serverlessoperatorv1alpha1.KnativeKafka{},
+					v1beta1:  &serverlessoperatorv1beta1.KnativeKafka{},
+				},
+			},
 		},
 
 		// A function that infuses the context passed to ConvertTo/ConvertFrom/SetDefaults with custom metadata.
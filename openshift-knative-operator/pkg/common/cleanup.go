@@ -0,0 +1,200 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+	"knative.dev/operator/pkg/apis/operator/base"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+)
+
+// CompletedCleanupsAnnotation is the status annotation of a Knative component listing the IDs of
+// the legacy resource cleanups completed for it, as "id,...".
+const CompletedCleanupsAnnotation = "serverless.openshift.io/completed-cleanups"
+
+// Cleanup deletes the legacy resources left over by a former operator version, e.g. the
+// Deployments of a removed feature.
+type Cleanup struct {
+	// ID identifies the cleanup in CompletedCleanupsAnnotation, it must not change.
+	ID string
+	// IntroducedIn is the first operator version leaving the resources behind, the cleanup
+	// doesn't run in former versions. Empty if the cleanup always applies.
+	IntroducedIn string
+	// RemovedIn is the first operator version which no supported upgrade leaves the resources
+	// behind for, the cleanup doesn't run any longer and can be dropped from its registry. Empty
+	// if the cleanup doesn't expire.
+	RemovedIn string
+	// Resources are the legacy resources to delete.
+	Resources []LegacyResource
+}
+
+// LegacyResource selects legacy resources of a kind by name or name prefix.
+type LegacyResource struct {
+	Resource schema.GroupVersionResource
+	// Namespaced resources are deleted in the namespace of the Knative component.
+	Namespaced bool
+	Names      []string
+	Prefixes   []string
+}
+
+// CleanupRegistry is the list of cleanups of a Knative component, run in order.
+type CleanupRegistry []Cleanup
+
+// Run runs the cleanups of the registry which apply to the current operator version and aren't
+// completed yet for the given Knative component, records the completed ones on its status and
+// reports the deleted resources as events.
+func (r CleanupRegistry) Run(ctx context.Context, client dynamic.Interface, comp base.KComponent, status *duckv1.Status) error {
+	version := currentVersion()
+	completed := sets.New(strings.Split(status.Annotations[CompletedCleanupsAnnotation], ",")...)
+	completed.Delete("")
+
+	done := sets.New[string]()
+	for _, cleanup := range r {
+		applies, err := cleanup.applies(version)
+		if err != nil {
+			recordCompletedCleanups(status, done.Union(completed))
+			return err
+		}
+		if !applies {
+			continue
+		}
+		if !completed.Has(cleanup.ID) {
+			if err := cleanup.run(ctx, client, comp); err != nil {
+				// Keep the progress, the remaining cleanups run again with the next reconcile.
+				recordCompletedCleanups(status, done.Union(completed))
+				return fmt.Errorf("failed to clean up legacy resources of %s: %w", cleanup.ID, err)
+			}
+		}
+		done.Insert(cleanup.ID)
+	}
+	recordCompletedCleanups(status, done)
+	return nil
+}
+
+// Validate checks that the IDs of the cleanups are unique and their versions valid.
+func (r CleanupRegistry) Validate() error {
+	ids := sets.New[string]()
+	for _, cleanup := range r {
+		if cleanup.ID == "" || ids.Has(cleanup.ID) {
+			return fmt.Errorf("cleanup IDs must be unique and not empty, got %q", cleanup.ID)
+		}
+		ids.Insert(cleanup.ID)
+		if _, err := cleanup.applies(nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applies returns whether the cleanup runs in the given operator version, all the cleanups run
+// in an unknown version. It fails if a version of the cleanup is invalid.
+func (c Cleanup) applies(version *semver.Version) (bool, error) {
+	introducedIn, err := parseVersion(c.IntroducedIn)
+	if err != nil {
+		return false, fmt.Errorf("invalid IntroducedIn of cleanup %s: %w", c.ID, err)
+	}
+	removedIn, err := parseVersion(c.RemovedIn)
+	if err != nil {
+		return false, fmt.Errorf("invalid RemovedIn of cleanup %s: %w", c.ID, err)
+	}
+	if version == nil {
+		return true, nil
+	}
+	if introducedIn != nil && version.LT(*introducedIn) {
+		return false, nil
+	}
+	return removedIn == nil || version.LT(*removedIn), nil
+}
+
+// parseVersion parses the given version of a cleanup, nil if empty.
+func parseVersion(value string) (*semver.Version, error) {
+	if value == "" {
+		return nil, nil
+	}
+	version, err := semver.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+func (c Cleanup) run(ctx context.Context, client dynamic.Interface, comp base.KComponent) error {
+	logger := logging.FromContext(ctx)
+	recorder := controller.GetEventRecorder(ctx)
+
+	for _, legacy := range c.Resources {
+		var resource dynamic.ResourceInterface = client.Resource(legacy.Resource)
+		if legacy.Namespaced {
+			resource = client.Resource(legacy.Resource).Namespace(comp.GetNamespace())
+		}
+
+		names := legacy.Names
+		if len(legacy.Prefixes) > 0 {
+			list, err := resource.List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return fmt.Errorf("failed to list %s: %w", legacy.Resource.Resource, err)
+			}
+			for _, item := range list.Items {
+				for _, prefix := range legacy.Prefixes {
+					if strings.HasPrefix(item.GetName(), prefix) {
+						names = append(names, item.GetName())
+						break
+					}
+				}
+			}
+		}
+
+		for _, name := range names {
+			err := resource.Delete(ctx, name, metav1.DeleteOptions{})
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to delete %s %s: %w", legacy.Resource.Resource, name, err)
+			}
+			logger.Infof("Deleted legacy %s %s of cleanup %s", legacy.Resource.Resource, name, c.ID)
+			if obj, ok := comp.(runtime.Object); ok && recorder != nil {
+				recorder.Eventf(obj, corev1.EventTypeNormal, "LegacyResourceDeleted",
+					"Deleted legacy %s %q of cleanup %s", legacy.Resource.Resource, name, c.ID)
+			}
+		}
+	}
+	return nil
+}
+
+// recordCompletedCleanups records the given cleanups on the status, dropping the ones which
+// don't apply any longer.
+func recordCompletedCleanups(status *duckv1.Status, done sets.Set[string]) {
+	if done.Len() == 0 {
+		delete(status.Annotations, CompletedCleanupsAnnotation)
+		return
+	}
+	if status.Annotations == nil {
+		status.Annotations = make(map[string]string, 1)
+	}
+	ids := done.UnsortedList()
+	sort.Strings(ids)
+	status.Annotations[CompletedCleanupsAnnotation] = strings.Join(ids, ",")
+}
+
+// currentVersion returns the version of the operator, nil if unknown.
+func currentVersion() *semver.Version {
+	version, err := semver.ParseTolerant(os.Getenv("CURRENT_VERSION"))
+	if err != nil {
+		return nil
+	}
+	return &version
+}
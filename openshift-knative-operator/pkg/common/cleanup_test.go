@@ -0,0 +1,183 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	clientgotesting "k8s.io/client-go/testing"
+	operatorv1beta1 "knative.dev/operator/pkg/apis/operator/v1beta1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+func TestCleanupRegistry(t *testing.T) {
+	const ns = "knative-serving"
+	secrets := corev1.SchemeGroupVersion.WithResource("secrets")
+	leases := coordinationv1.SchemeGroupVersion.WithResource("leases")
+	registry := CleanupRegistry{{
+		ID: "old-secret",
+		Resources: []LegacyResource{{
+			Resource:   secrets,
+			Namespaced: true,
+			Names:      []string{"old-secret"},
+		}},
+	}, {
+		ID:           "old-leases",
+		IntroducedIn: "1.30.0",
+		Resources: []LegacyResource{{
+			Resource:   leases,
+			Namespaced: true,
+			Prefixes:   []string{"old"},
+		}},
+	}, {
+		ID:        "expired",
+		RemovedIn: "1.32.0",
+		Resources: []LegacyResource{{
+			Resource:   secrets,
+			Namespaced: true,
+			Names:      []string{"expired-secret"},
+		}},
+	}}
+
+	objects := func() []runtime.Object {
+		return []runtime.Object{
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: "old-secret"}},
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: "expired-secret"}},
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: "current-secret"}},
+			&coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: "old-controller"}},
+			&coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: "old-webhook"}},
+			&coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: "current-controller"}},
+			&coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "old-controller"}},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		version     string
+		completed   string
+		failDelete  string
+		want        string
+		wantErr     bool
+		wantSecrets []string
+		wantLeases  []string
+	}{{
+		name:        "unknown version runs all cleanups",
+		want:        "expired,old-leases,old-secret",
+		wantSecrets: []string{"current-secret"},
+		wantLeases:  []string{"current-controller"},
+	}, {
+		name:        "cleanups applying to the version",
+		version:     "1.32.0",
+		want:        "old-leases,old-secret",
+		wantSecrets: []string{"current-secret", "expired-secret"},
+		wantLeases:  []string{"current-controller"},
+	}, {
+		name:        "cleanups introduced later",
+		version:     "1.29.1",
+		want:        "expired,old-secret",
+		wantSecrets: []string{"current-secret"},
+		wantLeases:  []string{"current-controller", "old-controller", "old-webhook"},
+	}, {
+		name:        "completed cleanups don't run again",
+		version:     "1.32.0",
+		completed:   "expired,old-secret",
+		want:        "old-leases,old-secret",
+		wantSecrets: []string{"current-secret", "expired-secret", "old-secret"},
+		wantLeases:  []string{"current-controller"},
+	}, {
+		name:        "failed cleanup keeps the progress",
+		version:     "1.32.0",
+		failDelete:  "leases",
+		want:        "old-secret",
+		wantErr:     true,
+		wantSecrets: []string{"current-secret", "expired-secret"},
+		wantLeases:  []string{"current-controller", "old-controller", "old-webhook"},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("CURRENT_VERSION", test.version)
+			client := dynamicfake.NewSimpleDynamicClient(scheme.Scheme, objects()...)
+			if test.failDelete != "" {
+				client.PrependReactor("delete", test.failDelete, func(clientgotesting.Action) (bool, runtime.Object, error) {
+					return true, nil, errors.New("inducing failure")
+				})
+			}
+			ks := &operatorv1beta1.KnativeServing{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: "knative-serving"}}
+			status := &duckv1.Status{}
+			if test.completed != "" {
+				status.Annotations = map[string]string{CompletedCleanupsAnnotation: test.completed}
+			}
+
+			err := registry.Run(context.Background(), client, ks, status)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("Run() = %v, wantErr %v", err, test.wantErr)
+			}
+			if got := status.Annotations[CompletedCleanupsAnnotation]; got != test.want {
+				t.Errorf("Completed cleanups = %q, want %q", got, test.want)
+			}
+
+			if got := names(t, client, secrets, ns); !cmp.Equal(got, test.wantSecrets) {
+				t.Error("Unexpected secrets (-want, +got):", cmp.Diff(test.wantSecrets, got))
+			}
+			if got := names(t, client, leases, ns); !cmp.Equal(got, test.wantLeases) {
+				t.Error("Unexpected leases (-want, +got):", cmp.Diff(test.wantLeases, got))
+			}
+			if got := names(t, client, leases, "other"); !cmp.Equal(got, []string{"old-controller"}) {
+				t.Error("Unexpected leases in other namespace:", got)
+			}
+		})
+	}
+}
+
+func TestCleanupRegistryValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		registry CleanupRegistry
+		wantErr  bool
+	}{{
+		name:     "valid",
+		registry: CleanupRegistry{{ID: "a"}, {ID: "b", IntroducedIn: "1.30.0", RemovedIn: "1.32.0"}},
+	}, {
+		name:     "duplicate ID",
+		registry: CleanupRegistry{{ID: "a"}, {ID: "a"}},
+		wantErr:  true,
+	}, {
+		name:     "missing ID",
+		registry: CleanupRegistry{{IntroducedIn: "1.30.0"}},
+		wantErr:  true,
+	}, {
+		name:     "invalid version",
+		registry: CleanupRegistry{{ID: "a", RemovedIn: "1.32"}},
+		wantErr:  true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.registry.Validate(); (err != nil) != test.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func names(t *testing.T, client *dynamicfake.FakeDynamicClient, gvr schema.GroupVersionResource, ns string) []string {
+	t.Helper()
+	list, err := client.Resource(gvr).Namespace(ns).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal("Failed to list", gvr.Resource, err)
+	}
+	names := make([]string, 0, len(list.Items))
+	for _, item := range list.Items {
+		names = append(names, item.GetName())
+	}
+	return names
+}
@@ -0,0 +1,158 @@
+package common
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+
+	mf "github.com/manifestival/manifestival"
+	configv1 "github.com/openshift/api/config/v1"
+	configv1listers "github.com/openshift/client-go/config/listers/config/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+const (
+	// ClusterProxyName is the name of the cluster-wide Proxy configuration.
+	ClusterProxyName = "cluster"
+	// ProxyChecksumAnnotation is the annotation of the pod template of the workloads the proxy is
+	// injected into, rolling their pods when the proxy configuration changes.
+	ProxyChecksumAnnotation = "serverless.openshift.io/proxy-checksum"
+)
+
+// proxyEnvNames are the environment variables configuring the proxy of the outbound calls.
+var proxyEnvNames = sets.New("HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY")
+
+// ProxyEnvironment returns the environment variables configuring the proxy of the given cluster
+// Proxy, taken from its status which holds the effective values, e.g. NO_PROXY including the
+// cluster networks. Without cluster Proxy, it falls back to the proxy environment OLM injects
+// into the operator. It returns nil if no proxy is configured.
+func ProxyEnvironment(proxy *configv1.Proxy) []corev1.EnvVar {
+	status := configv1.ProxyStatus{
+		HTTPProxy:  os.Getenv("HTTP_PROXY"),
+		HTTPSProxy: os.Getenv("HTTPS_PROXY"),
+		NoProxy:    os.Getenv("NO_PROXY"),
+	}
+	if proxy != nil {
+		status = proxy.Status
+	}
+	var env []corev1.EnvVar
+	for _, v := range []corev1.EnvVar{
+		{Name: "HTTP_PROXY", Value: status.HTTPProxy},
+		{Name: "HTTPS_PROXY", Value: status.HTTPSProxy},
+		{Name: "NO_PROXY", Value: status.NoProxy},
+	} {
+		if v.Value != "" {
+			env = append(env, v)
+		}
+	}
+	return env
+}
+
+// InjectProxyTransform injects the given proxy environment into the containers of the Deployments
+// and StatefulSets with the given names, replacing any proxy environment of the manifests. The
+// workloads trust the CA of the proxy through the given CA bundle they mount, which the running
+// pods don't pick up. Their pod templates are thus annotated with the checksum of the proxy
+// environment and of the contents of the CA bundle, rolling the pods when either is changed.
+func InjectProxyTransform(env []corev1.EnvVar, caBundle *corev1.ConfigMap, workloads ...string) mf.Transformer {
+	names := sets.New(workloads...)
+	checksum := proxyChecksum(env, caBundle)
+	return func(u *unstructured.Unstructured) error {
+		if len(env) == 0 || !names.Has(u.GetName()) {
+			return nil
+		}
+
+		var template *corev1.PodTemplateSpec
+		var obj metav1.Object
+		switch u.GetKind() {
+		case "Deployment":
+			deployment := &appsv1.Deployment{}
+			if err := scheme.Scheme.Convert(u, deployment, nil); err != nil {
+				return fmt.Errorf("failed to convert Unstructured to Deployment: %w", err)
+			}
+			obj = deployment
+			template = &deployment.Spec.Template
+		case "StatefulSet":
+			ss := &appsv1.StatefulSet{}
+			if err := scheme.Scheme.Convert(u, ss, nil); err != nil {
+				return fmt.Errorf("failed to convert Unstructured to StatefulSet: %w", err)
+			}
+			obj = ss
+			template = &ss.Spec.Template
+		default:
+			return nil
+		}
+
+		for i := range template.Spec.Containers {
+			c := &template.Spec.Containers[i]
+			containerEnv := make([]corev1.EnvVar, 0, len(c.Env)+len(env))
+			for _, v := range c.Env {
+				if !proxyEnvNames.Has(v.Name) {
+					containerEnv = append(containerEnv, v)
+				}
+			}
+			c.Env = append(containerEnv, env...)
+		}
+		if template.Annotations == nil {
+			template.Annotations = make(map[string]string, 1)
+		}
+		template.Annotations[ProxyChecksumAnnotation] = checksum
+
+		if err := scheme.Scheme.Convert(obj, u, nil); err != nil {
+			return err
+		}
+		// The zero-value timestamp defaulted by the conversion causes
+		// superfluous updates
+		u.SetCreationTimestamp(metav1.Time{})
+		return nil
+	}
+}
+
+// CABundle returns the ConfigMap with the given name holding the CA bundle trusted by the
+// workloads in the given namespace, nil if it can't be found.
+func CABundle(ctx context.Context, client kubernetes.Interface, namespace, name string) *corev1.ConfigMap {
+	if client == nil || name == "" {
+		return nil
+	}
+	cm, err := client.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil
+	}
+	return cm
+}
+
+func proxyChecksum(env []corev1.EnvVar, caBundle *corev1.ConfigMap) string {
+	h := sha256.New()
+	for _, v := range env {
+		h.Write([]byte(v.Name + "=" + v.Value + "\n"))
+	}
+	if caBundle != nil {
+		for _, k := range sets.List(sets.KeySet(caBundle.Data)) {
+			h.Write([]byte(k + "=" + caBundle.Data[k] + "\n"))
+		}
+		for _, k := range sets.List(sets.KeySet(caBundle.BinaryData)) {
+			h.Write([]byte(k + "="))
+			h.Write(caBundle.BinaryData[k])
+			h.Write([]byte("\n"))
+		}
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))[0:16]
+}
+
+// ClusterProxy returns the cluster-wide Proxy configuration, nil if it can't be found.
+func ClusterProxy(lister configv1listers.ProxyLister) *configv1.Proxy {
+	if lister == nil {
+		return nil
+	}
+	proxy, err := lister.Get(ClusterProxyName)
+	if err != nil {
+		return nil
+	}
+	return proxy
+}
@@ -0,0 +1,163 @@
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	configv1 "github.com/openshift/api/config/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/scheme"
+	kubefake "knative.dev/pkg/client/injection/kube/client/fake"
+)
+
+func TestProxyEnvironment(t *testing.T) {
+	t.Setenv("HTTP_PROXY", "http://operator-proxy:3128")
+	t.Setenv("HTTPS_PROXY", "")
+	t.Setenv("NO_PROXY", ".svc")
+
+	tests := []struct {
+		name  string
+		proxy *configv1.Proxy
+		want  []corev1.EnvVar
+	}{{
+		name: "cluster proxy",
+		proxy: &configv1.Proxy{
+			Spec: configv1.ProxySpec{HTTPProxy: "http://spec-proxy:3128"},
+			Status: configv1.ProxyStatus{
+				HTTPProxy:  "http://proxy:3128",
+				HTTPSProxy: "https://proxy:3129",
+				NoProxy:    ".cluster.local,.svc,10.0.0.0/16",
+			},
+		},
+		want: []corev1.EnvVar{
+			envVar("HTTP_PROXY", "http://proxy:3128"),
+			envVar("HTTPS_PROXY", "https://proxy:3129"),
+			envVar("NO_PROXY", ".cluster.local,.svc,10.0.0.0/16"),
+		},
+	}, {
+		name:  "cluster proxy not configured",
+		proxy: &configv1.Proxy{},
+	}, {
+		name: "operator environment without cluster proxy",
+		want: []corev1.EnvVar{
+			envVar("HTTP_PROXY", "http://operator-proxy:3128"),
+			envVar("NO_PROXY", ".svc"),
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := ProxyEnvironment(test.proxy); !cmp.Equal(got, test.want) {
+				t.Error("Unexpected environment (-want, +got):", cmp.Diff(test.want, got))
+			}
+		})
+	}
+}
+
+func TestInjectProxyTransform(t *testing.T) {
+	proxyEnv := []corev1.EnvVar{envVar("HTTP_PROXY", "http://proxy:3128"), envVar("NO_PROXY", ".svc")}
+	deployment := func(name string, annotations map[string]string, env ...corev1.EnvVar) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Annotations: annotations},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "container", Env: env}},
+					},
+				},
+			},
+		}
+	}
+	caBundle := trustedCABundle("bundle")
+	checksum := proxyChecksum(proxyEnv, caBundle)
+
+	tests := []struct {
+		name string
+		env  []corev1.EnvVar
+		in   *appsv1.Deployment
+		want *appsv1.Deployment
+	}{{
+		name: "inject into workload",
+		env:  proxyEnv,
+		in:   deployment("dispatcher", nil, envVar("foo", "bar")),
+		want: deployment("dispatcher", map[string]string{ProxyChecksumAnnotation: checksum},
+			envVar("foo", "bar"), envVar("HTTP_PROXY", "http://proxy:3128"), envVar("NO_PROXY", ".svc")),
+	}, {
+		name: "replace proxy environment of the manifest",
+		env:  proxyEnv,
+		in:   deployment("dispatcher", nil, envVar("HTTPS_PROXY", "https://old:3129"), envVar("foo", "bar")),
+		want: deployment("dispatcher", map[string]string{ProxyChecksumAnnotation: checksum},
+			envVar("foo", "bar"), envVar("HTTP_PROXY", "http://proxy:3128"), envVar("NO_PROXY", ".svc")),
+	}, {
+		name: "ignore other workloads",
+		env:  proxyEnv,
+		in:   deployment("webhook", nil, envVar("foo", "bar")),
+		want: deployment("webhook", nil, envVar("foo", "bar")),
+	}, {
+		name: "no proxy",
+		in:   deployment("dispatcher", nil, envVar("foo", "bar")),
+		want: deployment("dispatcher", nil, envVar("foo", "bar")),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			u := &unstructured.Unstructured{}
+			if err := scheme.Scheme.Convert(test.in, u, nil); err != nil {
+				t.Fatal("Failed to convert deployment to unstructured", err)
+			}
+			if err := InjectProxyTransform(test.env, caBundle, "dispatcher")(u); err != nil {
+				t.Fatal("Unexpected error from transformer", err)
+			}
+			got := &appsv1.Deployment{}
+			if err := scheme.Scheme.Convert(u, got, nil); err != nil {
+				t.Fatal("Failed to convert unstructured to deployment", err)
+			}
+			if !cmp.Equal(got, test.want) {
+				t.Error("Unexpected deployment (-want, +got):", cmp.Diff(test.want, got))
+			}
+		})
+	}
+}
+
+func TestProxyChecksum(t *testing.T) {
+	env := []corev1.EnvVar{envVar("HTTP_PROXY", "http://proxy:3128")}
+	if proxyChecksum(env, nil) == proxyChecksum(env, trustedCABundle("bundle")) {
+		t.Error("Expected the checksum to change with the trusted CA bundle")
+	}
+	if proxyChecksum(env, trustedCABundle("bundle")) == proxyChecksum(env, trustedCABundle("rotated bundle")) {
+		t.Error("Expected the checksum to change with the contents of the trusted CA bundle")
+	}
+	renamed := trustedCABundle("bundle")
+	renamed.Name = "other-ca-bundle"
+	if proxyChecksum(env, trustedCABundle("bundle")) != proxyChecksum(env, renamed) {
+		t.Error("Expected the checksum to only depend on the contents of the trusted CA bundle")
+	}
+	if proxyChecksum(env, nil) == proxyChecksum([]corev1.EnvVar{envVar("HTTP_PROXY", "http://other:3128")}, nil) {
+		t.Error("Expected the checksum to change with the proxy")
+	}
+}
+
+func TestCABundle(t *testing.T) {
+	_, client := kubefake.With(context.Background(), trustedCABundle("bundle"))
+	if got := CABundle(context.Background(), client, "knative-eventing", TrustedCAConfigMapName); !cmp.Equal(got, trustedCABundle("bundle")) {
+		t.Error("Unexpected CA bundle (-want, +got):", cmp.Diff(trustedCABundle("bundle"), got))
+	}
+	if got := CABundle(context.Background(), client, "knative-serving", TrustedCAConfigMapName); got != nil {
+		t.Errorf("CABundle() = %v, want nil for a missing CA bundle", got)
+	}
+	if got := CABundle(context.Background(), nil, "knative-eventing", TrustedCAConfigMapName); got != nil {
+		t.Errorf("CABundle() = %v, want nil without client", got)
+	}
+}
+
+func trustedCABundle(bundle string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: TrustedCAConfigMapName, Namespace: "knative-eventing"},
+		Data:       map[string]string{TrustedCAKey: bundle},
+	}
+}
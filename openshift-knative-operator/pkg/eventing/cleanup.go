@@ -0,0 +1,9 @@
+package eventing
+
+import (
+	"github.com/openshift-knative/serverless-operator/openshift-knative-operator/pkg/common"
+)
+
+// cleanups are the cleanups of legacy, deprecated or dangling resources of former Eventing features.
+// Add a cleanup with a new ID for resources which a release stops managing.
+var cleanups = common.CleanupRegistry{}
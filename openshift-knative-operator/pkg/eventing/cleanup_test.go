@@ -0,0 +1,9 @@
+package eventing
+
+import "testing"
+
+func TestCleanups(t *testing.T) {
+	if err := cleanups.Validate(); err != nil {
+		t.Error("Invalid cleanups:", err)
+	}
+}
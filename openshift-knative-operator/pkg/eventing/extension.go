@@ -7,6 +7,7 @@ import (
 	"strconv"
 
 	mf "github.com/manifestival/manifestival"
+	configv1listers "github.com/openshift/client-go/config/listers/config/v1"
 	"go.uber.org/zap"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -15,8 +16,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
 	"knative.dev/operator/pkg/apis/operator/base"
 	operatorv1beta1 "knative.dev/operator/pkg/apis/operator/v1beta1"
+	knativeeventinginformer "knative.dev/operator/pkg/client/injection/informers/operator/v1beta1/knativeeventing"
 	operator "knative.dev/operator/pkg/reconciler/common"
 	kubeclient "knative.dev/pkg/client/injection/kube/client"
 	"knative.dev/pkg/controller"
@@ -26,6 +29,7 @@ import (
 
 	"github.com/openshift-knative/serverless-operator/openshift-knative-operator/pkg/common"
 	"github.com/openshift-knative/serverless-operator/openshift-knative-operator/pkg/monitoring"
+	proxyinformer "github.com/openshift-knative/serverless-operator/pkg/client/config/injection/informers/config/v1/proxy"
 	"github.com/openshift-knative/serverless-operator/pkg/istio/eventingistio"
 )
 
@@ -35,9 +39,24 @@ const (
 	disableGeneratingIstioNetPoliciesAnnotation = "serverless.openshift.io/disable-istio-net-policies-generation"
 )
 
+// proxyWorkloads are the workloads delivering events to sinks, which might be outside of the cluster
+// and thus called through the cluster proxy. All the workloads mount the trusted CA bundle, whose
+// ConfigMap is part of the manifest, so changes of its contents already trigger a reconcile.
+var proxyWorkloads = []string{"pingsource-mt-adapter", "mt-broker-filter", "imc-dispatcher"}
+
 // NewExtension creates a new extension for a Knative Eventing controller.
-func NewExtension(ctx context.Context, _ *controller.Impl) operator.Extension {
+func NewExtension(ctx context.Context, impl *controller.Impl) operator.Extension {
+	// Roll the components making outbound calls when the cluster proxy configuration changes.
+	proxyInformer := proxyinformer.Get(ctx)
+	proxyInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.FilterWithName(common.ClusterProxyName),
+		Handler: controller.HandleAll(func(interface{}) {
+			impl.GlobalResync(knativeeventinginformer.Get(ctx).Informer())
+		}),
+	})
+
 	return &extension{
+		proxyLister:   proxyInformer.Lister(),
 		kubeclient:    kubeclient.Get(ctx),
 		dynamicclient: dynamicclient.Get(ctx),
 		logger:        logging.FromContext(ctx),
@@ -45,6 +64,7 @@ func NewExtension(ctx context.Context, _ *controller.Impl) operator.Extension {
 }
 
 type extension struct {
+	proxyLister   configv1listers.ProxyLister
 	kubeclient    kubernetes.Interface
 	dynamicclient dynamic.Interface
 	logger        *zap.SugaredLogger
@@ -88,11 +108,14 @@ func (e *extension) deleteResourcesSilently(m mf.Manifest) {
 }
 
 func (e *extension) Transformers(ke base.KComponent) []mf.Transformer {
+	proxy := common.ClusterProxy(e.proxyLister)
+	caBundle := common.CABundle(context.Background(), e.kubeclient, ke.GetNamespace(), common.TrustedCAConfigMapName)
 	tf := []mf.Transformer{
 		common.InjectCommonLabelIntoNamespace(),
 		common.VersionedJobNameTransform(),
 		common.InjectCommonEnvironment(),
 		common.ApplyCABundlesTransform(),
+		common.InjectProxyTransform(common.ProxyEnvironment(proxy), caBundle, proxyWorkloads...),
 		common.JobsRemoveTTLSecondsAfterFinished(),
 	}
 	tf = append(tf, monitoring.GetEventingTransformers(ke)...)
@@ -135,6 +158,10 @@ func (e *extension) Reconcile(ctx context.Context, comp base.KComponent) error {
 		eventingistio.ScaleIstioController(requiredNs, ke, 1)
 	}
 
+	if err := cleanups.Run(ctx, e.dynamicclient, ke, &ke.Status.Status); err != nil {
+		return err
+	}
+
 	return monitoring.ReconcileMonitoringForEventing(ctx, e.kubeclient, ke)
 }
 
@@ -11,9 +11,12 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	mf "github.com/manifestival/manifestival"
+	configv1 "github.com/openshift/api/config/v1"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/utils/ptr"
 	"knative.dev/operator/pkg/apis/operator/base"
@@ -21,9 +24,11 @@ import (
 	"knative.dev/pkg/apis"
 	kubefake "knative.dev/pkg/client/injection/kube/client/fake"
 	dynamicfake "knative.dev/pkg/injection/clients/dynamicclient/fake"
+	rtesting "knative.dev/pkg/reconciler/testing"
 
 	"github.com/openshift-knative/serverless-operator/openshift-knative-operator/pkg/common"
 	"github.com/openshift-knative/serverless-operator/openshift-knative-operator/pkg/monitoring"
+	proxyinformer "github.com/openshift-knative/serverless-operator/pkg/client/config/injection/informers/config/v1/proxy/fake"
 )
 
 const requiredNs = "knative-eventing"
@@ -139,7 +144,8 @@ func TestReconcile(t *testing.T) {
 			}
 
 			ke := c.in.DeepCopy()
-			ctx, _ := kubefake.With(context.Background(), &eventingNamespace)
+			ctx, _ := rtesting.SetupFakeContext(t)
+			ctx, _ = kubefake.With(ctx, &eventingNamespace)
 			ctx, _ = dynamicfake.With(ctx, scheme.Scheme)
 			ext := NewExtension(ctx, nil)
 			ext.Reconcile(context.Background(), ke)
@@ -260,7 +266,8 @@ func TestManifests(t *testing.T) {
 				tc.in.Namespace = requiredNs
 			}
 			ke := tc.in
-			ctx, _ := kubefake.With(context.Background(), &eventingNamespace)
+			ctx, _ := rtesting.SetupFakeContext(t)
+			ctx, _ = kubefake.With(ctx, &eventingNamespace)
 			ctx, _ = dynamicfake.With(ctx, scheme.Scheme)
 			ext := NewExtension(ctx, nil)
 			m, err := ext.Manifests(ke)
@@ -389,7 +396,8 @@ func TestMonitoring(t *testing.T) {
 				ke.Namespace = requiredNs
 			}
 			c.expected.Namespace = ke.Namespace
-			ctx, kube := kubefake.With(context.Background(), &eventingNamespace)
+			ctx, _ := rtesting.SetupFakeContext(t)
+			ctx, kube := kubefake.With(ctx, &eventingNamespace)
 			ctx, _ = dynamicfake.With(ctx, scheme.Scheme)
 			ext := NewExtension(ctx, nil)
 			shouldEnableMonitoring, err := c.setupMonitoringToggle()
@@ -417,6 +425,56 @@ func TestMonitoring(t *testing.T) {
 	}
 }
 
+func TestProxyCABundle(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	proxyinformer.Get(ctx).Informer().GetIndexer().Add(&configv1.Proxy{
+		ObjectMeta: metav1.ObjectMeta{Name: common.ClusterProxyName},
+		Status:     configv1.ProxyStatus{HTTPProxy: "http://proxy:3128"},
+	})
+	bundle := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: common.TrustedCAConfigMapName, Namespace: requiredNs},
+		Data:       map[string]string{common.TrustedCAKey: "bundle"},
+	}
+	ctx, kube := kubefake.With(ctx, &eventingNamespace, bundle)
+	ctx, _ = dynamicfake.With(ctx, scheme.Scheme)
+	ext := NewExtension(ctx, nil)
+
+	checksum := func() string {
+		dispatcher := &appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+			ObjectMeta: metav1.ObjectMeta{Name: "imc-dispatcher", Namespace: requiredNs},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "dispatcher"}}},
+				},
+			},
+		}
+		u := &unstructured.Unstructured{}
+		if err := scheme.Scheme.Convert(dispatcher, u, nil); err != nil {
+			t.Fatal("Failed to convert deployment to unstructured", err)
+		}
+		manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{*u}))
+		if err != nil {
+			t.Fatal("Failed to create manifest", err)
+		}
+		manifest, err = manifest.Transform(ext.Transformers(ke())...)
+		if err != nil {
+			t.Fatal("Failed to transform manifest", err)
+		}
+		annotations, _, _ := unstructured.NestedStringMap(manifest.Resources()[0].Object, "spec", "template", "metadata", "annotations")
+		return annotations[common.ProxyChecksumAnnotation]
+	}
+
+	before := checksum()
+	bundle.Data[common.TrustedCAKey] = "rotated bundle"
+	if _, err := kube.CoreV1().ConfigMaps(requiredNs).Update(context.Background(), bundle, metav1.UpdateOptions{}); err != nil {
+		t.Fatal("Failed to update the CA bundle", err)
+	}
+	if after := checksum(); after == before {
+		t.Errorf("Proxy checksum = %q, want it changed with the contents of the CA bundle", after)
+	}
+}
+
 func ke(mods ...func(*operatorv1beta1.KnativeEventing)) *operatorv1beta1.KnativeEventing {
 	base := &operatorv1beta1.KnativeEventing{
 		ObjectMeta: metav1.ObjectMeta{
@@ -0,0 +1,61 @@
+package serving
+
+import (
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openshift-knative/serverless-operator/openshift-knative-operator/pkg/common"
+)
+
+const (
+	networkingCertificatesReconcilerLease      = "controller.knative.dev.networking.pkg.certificates.reconciler.reconciler"
+	controlProtocolCertificatesReconcilerLease = "controller.knative.dev.control-protocol.pkg.certificates.reconciler.reconciler"
+)
+
+// cleanups are the cleanups of legacy, deprecated or dangling resources of former Serving features.
+// Add a cleanup with a new ID for resources which a release stops managing.
+var cleanups = common.CleanupRegistry{{
+	// DomainMapping moved into the Serving controller and webhook.
+	ID: "domain-mapping",
+	Resources: []common.LegacyResource{{
+		Resource:   appsv1.SchemeGroupVersion.WithResource("deployments"),
+		Namespaced: true,
+		Names:      []string{"domain-mapping", "domainmapping-webhook"},
+	}, {
+		Resource:   corev1.SchemeGroupVersion.WithResource("services"),
+		Namespaced: true,
+		Names:      []string{"domainmapping-webhook", "domain-mapping-sm-service", "domainmapping-webhook-sm-service"},
+	}, {
+		Resource:   coordinationv1.SchemeGroupVersion.WithResource("leases"),
+		Namespaced: true,
+		Prefixes:   []string{"domainmapping"},
+	}, {
+		Resource:   corev1.SchemeGroupVersion.WithResource("secrets"),
+		Namespaced: true,
+		Names:      []string{"domainmapping-webhook-certs"},
+	}, {
+		Resource: admissionregistrationv1.SchemeGroupVersion.WithResource("mutatingwebhookconfigurations"),
+		Names:    []string{"webhook.domainmapping.serving.knative.dev"},
+	}, {
+		Resource: admissionregistrationv1.SchemeGroupVersion.WithResource("validatingwebhookconfigurations"),
+		Names:    []string{"validation.webhook.domainmapping.serving.knative.dev"},
+	}},
+}, {
+	// The leases of the net-certmanager controller and the certificates reconcilers.
+	ID: "certificate-reconciler-leases",
+	Resources: []common.LegacyResource{{
+		Resource:   coordinationv1.SchemeGroupVersion.WithResource("leases"),
+		Namespaced: true,
+		Prefixes:   []string{"net-certmanager", networkingCertificatesReconcilerLease, controlProtocolCertificatesReconcilerLease},
+	}},
+}, {
+	// SRVKS-1264 - deprecated TLS secret
+	ID: "control-serving-certs",
+	Resources: []common.LegacyResource{{
+		Resource:   corev1.SchemeGroupVersion.WithResource("secrets"),
+		Namespaced: true,
+		Names:      []string{"control-serving-certs"},
+	}},
+}}
@@ -0,0 +1,108 @@
+package serving
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	mf "github.com/manifestival/manifestival"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/scheme"
+	"knative.dev/operator/pkg/apis/operator/base"
+	operatorv1beta1 "knative.dev/operator/pkg/apis/operator/v1beta1"
+
+	"github.com/openshift-knative/serverless-operator/openshift-knative-operator/pkg/monitoring"
+)
+
+// EffectiveConfigName is the name of the ConfigMap, in the namespace of KnativeServing, showing
+// the effective configuration computed by the operator, including the defaults it applied.
+// It's informational only, changing it has no effect.
+const EffectiveConfigName = "serverless-effective-config"
+
+const (
+	kourierGatewayPDBName = kourierGatewayName + "-pdb"
+	// kourierGatewayPDBDefault is the PodDisruptionBudget of the Kourier gateway in its manifest.
+	kourierGatewayPDBDefault = "minAvailable=1"
+)
+
+// effectiveConfigManifest returns the manifest of the ConfigMap showing the effective configuration
+// of the given KnativeServing, once the defaults were applied by Reconcile.
+func effectiveConfigManifest(ks base.KComponent) (mf.Manifest, error) {
+	comp := ks.(*operatorv1beta1.KnativeServing)
+	cm := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      EffectiveConfigName,
+			Namespace: ks.GetNamespace(),
+		},
+		Data: effectiveConfig(comp),
+	}
+
+	u := unstructured.Unstructured{}
+	if err := scheme.Scheme.Convert(cm, &u, nil); err != nil {
+		return mf.Manifest{}, err
+	}
+	return mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{u}))
+}
+
+// effectiveConfig returns the effective values of the configuration the operator defaults.
+func effectiveConfig(ks *operatorv1beta1.KnativeServing) map[string]string {
+	data := make(map[string]string)
+	set := func(key, value string) {
+		if value != "" {
+			data[key] = value
+		}
+	}
+
+	// The keys of config-domain are the domains.
+	domains := make([]string, 0)
+	for domain := range monitoring.GetCmDataforName(ks.Spec.Config, "config-domain") {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+	set("domain", strings.Join(domains, ","))
+
+	// Both the current and the legacy keys of config-network are supported.
+	set("ingress-class", configValue(ks, networkCMName, "ingress-class", "ingress.class"))
+	set("domain-template", configValue(ks, networkCMName, "domain-template", "domainTemplate"))
+	set("external-scheme", configValue(ks, networkCMName, "default-external-scheme", "defaultExternalScheme"))
+	if ks.Spec.Ingress != nil && ks.Spec.Ingress.Kourier.Enabled {
+		set("kourier-namespace", kourierNamespace(ks.GetNamespace()))
+	}
+
+	if ha := ks.Spec.HighAvailability; ha != nil && ha.Replicas != nil {
+		set("ha-replicas", strconv.Itoa(int(*ha.Replicas)))
+	}
+	// The PodDisruptionBudgets of Serving are always overridden by the defaults of Reconcile, the
+	// one of the Kourier gateway keeps the value of its manifest unless overridden.
+	if ks.Spec.Ingress != nil && ks.Spec.Ingress.Kourier.Enabled {
+		set("pdb."+kourierGatewayPDBName, kourierGatewayPDBDefault)
+	}
+	for _, pdb := range ks.Spec.PodDisruptionBudgetOverride {
+		switch {
+		case pdb.MinAvailable != nil:
+			set("pdb."+pdb.Name, fmt.Sprintf("minAvailable=%s", pdb.MinAvailable.String()))
+		case pdb.MaxUnavailable != nil:
+			set("pdb."+pdb.Name, fmt.Sprintf("maxUnavailable=%s", pdb.MaxUnavailable.String()))
+		}
+	}
+	return data
+}
+
+// configValue returns the value of the first of the given keys set in the given ConfigMap of the
+// configuration of KnativeServing.
+func configValue(ks *operatorv1beta1.KnativeServing, cm string, keys ...string) string {
+	data := monitoring.GetCmDataforName(ks.Spec.Config, "config-"+cm)
+	for _, key := range keys {
+		if value := data[key]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,111 @@
+package serving
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	"knative.dev/operator/pkg/apis/operator/base"
+	operatorv1beta1 "knative.dev/operator/pkg/apis/operator/v1beta1"
+	kubefake "knative.dev/pkg/client/injection/kube/client/fake"
+	"knative.dev/pkg/ptr"
+
+	configfake "github.com/openshift-knative/serverless-operator/pkg/client/config/injection/client/fake"
+	routefake "github.com/openshift-knative/serverless-operator/pkg/client/route/injection/client/fake"
+)
+
+func TestEffectiveConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		in   *operatorv1beta1.KnativeServing
+		want map[string]string
+	}{{
+		name: "defaults",
+		in:   &operatorv1beta1.KnativeServing{},
+		want: map[string]string{
+			"domain":            "routing.example.com",
+			"ingress-class":     kourierIngressClassName,
+			"domain-template":   defaultDomainTemplate,
+			"external-scheme":   "https",
+			"kourier-namespace": "knative-serving-ingress",
+			"ha-replicas":       "2",
+			"pdb.activator-pdb": "minAvailable=1",
+			"pdb.webhook-pdb":   "minAvailable=1",
+
+			"pdb.3scale-kourier-gateway-pdb": "minAvailable=1",
+		},
+	}, {
+		name: "user configuration",
+		in: &operatorv1beta1.KnativeServing{
+			Spec: operatorv1beta1.KnativeServingSpec{
+				CommonSpec: base.CommonSpec{
+					Config: base.ConfigMapData{
+						"config-domain": {"b.example.com": "", "a.example.com": "selector:\n  app: a"},
+						"network": {
+							"ingress-class":           istioIngressClassName,
+							"domain-template":         "{{.Name}}.{{.Namespace}}.{{.Domain}}",
+							"default-external-scheme": "http",
+						},
+					},
+					HighAvailability: &base.HighAvailability{Replicas: ptr.Int32(3)},
+					PodDisruptionBudgetOverride: []base.PodDisruptionBudgetOverride{{
+						Name: "activator-pdb",
+						PodDisruptionBudgetSpec: policyv1.PodDisruptionBudgetSpec{
+							MaxUnavailable: &intstr.IntOrString{Type: intstr.String, StrVal: "50%"},
+						},
+					}},
+				},
+				Ingress: &operatorv1beta1.IngressConfigs{
+					Istio: base.IstioIngressConfiguration{Enabled: true},
+				},
+			},
+		},
+		want: map[string]string{
+			"domain":            "a.example.com,b.example.com",
+			"ingress-class":     istioIngressClassName,
+			"domain-template":   "{{.Name}}.{{.Namespace}}.{{.Domain}}",
+			"external-scheme":   "http",
+			"ha-replicas":       "3",
+			"pdb.activator-pdb": "maxUnavailable=50%",
+			"pdb.webhook-pdb":   "minAvailable=1",
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ks := test.in.DeepCopy()
+			ks.Namespace = servingNamespace.Name
+
+			ctx, _ := routefake.With(context.Background())
+			ctx, _ = configfake.With(ctx, defaultIngress)
+			ctx, _ = kubefake.With(ctx, &servingNamespace)
+			ext := newFakeExtension(ctx, t)
+			if err := ext.Reconcile(context.Background(), ks); err != nil {
+				t.Fatal("Unexpected error from Reconcile", err)
+			}
+
+			manifest, err := effectiveConfigManifest(ks)
+			if err != nil {
+				t.Fatal("Failed to generate the effective configuration", err)
+			}
+			resources := manifest.Resources()
+			if len(resources) != 1 {
+				t.Fatalf("Got %d resources, want 1", len(resources))
+			}
+			cm := &corev1.ConfigMap{}
+			if err := scheme.Scheme.Convert(&resources[0], cm, nil); err != nil {
+				t.Fatal("Failed to convert unstructured to ConfigMap", err)
+			}
+			if cm.Name != EffectiveConfigName || cm.Namespace != servingNamespace.Name {
+				t.Errorf("Got ConfigMap %s/%s, want %s/%s", cm.Namespace, cm.Name, servingNamespace.Name, EffectiveConfigName)
+			}
+			if !cmp.Equal(cm.Data, test.want) {
+				t.Error("Unexpected effective configuration (-want, +got):", cmp.Diff(test.want, cm.Data))
+			}
+		})
+	}
+}
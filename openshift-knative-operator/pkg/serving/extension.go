@@ -5,49 +5,50 @@ import (
 	"fmt"
 	"os"
 	"strconv"
-	"strings"
-	"sync/atomic"
 
 	mf "github.com/manifestival/manifestival"
 	"github.com/openshift-knative/serverless-operator/openshift-knative-operator/pkg/common"
 	"github.com/openshift-knative/serverless-operator/openshift-knative-operator/pkg/monitoring"
 	socommon "github.com/openshift-knative/serverless-operator/pkg/common"
 	appsv1 "k8s.io/api/apps/v1"
-	corev1 "k8s.io/api/core/v1"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/cache"
 	"knative.dev/operator/pkg/apis/operator/base"
 	operatorv1beta1 "knative.dev/operator/pkg/apis/operator/v1beta1"
+	knativeservinginformer "knative.dev/operator/pkg/client/injection/informers/operator/v1beta1/knativeserving"
 	operator "knative.dev/operator/pkg/reconciler/common"
 	kubeclient "knative.dev/pkg/client/injection/kube/client"
 	deploymentinformer "knative.dev/pkg/client/injection/kube/informers/apps/v1/deployment"
 	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection/clients/dynamicclient"
 	"knative.dev/pkg/ptr"
 	"knative.dev/pkg/reconciler"
 
 	configinjection "github.com/openshift-knative/serverless-operator/pkg/client/config/injection/client"
+	proxyinformer "github.com/openshift-knative/serverless-operator/pkg/client/config/injection/informers/config/v1/proxy"
 	routeinjection "github.com/openshift-knative/serverless-operator/pkg/client/route/injection/client"
 
 	configclient "github.com/openshift/client-go/config/clientset/versioned"
-	routeclient "github.com/openshift/client-go/route/clientset/versioned"
+	configv1listers "github.com/openshift/client-go/config/listers/config/v1"
 )
 
+// proxyWorkloads are the workloads making outbound calls through the cluster proxy, e.g. the
+// controller resolving image digests. The controller trusts the cluster CA bundle through the
+// custom certificates of Serving, whose ConfigMap is labeled to trigger a reconcile when changed.
+var proxyWorkloads = []string{"controller"}
+
 const (
-	loggingURLTemplate                         = "https://%s/app/kibana#/discover?_a=(index:.all,query:'kubernetes.labels.serving_knative_dev%%5C%%2FrevisionUID:${REVISION_UID}')"
-	requiredNsEnvName                          = "REQUIRED_SERVING_NAMESPACE"
-	defaultDomainTemplate                      = "{{.Name}}-{{.Namespace}}.{{.Domain}}"
-	networkingCertificatesReconcilerLease      = "controller.knative.dev.networking.pkg.certificates.reconciler.reconciler"
-	controlProtocolCertificatesReconcilerLease = "controller.knative.dev.control-protocol.pkg.certificates.reconciler.reconciler"
-	maxRevisionTimeoutSeconds                  = "max-revision-timeout-seconds"
+	requiredNsEnvName         = "REQUIRED_SERVING_NAMESPACE"
+	defaultDomainTemplate     = "{{.Name}}-{{.Namespace}}.{{.Domain}}"
+	maxRevisionTimeoutSeconds = "max-revision-timeout-seconds"
+	revisionTimeoutSeconds    = "revision-timeout-seconds"
 )
 
-var oldResourceRemoved atomic.Bool
-
 // NewExtension creates a new extension for a Knative Serving controller.
 func NewExtension(ctx context.Context, impl *controller.Impl) operator.Extension {
 	deploymentInformer := deploymentinformer.Get(ctx)
@@ -59,17 +60,34 @@ func NewExtension(ctx context.Context, impl *controller.Impl) operator.Extension
 		Handler:    controller.HandleAll(impl.EnqueueLabelOfNamespaceScopedResource(socommon.ServingOwnerNamespace, socommon.ServingOwnerName)),
 	})
 
+	// Roll the components making outbound calls when the cluster proxy configuration changes.
+	proxyInformer := proxyinformer.Get(ctx)
+	proxyInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.FilterWithName(common.ClusterProxyName),
+		Handler: controller.HandleAll(func(interface{}) {
+			impl.GlobalResync(knativeservinginformer.Get(ctx).Informer())
+		}),
+	})
+
+	routeClient := routeinjection.Get(ctx)
+	configClient := configinjection.Get(ctx)
+	dynamicClient := dynamicclient.Get(ctx)
 	return &extension{
-		routeClient:  routeinjection.Get(ctx),
-		configClient: configinjection.Get(ctx),
-		kubeclient:   kubeclient.Get(ctx),
+		proxyLister:      proxyInformer.Lister(),
+		configClient:     configClient,
+		kubeclient:       kubeclient.Get(ctx),
+		dynamicClient:    dynamicClient,
+		logLinkProviders: newLogLinkProviders(routeClient, configClient, dynamicClient),
 	}
 }
 
 type extension struct {
-	routeClient  routeclient.Interface
-	configClient configclient.Interface
-	kubeclient   kubernetes.Interface
+	proxyLister   configv1listers.ProxyLister
+	configClient  configclient.Interface
+	kubeclient    kubernetes.Interface
+	dynamicClient dynamic.Interface
+	// logLinkProviders provide the links to the logs of the revisions, by precedence.
+	logLinkProviders []logLinkProvider
 }
 
 func (e *extension) Manifests(ks base.KComponent) ([]mf.Manifest, error) {
@@ -81,17 +99,20 @@ func (e *extension) Manifests(ks base.KComponent) ([]mf.Manifest, error) {
 	if err != nil {
 		return nil, err
 	}
-	return append(monitoringManifests, istioNetPoliciesManifests...), nil
+	configManifest, err := effectiveConfigManifest(ks)
+	if err != nil {
+		return nil, err
+	}
+	manifests := append(monitoringManifests, istioNetPoliciesManifests...)
+	return append(manifests, configManifest), nil
 }
 
 func (e *extension) Transformers(ks base.KComponent) []mf.Transformer {
+	proxy := common.ClusterProxy(e.proxyLister)
+	caBundle := common.CABundle(context.Background(), e.kubeclient, ks.GetNamespace(), customCertsConfigMap(ks))
 	tf := []mf.Transformer{
 		common.InjectCommonLabelIntoNamespace(),
-		common.InjectEnvironmentIntoDeployment("controller", "controller",
-			corev1.EnvVar{Name: "HTTP_PROXY", Value: os.Getenv("HTTP_PROXY")},
-			corev1.EnvVar{Name: "HTTPS_PROXY", Value: os.Getenv("HTTPS_PROXY")},
-			corev1.EnvVar{Name: "NO_PROXY", Value: os.Getenv("NO_PROXY")},
-		),
+		common.InjectProxyTransform(common.ProxyEnvironment(proxy), caBundle, proxyWorkloads...),
 		overrideKourierNamespace(ks),
 		overrideKourierBootstrap(ks),
 		addKourierEnvValues(ks),
@@ -102,6 +123,7 @@ func (e *extension) Transformers(ks base.KComponent) []mf.Transformer {
 	tf = append(tf, enableSecretInformerFilteringTransformers(ks)...)
 	tf = append(tf, monitoring.GetServingTransformers(ks)...)
 	tf = append(tf, overrideActivatorTerminationGracePeriod(ks))
+	tf = append(tf, longRequestTransformers(ks)...)
 	return append(tf, common.DeprecatedAPIsTranformers(e.kubeclient.Discovery())...)
 }
 
@@ -120,6 +142,12 @@ func (e *extension) Reconcile(ctx context.Context, comp base.KComponent) error {
 		ks.Status.MarkDependenciesInstalled()
 	}
 
+	// Derive the timeouts along the request path from the long request profile, if enabled.
+	if err := applyLongRequestProfile(ks); err != nil {
+		ks.Status.MarkInstallFailed(err.Error())
+		return controller.NewPermanentError(err)
+	}
+
 	// Set the default host to the cluster's host.
 	if domain, err := e.fetchClusterHost(ctx); err != nil {
 		return fmt.Errorf("failed to fetch cluster host: %w", err)
@@ -127,11 +155,8 @@ func (e *extension) Reconcile(ctx context.Context, comp base.KComponent) error {
 		common.ConfigureIfConfigmapUnset(&ks.Spec.CommonSpec, "domain", domain, "")
 	}
 
-	// Attempt to locate kibana routeinjection which is available if openshift-logging has been configured
-	if loggingHost := e.fetchLoggingHost(ctx); loggingHost != "" {
-		common.Configure(&ks.Spec.CommonSpec, monitoring.ObservabilityCMName, "logging.revision-url-template",
-			fmt.Sprintf(loggingURLTemplate, loggingHost))
-	}
+	// Link the revisions to their logs, if a log store of OpenShift Logging is available.
+	configureRevisionURLTemplate(ctx, ks, e.logLinkProviders)
 
 	// Override images.
 	// TODO(SRVCOM-1069): Rethink overriding behavior and/or error surfacing.
@@ -187,11 +212,8 @@ func (e *extension) Reconcile(ctx context.Context, comp base.KComponent) error {
 		common.ConfigureIfUnset(&ks.Spec.CommonSpec, monitoring.ObservabilityCMName, monitoring.ObservabilityBackendKey, "none")
 	}
 
-	if !oldResourceRemoved.Load() {
-		if err := e.cleanupOldResources(ctx, ks.GetNamespace()); err != nil {
-			return err
-		}
-		oldResourceRemoved.Store(true)
+	if err := cleanups.Run(ctx, e.dynamicClient, ks, &ks.Status.Status); err != nil {
+		return err
 	}
 
 	return monitoring.ReconcileMonitoringForServing(ctx, e.kubeclient, ks)
@@ -213,60 +235,14 @@ func (e *extension) fetchClusterHost(ctx context.Context) (string, error) {
 	return ingress.Spec.Domain, nil
 }
 
-// fetchLoggingHost fetches the hostname of the Kibana installed by Openshift Logging,
-// if present.
-func (e *extension) fetchLoggingHost(ctx context.Context) string {
-	route, err := e.routeClient.RouteV1().Routes("openshift-logging").Get(ctx, "kibana", metav1.GetOptions{})
-	if err != nil || len(route.Status.Ingress) == 0 {
+// customCertsConfigMap returns the name of the ConfigMap holding the custom certificates of the
+// controller, including the cluster CA bundle, empty if they aren't taken from a ConfigMap.
+func customCertsConfigMap(ks base.KComponent) string {
+	certs := ks.(*operatorv1beta1.KnativeServing).Spec.ControllerCustomCerts
+	if certs.Type != "ConfigMap" {
 		return ""
 	}
-	return route.Status.Ingress[0].Host
-}
-
-// cleanupOldResources util function to clean up old, deprecated or dangling resources from Serving features.
-func (e *extension) cleanupOldResources(ctx context.Context, ns string) error {
-	client := e.kubeclient
-	// DomainMapping related resources
-	for _, dep := range []string{"domain-mapping", "domainmapping-webhook"} {
-		if err := client.AppsV1().Deployments(ns).Delete(ctx, dep, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
-			return fmt.Errorf("failed to delete deployment %s: %w", dep, err)
-		}
-	}
-	// Delete the rest of the domain mapping resources
-	for _, svc := range []string{"domainmapping-webhook", "domain-mapping-sm-service", "domainmapping-webhook-sm-service"} {
-		if err := client.CoreV1().Services(ns).Delete(ctx, svc, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
-			return fmt.Errorf("failed to delete service %s: %w", svc, err)
-		}
-	}
-	leases, err := client.CoordinationV1().Leases(ns).List(ctx, metav1.ListOptions{})
-	if err != nil {
-		return err
-	}
-	for _, lease := range leases.Items {
-		if strings.HasPrefix(lease.Name, "domainmapping") ||
-			strings.HasPrefix(lease.Name, "net-certmanager") ||
-			strings.HasPrefix(lease.Name, networkingCertificatesReconcilerLease) || strings.HasPrefix(lease.Name, controlProtocolCertificatesReconcilerLease) {
-			if err := client.CoordinationV1().Leases(ns).Delete(ctx, lease.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
-				return fmt.Errorf("failed to delete lease %s: %w", lease.Name, err)
-			}
-		}
-	}
-	if err := client.CoreV1().Secrets(ns).Delete(ctx, "domainmapping-webhook-certs", metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
-		return fmt.Errorf("failed to delete secret domainmapping-webhook-certs: %w", err)
-	}
-	if err := client.AdmissionregistrationV1().MutatingWebhookConfigurations().Delete(ctx, "webhook.domainmapping.serving.knative.dev", metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
-		return fmt.Errorf("failed to delete mutating webhook configuration webhook.domainmapping.serving.knative.dev: %w", err)
-	}
-	if err := client.AdmissionregistrationV1().ValidatingWebhookConfigurations().Delete(ctx, "validation.webhook.domainmapping.serving.knative.dev", metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
-		return fmt.Errorf("failed to delete validating webhook configuration validation.webhook.domainmapping.serving.knative.dev: %w", err)
-	}
-
-	// SRVKS-1264 - deprecated TLS secret
-	if err := client.CoreV1().Secrets(ns).Delete(ctx, "control-serving-certs", metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
-		return fmt.Errorf("failed to delete old internal TLS secret: %w", err)
-	}
-
-	return nil
+	return certs.Name
 }
 
 func overrideActivatorTerminationGracePeriod(ks base.KComponent) mf.Transformer {
@@ -8,18 +8,25 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	mf "github.com/manifestival/manifestival"
 	"github.com/openshift-knative/serverless-operator/openshift-knative-operator/pkg/common"
 	"github.com/openshift-knative/serverless-operator/openshift-knative-operator/pkg/monitoring"
 	configv1 "github.com/openshift/api/config/v1"
 	routev1 "github.com/openshift/api/route/v1"
+	configv1listers "github.com/openshift/client-go/config/listers/config/v1"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/version"
 	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/utils/ptr"
 	"knative.dev/operator/pkg/apis/operator/base"
 	operatorv1beta1 "knative.dev/operator/pkg/apis/operator/v1beta1"
@@ -32,6 +39,7 @@ import (
 	configfake "github.com/openshift-knative/serverless-operator/pkg/client/config/injection/client/fake"
 	routeinjection "github.com/openshift-knative/serverless-operator/pkg/client/route/injection/client"
 	routefake "github.com/openshift-knative/serverless-operator/pkg/client/route/injection/client/fake"
+	ingressconfig "github.com/openshift-knative/serverless-operator/serving/ingress/pkg/reconciler/ingress/config"
 )
 
 var (
@@ -85,7 +93,7 @@ func TestReconcile(t *testing.T) {
 	}{{
 		name:     "all nil",
 		in:       &operatorv1beta1.KnativeServing{},
-		expected: ks(),
+		expected: ks(withCompletedCleanups),
 	}, {
 		name: "different HA settings",
 		in: &operatorv1beta1.KnativeServing{
@@ -97,7 +105,7 @@ func TestReconcile(t *testing.T) {
 				},
 			},
 		},
-		expected: ks(func(ks *operatorv1beta1.KnativeServing) {
+		expected: ks(withCompletedCleanups, func(ks *operatorv1beta1.KnativeServing) {
 			ks.Spec.HighAvailability.Replicas = ptr.To(int32(3))
 		}),
 	}, {
@@ -110,7 +118,7 @@ func TestReconcile(t *testing.T) {
 				},
 			},
 		},
-		expected: ks(func(ks *operatorv1beta1.KnativeServing) {
+		expected: ks(withCompletedCleanups, func(ks *operatorv1beta1.KnativeServing) {
 			ks.Spec.ControllerCustomCerts.Type = "Secret"
 			ks.Spec.ControllerCustomCerts.Name = "foo"
 		}),
@@ -134,7 +142,7 @@ func TestReconcile(t *testing.T) {
 				},
 			},
 		},
-		expected: ks(func(ks *operatorv1beta1.KnativeServing) {
+		expected: ks(withCompletedCleanups, func(ks *operatorv1beta1.KnativeServing) {
 			common.Configure(&ks.Spec.CommonSpec, monitoring.ObservabilityCMName, "logging.revision-url-template",
 				fmt.Sprintf(loggingURLTemplate, "logging.example.com"))
 		}),
@@ -152,7 +160,7 @@ func TestReconcile(t *testing.T) {
 				},
 			},
 		},
-		expected: ks(),
+		expected: ks(withCompletedCleanups),
 	}, {
 		name: "override ingress class",
 		in: &operatorv1beta1.KnativeServing{
@@ -166,7 +174,7 @@ func TestReconcile(t *testing.T) {
 				},
 			},
 		},
-		expected: ks(func(ks *operatorv1beta1.KnativeServing) {
+		expected: ks(withCompletedCleanups, func(ks *operatorv1beta1.KnativeServing) {
 			common.Configure(&ks.Spec.CommonSpec, "network", "ingress.class", "foo")
 		}),
 	}, {
@@ -180,7 +188,7 @@ func TestReconcile(t *testing.T) {
 				},
 			},
 		},
-		expected: ks(func(ks *operatorv1beta1.KnativeServing) {
+		expected: ks(withCompletedCleanups, func(ks *operatorv1beta1.KnativeServing) {
 			ks.Spec.Ingress = &operatorv1beta1.IngressConfigs{
 				Kourier: base.KourierIngressConfiguration{
 					Enabled:     true,
@@ -200,7 +208,7 @@ func TestReconcile(t *testing.T) {
 				},
 			},
 		},
-		expected: ks(func(ks *operatorv1beta1.KnativeServing) {
+		expected: ks(withCompletedCleanups, func(ks *operatorv1beta1.KnativeServing) {
 			ks.Spec.Ingress = &operatorv1beta1.IngressConfigs{
 				Kourier: base.KourierIngressConfiguration{
 					Enabled:     true,
@@ -219,7 +227,7 @@ func TestReconcile(t *testing.T) {
 				},
 			},
 		},
-		expected: ks(func(ks *operatorv1beta1.KnativeServing) {
+		expected: ks(withCompletedCleanups, func(ks *operatorv1beta1.KnativeServing) {
 			ks.Spec.Ingress = &operatorv1beta1.IngressConfigs{
 				Istio: base.IstioIngressConfiguration{
 					Enabled: true,
@@ -245,7 +253,7 @@ func TestReconcile(t *testing.T) {
 				},
 			},
 		},
-		expected: ks(func(ks *operatorv1beta1.KnativeServing) {
+		expected: ks(withCompletedCleanups, func(ks *operatorv1beta1.KnativeServing) {
 			ks.Spec.Ingress = &operatorv1beta1.IngressConfigs{
 				Kourier: base.KourierIngressConfiguration{
 					Enabled:     true,
@@ -265,7 +273,7 @@ func TestReconcile(t *testing.T) {
 				},
 			},
 		},
-		expected: ks(func(ks *operatorv1beta1.KnativeServing) {
+		expected: ks(withCompletedCleanups, func(ks *operatorv1beta1.KnativeServing) {
 			ks.Spec.Ingress = &operatorv1beta1.IngressConfigs{
 				Kourier: base.KourierIngressConfiguration{
 					Enabled:     true,
@@ -286,7 +294,7 @@ func TestReconcile(t *testing.T) {
 				},
 			},
 		},
-		expected: ks(func(ks *operatorv1beta1.KnativeServing) {
+		expected: ks(withCompletedCleanups, func(ks *operatorv1beta1.KnativeServing) {
 			common.Configure(&ks.Spec.CommonSpec, "network", "defaultExternalScheme", "http")
 		}),
 	}, {
@@ -302,7 +310,7 @@ func TestReconcile(t *testing.T) {
 				},
 			},
 		},
-		expected: ks(func(ks *operatorv1beta1.KnativeServing) {
+		expected: ks(withCompletedCleanups, func(ks *operatorv1beta1.KnativeServing) {
 			common.Configure(&ks.Spec.CommonSpec, "network", "autocreateClusterDomainClaims", "false")
 		}),
 	}, {
@@ -310,7 +318,7 @@ func TestReconcile(t *testing.T) {
 		in: ks(func(ks *operatorv1beta1.KnativeServing) {
 			ks.Status.MarkDependenciesInstalled()
 		}),
-		expected: ks(func(ks *operatorv1beta1.KnativeServing) {
+		expected: ks(withCompletedCleanups, func(ks *operatorv1beta1.KnativeServing) {
 			ks.Status.MarkDependenciesInstalled()
 		}),
 	}, {
@@ -322,6 +330,42 @@ func TestReconcile(t *testing.T) {
 			ks.Namespace = "foo"
 			ks.Status.MarkInstallFailed(`Knative Serving must be installed into the namespace "knative-serving"`)
 		}),
+	}, {
+		name: "long request profile",
+		in: &operatorv1beta1.KnativeServing{
+			ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{LongRequestTimeoutAnnotation: "3600"}},
+		},
+		expected: ks(withCompletedCleanups, func(ks *operatorv1beta1.KnativeServing) {
+			ks.Annotations = map[string]string{LongRequestTimeoutAnnotation: "3600"}
+			common.Configure(&ks.Spec.CommonSpec, "defaults", maxRevisionTimeoutSeconds, "3600")
+			common.Configure(&ks.Spec.CommonSpec, "network", ingressconfig.RouteTimeoutKey, "3600")
+			common.Configure(&ks.Spec.CommonSpec, "kourier", streamIdleTimeoutKey, "3600s")
+		}),
+	}, {
+		name: "long request profile cutting requests short",
+		in: &operatorv1beta1.KnativeServing{
+			ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{LongRequestTimeoutAnnotation: "3600"}},
+			Spec: operatorv1beta1.KnativeServingSpec{
+				CommonSpec: base.CommonSpec{
+					Config: base.ConfigMapData{"defaults": {maxRevisionTimeoutSeconds: "7200"}},
+				},
+			},
+		},
+		expected: func() *operatorv1beta1.KnativeServing {
+			ks := &operatorv1beta1.KnativeServing{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   servingNamespace.Name,
+					Annotations: map[string]string{LongRequestTimeoutAnnotation: "3600"},
+				},
+				Spec: operatorv1beta1.KnativeServingSpec{
+					CommonSpec: base.CommonSpec{
+						Config: base.ConfigMapData{"defaults": {maxRevisionTimeoutSeconds: "7200"}},
+					},
+				},
+			}
+			ks.Status.MarkInstallFailed("max-revision-timeout-seconds 7200 exceeds the long request timeout 3600, the requests would be cut short by the Routes")
+			return ks
+		}(),
 	}}
 
 	for _, c := range cases {
@@ -373,10 +417,14 @@ func newFakeExtension(ctx context.Context, t *testing.T) operator.Extension {
 		GitVersion: defaultK8sVersion,
 	}
 
+	routeClient := routeinjection.Get(ctx)
+	configClient := configinjection.Get(ctx)
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(scheme.Scheme)
 	return &extension{
-		routeClient:  routeinjection.Get(ctx),
-		configClient: configinjection.Get(ctx),
-		kubeclient:   kclient,
+		configClient:     configClient,
+		kubeclient:       kclient,
+		dynamicClient:    dynamicClient,
+		logLinkProviders: newLogLinkProviders(routeClient, configClient, dynamicClient),
 	}
 }
 
@@ -390,7 +438,7 @@ func TestMonitoring(t *testing.T) {
 	}{{
 		name:                  "enable monitoring when monitoring toggle is not defined, backend is not defined",
 		in:                    &operatorv1beta1.KnativeServing{},
-		expected:              ks(),
+		expected:              ks(withCompletedCleanups),
 		setupMonitoringToggle: func() (bool, error) { return true, nil },
 	}, {
 		name: "enable monitoring when monitoring toggle = not defined, backend = defined and not `none`",
@@ -401,7 +449,7 @@ func TestMonitoring(t *testing.T) {
 				},
 			},
 		},
-		expected: ks(func(ks *operatorv1beta1.KnativeServing) {
+		expected: ks(withCompletedCleanups, func(ks *operatorv1beta1.KnativeServing) {
 			common.Configure(&ks.Spec.CommonSpec, monitoring.ObservabilityCMName, monitoring.ObservabilityBackendKey, "prometheus")
 		}),
 		setupMonitoringToggle: func() (bool, error) { return true, nil },
@@ -414,14 +462,14 @@ func TestMonitoring(t *testing.T) {
 				},
 			},
 		},
-		expected: ks(func(ks *operatorv1beta1.KnativeServing) {
+		expected: ks(withCompletedCleanups, func(ks *operatorv1beta1.KnativeServing) {
 			common.Configure(&ks.Spec.CommonSpec, monitoring.ObservabilityCMName, monitoring.ObservabilityBackendKey, "none")
 		}),
 		setupMonitoringToggle: func() (bool, error) { return false, nil },
 	}, {
 		name:                  "enable monitoring when monitoring toggle is on, backend is not defined",
 		in:                    &operatorv1beta1.KnativeServing{},
-		expected:              ks(),
+		expected:              ks(withCompletedCleanups),
 		setupMonitoringToggle: func() (bool, error) { return true, os.Setenv(monitoring.EnableMonitoringEnvVar, "true") },
 	}, {
 		name: "enable monitoring when monitoring toggle is on, backend is defined and not `none`",
@@ -432,7 +480,7 @@ func TestMonitoring(t *testing.T) {
 				},
 			},
 		},
-		expected: ks(func(ks *operatorv1beta1.KnativeServing) {
+		expected: ks(withCompletedCleanups, func(ks *operatorv1beta1.KnativeServing) {
 			common.Configure(&ks.Spec.CommonSpec, monitoring.ObservabilityCMName, monitoring.ObservabilityBackendKey, "prometheus")
 		}),
 		setupMonitoringToggle: func() (bool, error) {
@@ -447,7 +495,7 @@ func TestMonitoring(t *testing.T) {
 				},
 			},
 		},
-		expected: ks(func(ks *operatorv1beta1.KnativeServing) {
+		expected: ks(withCompletedCleanups, func(ks *operatorv1beta1.KnativeServing) {
 			common.Configure(&ks.Spec.CommonSpec, monitoring.ObservabilityCMName, monitoring.ObservabilityBackendKey, "none")
 		}),
 		setupMonitoringToggle: func() (bool, error) {
@@ -456,7 +504,7 @@ func TestMonitoring(t *testing.T) {
 	}, {
 		name: "disable monitoring when monitoring toggle is off, backend is not defined",
 		in:   &operatorv1beta1.KnativeServing{},
-		expected: ks(func(ks *operatorv1beta1.KnativeServing) {
+		expected: ks(withCompletedCleanups, func(ks *operatorv1beta1.KnativeServing) {
 			common.Configure(&ks.Spec.CommonSpec, monitoring.ObservabilityCMName, monitoring.ObservabilityBackendKey, "none")
 		}),
 		setupMonitoringToggle: func() (bool, error) { return false, os.Setenv(monitoring.EnableMonitoringEnvVar, "false") },
@@ -469,7 +517,7 @@ func TestMonitoring(t *testing.T) {
 				},
 			},
 		},
-		expected: ks(func(ks *operatorv1beta1.KnativeServing) {
+		expected: ks(withCompletedCleanups, func(ks *operatorv1beta1.KnativeServing) {
 			common.Configure(&ks.Spec.CommonSpec, monitoring.ObservabilityCMName, monitoring.ObservabilityBackendKey, "prometheus")
 		}),
 		setupMonitoringToggle: func() (bool, error) { return true, os.Setenv(monitoring.EnableMonitoringEnvVar, "false") },
@@ -482,7 +530,7 @@ func TestMonitoring(t *testing.T) {
 				},
 			},
 		},
-		expected: ks(func(ks *operatorv1beta1.KnativeServing) {
+		expected: ks(withCompletedCleanups, func(ks *operatorv1beta1.KnativeServing) {
 			common.Configure(&ks.Spec.CommonSpec, monitoring.ObservabilityCMName, monitoring.ObservabilityBackendKey, "none")
 		}),
 		setupMonitoringToggle: func() (bool, error) { return false, os.Setenv(monitoring.EnableMonitoringEnvVar, "false") },
@@ -522,6 +570,66 @@ func TestMonitoring(t *testing.T) {
 	}
 }
 
+func TestProxyCABundle(t *testing.T) {
+	bundle := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "config-service-ca", Namespace: servingNamespace.Name},
+		Data:       map[string]string{common.TrustedCAKey: "bundle"},
+	}
+	ctx, _ := routefake.With(context.Background())
+	ctx, _ = configfake.With(ctx, defaultIngress)
+	ctx, kube := kubefake.With(ctx, &servingNamespace, bundle)
+	ext := newFakeExtension(ctx, t).(*extension)
+	proxies := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	proxies.Add(&configv1.Proxy{
+		ObjectMeta: metav1.ObjectMeta{Name: common.ClusterProxyName},
+		Status:     configv1.ProxyStatus{HTTPProxy: "http://proxy:3128"},
+	})
+	ext.proxyLister = configv1listers.NewProxyLister(proxies)
+
+	checksum := func() string {
+		controller := &appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+			ObjectMeta: metav1.ObjectMeta{Name: "controller", Namespace: servingNamespace.Name},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "controller"}}},
+				},
+			},
+		}
+		u := &unstructured.Unstructured{}
+		if err := scheme.Scheme.Convert(controller, u, nil); err != nil {
+			t.Fatal("Failed to convert deployment to unstructured", err)
+		}
+		manifest, err := mf.ManifestFrom(mf.Slice([]unstructured.Unstructured{*u}))
+		if err != nil {
+			t.Fatal("Failed to create manifest", err)
+		}
+		manifest, err = manifest.Transform(ext.Transformers(ks())...)
+		if err != nil {
+			t.Fatal("Failed to transform manifest", err)
+		}
+		annotations, _, _ := unstructured.NestedStringMap(manifest.Resources()[0].Object, "spec", "template", "metadata", "annotations")
+		return annotations[common.ProxyChecksumAnnotation]
+	}
+
+	before := checksum()
+	bundle.Data[common.TrustedCAKey] = "rotated bundle"
+	if _, err := kube.CoreV1().ConfigMaps(servingNamespace.Name).Update(context.Background(), bundle, metav1.UpdateOptions{}); err != nil {
+		t.Fatal("Failed to update the CA bundle", err)
+	}
+	if after := checksum(); after == before {
+		t.Errorf("Proxy checksum = %q, want it changed with the contents of the CA bundle", after)
+	}
+}
+
+// withCompletedCleanups records the legacy resource cleanups of Serving as completed, as they are
+// by a successful reconciliation.
+func withCompletedCleanups(ks *operatorv1beta1.KnativeServing) {
+	ks.Status.Annotations = map[string]string{
+		common.CompletedCleanupsAnnotation: "certificate-reconciler-leases,control-serving-certs,domain-mapping",
+	}
+}
+
 func ks(mods ...func(*operatorv1beta1.KnativeServing)) *operatorv1beta1.KnativeServing {
 	base := &operatorv1beta1.KnativeServing{
 		ObjectMeta: metav1.ObjectMeta{
@@ -0,0 +1,119 @@
+package serving
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	configclient "github.com/openshift/client-go/config/clientset/versioned"
+	routeclient "github.com/openshift/client-go/route/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	operatorv1beta1 "knative.dev/operator/pkg/apis/operator/v1beta1"
+
+	"github.com/openshift-knative/serverless-operator/openshift-knative-operator/pkg/common"
+	"github.com/openshift-knative/serverless-operator/openshift-knative-operator/pkg/monitoring"
+)
+
+const (
+	revisionURLTemplateKey = "logging.revision-url-template"
+	revisionUIDPlaceholder = "${REVISION_UID}"
+
+	loggingURLTemplate = "https://%s/app/kibana#/discover?_a=(index:.all,query:'kubernetes.labels.serving_knative_dev%%5C%%2FrevisionUID:${REVISION_UID}')"
+
+	// loggingConsolePlugin is the console plugin of OpenShift Logging, showing the logs stored in Loki.
+	loggingConsolePlugin = "logging-view-plugin"
+	// lokiRevisionQuery selects the logs of a revision, by the label of its pods.
+	lokiRevisionQuery = `{log_type="application"} | json | kubernetes_labels_serving_knative_dev_revisionUID="` + revisionUIDPlaceholder + `"`
+)
+
+var (
+	consolePluginResource   = schema.GroupVersionResource{Group: "console.openshift.io", Version: "v1", Resource: "consoleplugins"}
+	operatorConsoleResource = schema.GroupVersionResource{Group: "operator.openshift.io", Version: "v1", Resource: "consoles"}
+)
+
+// logLinkProvider detects a log store and provides the template of the links to the logs of
+// a revision, configured as logging.revision-url-template.
+type logLinkProvider interface {
+	// revisionURLTemplate returns the template, with ${REVISION_UID} substituted by the UID of the
+	// revision, empty if the log store isn't available.
+	revisionURLTemplate(ctx context.Context, ks *operatorv1beta1.KnativeServing) string
+}
+
+// newLogLinkProviders returns the providers of the revision log links, by precedence.
+func newLogLinkProviders(routeClient routeclient.Interface, configClient configclient.Interface, dynamicClient dynamic.Interface) []logLinkProvider {
+	return []logLinkProvider{
+		userTemplateProvider{},
+		lokiConsoleProvider{configClient: configClient, dynamicClient: dynamicClient},
+		kibanaProvider{routeClient: routeClient},
+	}
+}
+
+// configureRevisionURLTemplate sets logging.revision-url-template to the template of the first
+// provider detecting its log store, if any.
+func configureRevisionURLTemplate(ctx context.Context, ks *operatorv1beta1.KnativeServing, providers []logLinkProvider) {
+	for _, provider := range providers {
+		if template := provider.revisionURLTemplate(ctx, ks); template != "" {
+			common.Configure(&ks.Spec.CommonSpec, monitoring.ObservabilityCMName, revisionURLTemplateKey, template)
+			return
+		}
+	}
+}
+
+// userTemplateProvider keeps the template set by the user in the observability config of
+// KnativeServing.
+type userTemplateProvider struct{}
+
+func (userTemplateProvider) revisionURLTemplate(_ context.Context, ks *operatorv1beta1.KnativeServing) string {
+	return monitoring.GetCmDataforName(ks.Spec.Config, "config-"+monitoring.ObservabilityCMName)[revisionURLTemplateKey]
+}
+
+// lokiConsoleProvider links to the logs page of the OpenShift console, when the logging console
+// plugin showing the logs stored in Loki is enabled.
+type lokiConsoleProvider struct {
+	configClient  configclient.Interface
+	dynamicClient dynamic.Interface
+}
+
+func (p lokiConsoleProvider) revisionURLTemplate(ctx context.Context, _ *operatorv1beta1.KnativeServing) string {
+	if _, err := p.dynamicClient.Resource(consolePluginResource).Get(ctx, loggingConsolePlugin, metav1.GetOptions{}); err != nil {
+		return ""
+	}
+	console, err := p.dynamicClient.Resource(operatorConsoleResource).Get(ctx, "cluster", metav1.GetOptions{})
+	if err != nil {
+		return ""
+	}
+	plugins, _, _ := unstructured.NestedStringSlice(console.Object, "spec", "plugins")
+	enabled := false
+	for _, plugin := range plugins {
+		enabled = enabled || plugin == loggingConsolePlugin
+	}
+	if !enabled {
+		return ""
+	}
+
+	config, err := p.configClient.ConfigV1().Consoles().Get(ctx, "cluster", metav1.GetOptions{})
+	if err != nil || config.Status.ConsoleURL == "" {
+		return ""
+	}
+	// Escape the query around the placeholder, which must remain as is to be substituted.
+	query := strings.Split(lokiRevisionQuery, revisionUIDPlaceholder)
+	return fmt.Sprintf("%s/monitoring/logs?q=%s%s%s", strings.TrimSuffix(config.Status.ConsoleURL, "/"),
+		url.QueryEscape(query[0]), revisionUIDPlaceholder, url.QueryEscape(query[1]))
+}
+
+// kibanaProvider links to the Kibana installed by former versions of OpenShift Logging, if present.
+type kibanaProvider struct {
+	routeClient routeclient.Interface
+}
+
+func (p kibanaProvider) revisionURLTemplate(ctx context.Context, _ *operatorv1beta1.KnativeServing) string {
+	route, err := p.routeClient.RouteV1().Routes("openshift-logging").Get(ctx, "kibana", metav1.GetOptions{})
+	if err != nil || len(route.Status.Ingress) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(loggingURLTemplate, route.Status.Ingress[0].Host)
+}
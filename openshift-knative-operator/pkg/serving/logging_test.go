@@ -0,0 +1,132 @@
+package serving
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	routev1 "github.com/openshift/api/route/v1"
+	configfake "github.com/openshift/client-go/config/clientset/versioned/fake"
+	routefake "github.com/openshift/client-go/route/clientset/versioned/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"knative.dev/operator/pkg/apis/operator/base"
+	operatorv1beta1 "knative.dev/operator/pkg/apis/operator/v1beta1"
+
+	"github.com/openshift-knative/serverless-operator/openshift-knative-operator/pkg/monitoring"
+)
+
+func TestRevisionURLTemplate(t *testing.T) {
+	kibanaRoute := &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-logging", Name: "kibana"},
+		Status: routev1.RouteStatus{
+			Ingress: []routev1.RouteIngress{{Host: "kibana.example.com"}},
+		},
+	}
+	console := &configv1.Console{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Status:     configv1.ConsoleStatus{ConsoleURL: "https://console.example.com/"},
+	}
+	consolePlugin := func(name string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(consolePluginResource.GroupVersion().WithKind("ConsolePlugin"))
+		u.SetName(name)
+		return u
+	}
+	operatorConsole := func(plugins ...interface{}) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(operatorConsoleResource.GroupVersion().WithKind("Console"))
+		u.SetName("cluster")
+		if err := unstructured.SetNestedSlice(u.Object, plugins, "spec", "plugins"); err != nil {
+			t.Fatal("Failed to set plugins", err)
+		}
+		return u
+	}
+	userConfig := func(cm string) *operatorv1beta1.KnativeServing {
+		return &operatorv1beta1.KnativeServing{
+			Spec: operatorv1beta1.KnativeServingSpec{
+				CommonSpec: base.CommonSpec{
+					Config: base.ConfigMapData{cm: {revisionURLTemplateKey: "https://logs.example.com/${REVISION_UID}"}},
+				},
+			},
+		}
+	}
+	lokiTemplate := "https://console.example.com/monitoring/logs?q=%7Blog_type%3D%22application%22%7D+%7C+json+%7C+" +
+		"kubernetes_labels_serving_knative_dev_revisionUID%3D%22${REVISION_UID}%22"
+
+	tests := []struct {
+		name    string
+		ks      *operatorv1beta1.KnativeServing
+		routes  []runtime.Object
+		configs []runtime.Object
+		objs    []runtime.Object
+		want    string
+	}{{
+		name: "no log store",
+		ks:   &operatorv1beta1.KnativeServing{},
+	}, {
+		name:   "kibana",
+		ks:     &operatorv1beta1.KnativeServing{},
+		routes: []runtime.Object{kibanaRoute},
+		want:   fmt.Sprintf(loggingURLTemplate, "kibana.example.com"),
+	}, {
+		name:    "loki console plugin",
+		ks:      &operatorv1beta1.KnativeServing{},
+		configs: []runtime.Object{console},
+		objs:    []runtime.Object{consolePlugin(loggingConsolePlugin), operatorConsole("monitoring-plugin", loggingConsolePlugin)},
+		want:    lokiTemplate,
+	}, {
+		name:    "loki console plugin takes precedence over kibana",
+		ks:      &operatorv1beta1.KnativeServing{},
+		routes:  []runtime.Object{kibanaRoute},
+		configs: []runtime.Object{console},
+		objs:    []runtime.Object{consolePlugin(loggingConsolePlugin), operatorConsole(loggingConsolePlugin)},
+		want:    lokiTemplate,
+	}, {
+		name:    "loki console plugin not enabled",
+		ks:      &operatorv1beta1.KnativeServing{},
+		routes:  []runtime.Object{kibanaRoute},
+		configs: []runtime.Object{console},
+		objs:    []runtime.Object{consolePlugin(loggingConsolePlugin), operatorConsole("monitoring-plugin")},
+		want:    fmt.Sprintf(loggingURLTemplate, "kibana.example.com"),
+	}, {
+		name:    "loki console plugin not installed",
+		ks:      &operatorv1beta1.KnativeServing{},
+		configs: []runtime.Object{console},
+		objs:    []runtime.Object{operatorConsole(loggingConsolePlugin)},
+	}, {
+		name:    "user template",
+		ks:      userConfig("observability"),
+		routes:  []runtime.Object{kibanaRoute},
+		configs: []runtime.Object{console},
+		objs:    []runtime.Object{consolePlugin(loggingConsolePlugin), operatorConsole(loggingConsolePlugin)},
+		want:    "https://logs.example.com/${REVISION_UID}",
+	}, {
+		name:   "user template in prefixed config",
+		ks:     userConfig("config-observability"),
+		routes: []runtime.Object{kibanaRoute},
+		want:   "https://logs.example.com/${REVISION_UID}",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+				consolePluginResource:   "ConsolePluginList",
+				operatorConsoleResource: "ConsoleList",
+			}, test.objs...)
+			providers := newLogLinkProviders(routefake.NewSimpleClientset(test.routes...), configfake.NewSimpleClientset(test.configs...), dynamicClient)
+
+			ks := test.ks.DeepCopy()
+			configureRevisionURLTemplate(context.Background(), ks, providers)
+
+			got := monitoring.GetCmDataforName(ks.Spec.Config, "config-"+monitoring.ObservabilityCMName)[revisionURLTemplateKey]
+			if got != test.want {
+				t.Errorf("%s = %q, want %q", revisionURLTemplateKey, got, test.want)
+			}
+		})
+	}
+}
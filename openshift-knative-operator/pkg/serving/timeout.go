@@ -0,0 +1,170 @@
+package serving
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	mf "github.com/manifestival/manifestival"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	"knative.dev/operator/pkg/apis/operator/base"
+	operatorv1beta1 "knative.dev/operator/pkg/apis/operator/v1beta1"
+	"knative.dev/pkg/ptr"
+	servingconfig "knative.dev/serving/pkg/apis/config"
+
+	"github.com/openshift-knative/serverless-operator/openshift-knative-operator/pkg/common"
+	"github.com/openshift-knative/serverless-operator/openshift-knative-operator/pkg/monitoring"
+	ingressconfig "github.com/openshift-knative/serverless-operator/serving/ingress/pkg/reconciler/ingress/config"
+)
+
+const (
+	// LongRequestTimeoutAnnotation opts KnativeServing into the long request profile. Its value is
+	// the duration, in seconds, requests may take. The timeouts of the activator, the Kourier gateway
+	// and the Routes are derived from it.
+	LongRequestTimeoutAnnotation = "serverless.openshift.io/long-request-timeout-seconds"
+
+	// streamIdleTimeoutKey is the key of config-kourier holding the idle timeout of the requests.
+	streamIdleTimeoutKey = "stream-idle-timeout"
+
+	kourierGatewayName      = "3scale-kourier-gateway"
+	kourierGatewayContainer = "kourier-gateway"
+	// kourierDrainTimeEnv is the time the Kourier gateway drains its connections when stopping.
+	kourierDrainTimeEnv = "DRAIN_TIME_SECONDS"
+	// kourierGatewayExitSeconds is the time the Kourier gateway is given to exit after draining.
+	kourierGatewayExitSeconds = 15
+)
+
+// longRequestPDBNames are the PodDisruptionBudgets of the components on the request path.
+var longRequestPDBNames = []string{"activator-pdb", kourierGatewayPDBName}
+
+// longRequestTimeout returns the timeout, in seconds, of the long request profile of the given
+// KnativeServing, 0 if it isn't enabled.
+func longRequestTimeout(ks base.KComponent) (int64, error) {
+	value, ok := ks.GetAnnotations()[LongRequestTimeoutAnnotation]
+	if !ok {
+		return 0, nil
+	}
+	timeout, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("%s: invalid value %q, expected a positive number of seconds", LongRequestTimeoutAnnotation, value)
+	}
+	return timeout, nil
+}
+
+// applyLongRequestProfile derives the timeouts of the configuration of KnativeServing from its long
+// request profile, keeping the ones set explicitly. It rejects the configurations that would cut
+// requests short of the profile's timeout.
+func applyLongRequestProfile(ks *operatorv1beta1.KnativeServing) error {
+	timeout, err := longRequestTimeout(ks)
+	if err != nil || timeout == 0 {
+		return err
+	}
+	if err := validateLongRequestProfile(ks, timeout); err != nil {
+		return err
+	}
+
+	seconds := strconv.FormatInt(timeout, 10)
+	if monitoring.GetCmDataforName(ks.Spec.Config, "config-defaults")[maxRevisionTimeoutSeconds] == "" {
+		common.Configure(&ks.Spec.CommonSpec, "defaults", maxRevisionTimeoutSeconds, seconds)
+	}
+	if monitoring.GetCmDataforName(ks.Spec.Config, "config-"+networkCMName)[ingressconfig.RouteTimeoutKey] == "" {
+		common.Configure(&ks.Spec.CommonSpec, networkCMName, ingressconfig.RouteTimeoutKey, seconds)
+	}
+	if monitoring.GetCmDataforName(ks.Spec.Config, "config-kourier")[streamIdleTimeoutKey] == "" {
+		common.Configure(&ks.Spec.CommonSpec, "kourier", streamIdleTimeoutKey, seconds+"s")
+	}
+	return nil
+}
+
+// validateLongRequestProfile checks that the timeouts set explicitly don't cut requests short
+// of the given timeout, and that the timeout isn't shorter than the default timeout of the
+// revisions, as Serving rejects a max-revision-timeout-seconds below it.
+func validateLongRequestProfile(ks *operatorv1beta1.KnativeServing, timeout int64) error {
+	revisionTimeout := int64(servingconfig.DefaultRevisionTimeoutSeconds)
+	if value := monitoring.GetCmDataforName(ks.Spec.Config, "config-defaults")[revisionTimeoutSeconds]; value != "" {
+		var err error
+		if revisionTimeout, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return fmt.Errorf("%s: invalid value %q: %w", revisionTimeoutSeconds, value, err)
+		}
+	}
+	if timeout < revisionTimeout {
+		return fmt.Errorf("the long request timeout %d is shorter than %s %d", timeout, revisionTimeoutSeconds, revisionTimeout)
+	}
+	if value := monitoring.GetCmDataforName(ks.Spec.Config, "config-defaults")[maxRevisionTimeoutSeconds]; value != "" {
+		maxTimeout, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%s: invalid value %q: %w", maxRevisionTimeoutSeconds, value, err)
+		}
+		if maxTimeout > timeout {
+			return fmt.Errorf("%s %d exceeds the long request timeout %d, the requests would be cut short by the Routes",
+				maxRevisionTimeoutSeconds, maxTimeout, timeout)
+		}
+	}
+	if value := monitoring.GetCmDataforName(ks.Spec.Config, "config-"+networkCMName)[ingressconfig.RouteTimeoutKey]; value != "" {
+		routeTimeout, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%s: invalid value %q: %w", ingressconfig.RouteTimeoutKey, value, err)
+		}
+		if routeTimeout < timeout {
+			return fmt.Errorf("%s %d is shorter than the long request timeout %d", ingressconfig.RouteTimeoutKey, routeTimeout, timeout)
+		}
+	}
+	if value := monitoring.GetCmDataforName(ks.Spec.Config, "config-kourier")[streamIdleTimeoutKey]; value != "" {
+		idleTimeout, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("%s: invalid value %q: %w", streamIdleTimeoutKey, value, err)
+		}
+		// A zero idle timeout disables it.
+		if idleTimeout != 0 && idleTimeout < time.Duration(timeout)*time.Second {
+			return fmt.Errorf("%s %s is shorter than the long request timeout %d", streamIdleTimeoutKey, value, timeout)
+		}
+	}
+	for _, override := range ks.Spec.PodDisruptionBudgetOverride {
+		for _, name := range longRequestPDBNames {
+			if override.Name == name && override.MinAvailable != nil && isZero(*override.MinAvailable) {
+				return fmt.Errorf("PodDisruptionBudget %s allows evicting all the pods draining long requests at once", name)
+			}
+		}
+	}
+	return nil
+}
+
+func isZero(v intstr.IntOrString) bool {
+	if v.Type == intstr.Int {
+		return v.IntVal == 0
+	}
+	return v.StrVal == "0%" || v.StrVal == "0"
+}
+
+// longRequestTransformers lets the Kourier gateway drain the requests of the long request profile
+// when stopping.
+func longRequestTransformers(ks base.KComponent) []mf.Transformer {
+	timeout, err := longRequestTimeout(ks)
+	if err != nil || timeout == 0 {
+		return nil
+	}
+	return []mf.Transformer{
+		common.InjectEnvironmentIntoDeployment(kourierGatewayName, kourierGatewayContainer,
+			corev1.EnvVar{Name: kourierDrainTimeEnv, Value: strconv.FormatInt(timeout, 10)}),
+		overrideTerminationGracePeriod(kourierGatewayName, timeout+kourierGatewayExitSeconds),
+	}
+}
+
+// overrideTerminationGracePeriod sets the termination grace period of the pods of the given Deployment.
+func overrideTerminationGracePeriod(name string, seconds int64) mf.Transformer {
+	return func(u *unstructured.Unstructured) error {
+		if u.GetKind() != "Deployment" || u.GetName() != name {
+			return nil
+		}
+		dep := &appsv1.Deployment{}
+		if err := scheme.Scheme.Convert(u, dep, nil); err != nil {
+			return err
+		}
+		dep.Spec.Template.Spec.TerminationGracePeriodSeconds = ptr.Int64(seconds)
+		return scheme.Scheme.Convert(dep, u, nil)
+	}
+}
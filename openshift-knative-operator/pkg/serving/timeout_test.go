@@ -0,0 +1,187 @@
+package serving
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	"knative.dev/operator/pkg/apis/operator/base"
+	operatorv1beta1 "knative.dev/operator/pkg/apis/operator/v1beta1"
+	"knative.dev/pkg/ptr"
+
+	ingressconfig "github.com/openshift-knative/serverless-operator/serving/ingress/pkg/reconciler/ingress/config"
+)
+
+func TestApplyLongRequestProfile(t *testing.T) {
+	longRequest := func(timeout string, config base.ConfigMapData, pdbs ...base.PodDisruptionBudgetOverride) *operatorv1beta1.KnativeServing {
+		ks := &operatorv1beta1.KnativeServing{
+			ObjectMeta: metav1.ObjectMeta{Name: "knative-serving", Namespace: "knative-serving"},
+			Spec: operatorv1beta1.KnativeServingSpec{
+				CommonSpec: base.CommonSpec{
+					Config:                      config,
+					PodDisruptionBudgetOverride: pdbs,
+				},
+			},
+		}
+		if timeout != "" {
+			ks.Annotations = map[string]string{LongRequestTimeoutAnnotation: timeout}
+		}
+		return ks
+	}
+	minAvailable := func(name string, v intstr.IntOrString) base.PodDisruptionBudgetOverride {
+		return base.PodDisruptionBudgetOverride{
+			Name:                    name,
+			PodDisruptionBudgetSpec: policyv1.PodDisruptionBudgetSpec{MinAvailable: &v},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		in      *operatorv1beta1.KnativeServing
+		want    base.ConfigMapData
+		wantErr bool
+	}{{
+		name: "profile disabled",
+		in:   longRequest("", nil),
+	}, {
+		name: "derived timeouts",
+		in:   longRequest("3600", nil),
+		want: base.ConfigMapData{
+			"defaults": {maxRevisionTimeoutSeconds: "3600"},
+			"network":  {ingressconfig.RouteTimeoutKey: "3600"},
+			"kourier":  {streamIdleTimeoutKey: "3600s"},
+		},
+	}, {
+		name: "explicit timeouts not cutting requests short",
+		in: longRequest("3600", base.ConfigMapData{
+			"config-defaults": {maxRevisionTimeoutSeconds: "1800"},
+			"network":         {ingressconfig.RouteTimeoutKey: "7200"},
+			"kourier":         {streamIdleTimeoutKey: "0s"},
+		}),
+		want: base.ConfigMapData{
+			"config-defaults": {maxRevisionTimeoutSeconds: "1800"},
+			"network":         {ingressconfig.RouteTimeoutKey: "7200"},
+			"kourier":         {streamIdleTimeoutKey: "0s"},
+		},
+	}, {
+		name:    "timeout shorter than the default revision timeout",
+		in:      longRequest("120", nil),
+		wantErr: true,
+	}, {
+		name:    "timeout shorter than the revision timeout",
+		in:      longRequest("600", base.ConfigMapData{"defaults": {revisionTimeoutSeconds: "900"}}),
+		wantErr: true,
+	}, {
+		name: "timeout not shorter than the revision timeout",
+		in:   longRequest("120", base.ConfigMapData{"defaults": {revisionTimeoutSeconds: "60"}}),
+		want: base.ConfigMapData{
+			"defaults": {revisionTimeoutSeconds: "60", maxRevisionTimeoutSeconds: "120"},
+			"network":  {ingressconfig.RouteTimeoutKey: "120"},
+			"kourier":  {streamIdleTimeoutKey: "120s"},
+		},
+	}, {
+		name:    "invalid timeout",
+		in:      longRequest("1h", nil),
+		wantErr: true,
+	}, {
+		name:    "max revision timeout exceeding the profile",
+		in:      longRequest("3600", base.ConfigMapData{"defaults": {maxRevisionTimeoutSeconds: "7200"}}),
+		wantErr: true,
+	}, {
+		name:    "route timeout shorter than the profile",
+		in:      longRequest("3600", base.ConfigMapData{"config-network": {ingressconfig.RouteTimeoutKey: "600"}}),
+		wantErr: true,
+	}, {
+		name:    "kourier idle timeout shorter than the profile",
+		in:      longRequest("3600", base.ConfigMapData{"kourier": {streamIdleTimeoutKey: "5m"}}),
+		wantErr: true,
+	}, {
+		name:    "activator evicted at once",
+		in:      longRequest("3600", nil, minAvailable("activator-pdb", intstr.FromInt(0))),
+		wantErr: true,
+	}, {
+		name:    "kourier gateway evicted at once",
+		in:      longRequest("3600", nil, minAvailable("3scale-kourier-gateway-pdb", intstr.FromString("0%"))),
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ks := test.in.DeepCopy()
+			err := applyLongRequestProfile(ks)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("applyLongRequestProfile() = %v, wantErr %v", err, test.wantErr)
+			}
+			if test.wantErr {
+				return
+			}
+			if !cmp.Equal(ks.Spec.Config, test.want) {
+				t.Error("Unexpected config (-want, +got):", cmp.Diff(test.want, ks.Spec.Config))
+			}
+		})
+	}
+}
+
+func TestLongRequestTransformers(t *testing.T) {
+	gateway := func(grace int64, drain string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: kourierGatewayName},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						TerminationGracePeriodSeconds: ptr.Int64(grace),
+						Containers: []corev1.Container{{
+							Name: kourierGatewayContainer,
+							Env:  []corev1.EnvVar{{Name: kourierDrainTimeEnv, Value: drain}},
+						}},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		timeout string
+		want    *appsv1.Deployment
+	}{{
+		name: "profile disabled",
+		want: gateway(30, "15"),
+	}, {
+		name:    "drain long requests",
+		timeout: "3600",
+		want:    gateway(3615, "3600"),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ks := &operatorv1beta1.KnativeServing{}
+			if test.timeout != "" {
+				ks.Annotations = map[string]string{LongRequestTimeoutAnnotation: test.timeout}
+			}
+
+			u := &unstructured.Unstructured{}
+			if err := scheme.Scheme.Convert(gateway(30, "15"), u, nil); err != nil {
+				t.Fatal("Failed to convert deployment to unstructured", err)
+			}
+			for _, transform := range longRequestTransformers(ks) {
+				if err := transform(u); err != nil {
+					t.Fatal("Unexpected error from transformer", err)
+				}
+			}
+			got := &appsv1.Deployment{}
+			if err := scheme.Scheme.Convert(u, got, nil); err != nil {
+				t.Fatal("Failed to convert unstructured to deployment", err)
+			}
+			if !cmp.Equal(got, test.want) {
+				t.Error("Unexpected deployment (-want, +got):", cmp.Diff(test.want, got))
+			}
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 var ctors = []injection.ControllerConstructor{
 	ingress.NewIstioController,
 	ingress.NewKourierController,
+	ingress.NewOrphanedRouteSweeper,
 }
 
 func main() {
@@ -0,0 +1,71 @@
+package ingresscontroller
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection"
+	"knative.dev/pkg/injection/clients/dynamicclient"
+	"knative.dev/pkg/logging"
+)
+
+// Namespace is the namespace of the IngressControllers, i.e. the router shards of the cluster.
+const Namespace = "openshift-ingress-operator"
+
+// Resource is the resource of the IngressControllers.
+var Resource = schema.GroupVersionResource{
+	Group:    "operator.openshift.io",
+	Version:  "v1",
+	Resource: "ingresscontrollers",
+}
+
+func init() {
+	injection.Default.RegisterInformer(withInformer)
+}
+
+// Key is used for associating the Informer inside the context.Context.
+type Key struct{}
+
+// Informer watches the IngressControllers of the cluster, which aren't part of the generated
+// clients and are thus cached as unstructured objects.
+type Informer struct {
+	informer cache.SharedIndexInformer
+}
+
+// Informer returns the underlying shared informer.
+func (i *Informer) Informer() cache.SharedIndexInformer {
+	return i.informer
+}
+
+// Lister returns a lister backed by the informer.
+func (i *Informer) Lister() cache.GenericLister {
+	return cache.NewGenericLister(i.informer.GetIndexer(), Resource.GroupResource())
+}
+
+func withInformer(ctx context.Context) (context.Context, controller.Informer) {
+	client := dynamicclient.Get(ctx).Resource(Resource).Namespace(Namespace)
+	inf := cache.NewSharedIndexInformer(&cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			return client.List(ctx, opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			return client.Watch(ctx, opts)
+		},
+	}, &unstructured.Unstructured{}, controller.GetResyncPeriod(ctx), cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	return context.WithValue(ctx, Key{}, &Informer{informer: inf}), inf
+}
+
+// Get extracts the IngressController informer from the context.
+func Get(ctx context.Context) *Informer {
+	untyped := ctx.Value(Key{})
+	if untyped == nil {
+		logging.FromContext(ctx).Panic("Unable to fetch the IngressController informer from context.")
+	}
+	return untyped.(*Informer)
+}
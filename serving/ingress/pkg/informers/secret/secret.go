@@ -0,0 +1,57 @@
+package secret
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1informers "k8s.io/client-go/informers/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection"
+	"knative.dev/pkg/logging"
+
+	"github.com/openshift-knative/serverless-operator/serving/ingress/pkg/reconciler/ingress/resources"
+)
+
+func init() {
+	injection.Default.RegisterInformer(withInformer)
+}
+
+// Key is used for associating the Informer inside the context.Context.
+type Key struct{}
+
+// Informer watches the Secrets labeled to be used as Route certificates only, so the
+// controller doesn't cache all the Secrets of the cluster.
+type Informer struct {
+	informer cache.SharedIndexInformer
+}
+
+// Informer returns the underlying shared informer.
+func (i *Informer) Informer() cache.SharedIndexInformer {
+	return i.informer
+}
+
+// Lister returns a lister backed by the informer.
+func (i *Informer) Lister() corev1listers.SecretLister {
+	return corev1listers.NewSecretLister(i.informer.GetIndexer())
+}
+
+func withInformer(ctx context.Context) (context.Context, controller.Informer) {
+	inf := corev1informers.NewFilteredSecretInformer(kubeclient.Get(ctx), metav1.NamespaceAll,
+		controller.GetResyncPeriod(ctx), cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+		func(opts *metav1.ListOptions) {
+			opts.LabelSelector = resources.TLSSecretLabelKey
+		})
+	return context.WithValue(ctx, Key{}, &Informer{informer: inf}), inf
+}
+
+// Get extracts the Secret informer from the context.
+func Get(ctx context.Context) *Informer {
+	untyped := ctx.Value(Key{})
+	if untyped == nil {
+		logging.FromContext(ctx).Panic("Unable to fetch the Route certificate Secret informer from context.")
+	}
+	return untyped.(*Informer)
+}
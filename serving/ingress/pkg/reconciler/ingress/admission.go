@@ -0,0 +1,167 @@
+package ingress
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/controller"
+)
+
+const (
+	// IngressConditionRouteAdmitted reflects whether the OpenShift router admitted the Routes
+	// exposing the Ingress. It isn't part of the Ingress' Ready condition, a rejection reaches
+	// the Knative Route and Service through the LoadBalancerReady condition instead.
+	IngressConditionRouteAdmitted apis.ConditionType = "RouteAdmitted"
+
+	routeRejectedReason = "RouteRejected"
+	routePendingReason  = "RouteNotAdmitted"
+)
+
+// admissionConditions only manages the RouteAdmitted condition. It doesn't recompute the happy
+// condition as it has no dependents.
+var admissionConditions = apis.NewLivingConditionSet()
+
+// routeAdmission projects the admission status of the given Routes, as reported by the routers,
+// into a RouteAdmitted condition. It returns nil if no router reported on any of the Routes yet.
+func routeAdmission(routes []*routev1.Route) *apis.Condition {
+	reported := false
+	var pending []string
+	for _, route := range routes {
+		admitted := false
+		for _, ingress := range route.Status.Ingress {
+			for _, cond := range ingress.Conditions {
+				if cond.Type != routev1.RouteAdmitted {
+					continue
+				}
+				reported = true
+				switch cond.Status {
+				case corev1.ConditionTrue:
+					admitted = true
+				case corev1.ConditionFalse:
+					reason := cond.Reason
+					if reason == "" {
+						reason = routeRejectedReason
+					}
+					return &apis.Condition{
+						Type:     IngressConditionRouteAdmitted,
+						Status:   corev1.ConditionFalse,
+						Severity: apis.ConditionSeverityInfo,
+						Reason:   reason,
+						Message: fmt.Sprintf("Route %s/%s for host %q was rejected by router shard %q: %s",
+							route.Namespace, route.Name, ingress.Host, ingress.RouterName, cond.Message),
+					}
+				}
+			}
+		}
+		if !admitted {
+			pending = append(pending, route.Namespace+"/"+route.Name)
+		}
+	}
+
+	if !reported {
+		return nil
+	}
+	if len(pending) > 0 {
+		sort.Strings(pending)
+		return &apis.Condition{
+			Type:     IngressConditionRouteAdmitted,
+			Status:   corev1.ConditionUnknown,
+			Severity: apis.ConditionSeverityInfo,
+			Reason:   routePendingReason,
+			Message:  fmt.Sprintf("Waiting for the router to admit Routes %v", pending),
+		}
+	}
+	return &apis.Condition{
+		Type:     IngressConditionRouteAdmitted,
+		Status:   corev1.ConditionTrue,
+		Severity: apis.ConditionSeverityInfo,
+	}
+}
+
+// reconcileAdmission updates the RouteAdmitted condition of the Ingress from the admission status
+// of its Routes. The condition is removed if the Ingress isn't exposed through Routes.
+func (r *Reconciler) reconcileAdmission(ctx context.Context, ing *v1alpha1.Ingress, desired []*routev1.Route) error {
+	routes := make([]*routev1.Route, 0, len(desired))
+	for _, route := range desired {
+		existing, err := r.routeLister.Routes(route.Namespace).Get(route.Name)
+		if apierrors.IsNotFound(err) {
+			// The Route was just created, the router didn't pick it up yet.
+			routes = append(routes, route)
+			continue
+		} else if err != nil {
+			return fmt.Errorf("failed to get route: %w", err)
+		}
+		routes = append(routes, existing)
+	}
+
+	updated := ing.DeepCopy()
+	cm := admissionConditions.Manage(&updated.Status)
+	previous := cm.GetCondition(IngressConditionRouteAdmitted)
+	cond := routeAdmission(routes)
+	switch {
+	case len(routes) == 0:
+		if err := cm.ClearCondition(IngressConditionRouteAdmitted); err != nil {
+			return err
+		}
+	case cond != nil:
+		cm.SetCondition(*cond)
+		propagateAdmission(&updated.Status, previous, cond)
+	}
+	if equality.Semantic.DeepEqual(updated.Status, ing.Status) {
+		return nil
+	}
+
+	if _, err := r.ingressClient.Ingresses(ing.Namespace).UpdateStatus(ctx, updated, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update ingress status: %w", err)
+	}
+
+	if cond != nil && cond.IsFalse() && (previous == nil || !previous.IsFalse() || previous.Message != cond.Message) {
+		recordRejection(ctx, ing, cond)
+	}
+	return nil
+}
+
+// propagateAdmission fails the LoadBalancerReady condition of the Ingress when a rejection is
+// first reported, so that the Ready condition propagated by the Knative Route to the Service
+// is failed as well. The condition is owned by the networking layer, the rejection isn't
+// asserted again once it marked the load balancer ready so that both don't fight over it. When
+// the Routes are admitted again, the failure is reset for the networking layer to recompute it.
+func propagateAdmission(status *v1alpha1.IngressStatus, previous, cond *apis.Condition) {
+	rejected := previous != nil && previous.IsFalse()
+	switch {
+	case cond.IsFalse() && (!rejected || previous.Message != cond.Message):
+		status.MarkLoadBalancerFailed(cond.Reason, cond.Message)
+	case cond.IsTrue() && rejected:
+		if lb := status.GetCondition(v1alpha1.IngressConditionLoadBalancerReady); lb != nil && lb.IsFalse() && lb.Message == previous.Message {
+			status.MarkLoadBalancerNotReady()
+		}
+	}
+}
+
+// recordRejection records the rejection as events on the Ingress and on the Knative Route owning
+// it. The events aren't recorded on the Knative Service, they are listed by `kubectl get events`
+// in its namespace.
+func recordRejection(ctx context.Context, ing *v1alpha1.Ingress, cond *apis.Condition) {
+	recorder := controller.GetEventRecorder(ctx)
+	if recorder == nil {
+		return
+	}
+	recorder.Event(ing, corev1.EventTypeWarning, cond.Reason, cond.Message)
+	if owner := metav1.GetControllerOf(ing); owner != nil {
+		recorder.Event(&corev1.ObjectReference{
+			APIVersion: owner.APIVersion,
+			Kind:       owner.Kind,
+			Name:       owner.Name,
+			Namespace:  ing.Namespace,
+			UID:        owner.UID,
+		}, corev1.EventTypeWarning, cond.Reason, cond.Message)
+	}
+}
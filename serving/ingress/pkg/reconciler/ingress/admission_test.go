@@ -0,0 +1,48 @@
+package ingress
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+	"knative.dev/pkg/apis"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+func TestRejectionReachesService(t *testing.T) {
+	rejection := &apis.Condition{
+		Type:    IngressConditionRouteAdmitted,
+		Status:  corev1.ConditionFalse,
+		Reason:  "HostAlreadyClaimed",
+		Message: "rejected",
+	}
+
+	// The networking layer exposed the Ingress.
+	status := &v1alpha1.IngressStatus{}
+	status.InitializeConditions()
+	status.MarkNetworkConfigured()
+	status.MarkLoadBalancerReady(nil, nil)
+
+	propagateAdmission(status, nil, rejection)
+
+	// The Knative Route propagates the Ready condition of the Ingress to the Service.
+	route := &servingv1.RouteStatus{}
+	route.InitializeConditions()
+	route.MarkTrafficAssigned()
+	route.PropagateIngressStatus(*status)
+	service := &servingv1.ServiceStatus{}
+	service.InitializeConditions()
+	service.PropagateRouteStatus(route)
+
+	ready := service.GetCondition(servingv1.ServiceConditionRoutesReady)
+	if ready == nil || !ready.IsFalse() || ready.Reason != rejection.Reason || ready.Message != rejection.Message {
+		t.Errorf("RoutesReady = %+v, want False with the rejection", ready)
+	}
+
+	// The rejection isn't asserted again once the networking layer marked the load balancer ready.
+	status.MarkLoadBalancerReady(nil, nil)
+	propagateAdmission(status, rejection, rejection)
+	if !status.GetCondition(v1alpha1.IngressConditionReady).IsTrue() {
+		t.Errorf("Ready = %+v, want the load balancer of the networking layer kept", status.GetCondition(v1alpha1.IngressConditionReady))
+	}
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+	netcfg "knative.dev/networking/pkg/config"
+)
+
+// RouteTLSTerminationKey is the downstream key of config-network selecting the termination of
+// the Routes to Knative Ingresses when the internal traffic is encrypted:
+//   - "passthrough" (the default) forwards the TLS connection to the ingress gateway.
+//   - "reencrypt" terminates it at the router, with its certificate, and re-encrypts the
+//     traffic to the ingress gateway, trusting the service CA.
+const RouteTLSTerminationKey = "openshift-route-tls-termination"
+
+// RouteLabelsKey is the downstream key of config-network holding the labels, as "key=value,...",
+// set on all the Routes, e.g. to expose them through a router shard selecting Routes by label.
+// Knative Services override them with the routeLabels annotation.
+const RouteLabelsKey = "openshift-route-labels"
+
+// RouteWildcardKey is the downstream key of config-network enabling the wildcard Routes: "enabled"
+// exposes the ingresses without Route specific options through a single Route per domain suffix,
+// e.g. "*.ns.apps.example.com", leaving the host routing to the ingress gateway. It requires a
+// router allowing wildcard Routes. Defaults to "disabled", a Route per host.
+const RouteWildcardKey = "openshift-route-wildcard"
+
+// ClusterLocalRouteLabelsKey and ClusterLocalRouteDomainKey are the downstream keys of config-network
+// holding the labels, as "key=value,...", selecting the internal router shard exposing the cluster-local
+// services on a private network, and its domain. Both are required to expose cluster-local services
+// with the exposeClusterLocal annotation. No router shard but the one of the domain may select these
+// labels, the cluster-local services aren't exposed otherwise.
+const (
+	ClusterLocalRouteLabelsKey = "openshift-cluster-local-route-labels"
+	ClusterLocalRouteDomainKey = "openshift-cluster-local-route-domain"
+)
+
+// RouteTimeoutKey is the downstream key of config-network holding the timeout, in seconds, of the
+// Routes to Knative Ingresses. It takes precedence over the ROUTE_HAPROXY_TIMEOUT environment of the
+// controller, Knative Services override it with the setRouteTimeout annotation. The Serving operator
+// sets it from the long request timeout of KnativeServing.
+const RouteTimeoutKey = "openshift-route-timeout-seconds"
+
+// Network is the configuration of config-network the Routes depend on.
+type Network struct {
+	// InternalTLS is true if the traffic from the ingress gateway to the backends is encrypted.
+	InternalTLS bool
+	// RouteTLSTermination is the termination of the Routes when the traffic is encrypted.
+	RouteTLSTermination routev1.TLSTerminationType
+	// RouteLabels are the default labels of the Routes selecting the router shard.
+	RouteLabels map[string]string
+	// WildcardRoutes is true if the ingresses are exposed through wildcard Routes, when possible.
+	WildcardRoutes bool
+	// ClusterLocalRouteLabels are the labels of the Routes exposing cluster-local services, selecting
+	// the internal router shard.
+	ClusterLocalRouteLabels map[string]string
+	// ClusterLocalRouteDomain is the domain of the Routes exposing cluster-local services.
+	ClusterLocalRouteDomain string
+	// RouteTimeout is the default timeout of the Routes, e.g. "600s", empty if not configured.
+	RouteTimeout string
+}
+
+func defaultNetwork() *Network {
+	return &Network{
+		RouteTLSTermination: routev1.TLSTerminationPassthrough,
+	}
+}
+
+// NewNetworkFromConfigMap creates a Network from the config-network ConfigMap. The upstream keys
+// are validated by the Knative Serving webhook, an invalid one fails the Network. The downstream
+// keys aren't, an invalid one falls back to its default: the Network is returned along with the
+// errors of the invalid downstream keys.
+func NewNetworkFromConfigMap(cm *corev1.ConfigMap) (*Network, error) {
+	nc, err := netcfg.NewConfigFromConfigMap(cm)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+
+	network := defaultNetwork()
+	network.InternalTLS = nc.SystemInternalTLSEnabled()
+	switch termination := routev1.TLSTerminationType(strings.ToLower(cm.Data[RouteTLSTerminationKey])); termination {
+	case "":
+	case routev1.TLSTerminationPassthrough, routev1.TLSTerminationReencrypt:
+		network.RouteTLSTermination = termination
+	default:
+		errs = append(errs, fmt.Errorf("%s: invalid value %q, expected %q or %q", RouteTLSTerminationKey, termination,
+			routev1.TLSTerminationPassthrough, routev1.TLSTerminationReencrypt))
+	}
+
+	switch value := strings.ToLower(cm.Data[RouteWildcardKey]); value {
+	case "", "disabled":
+	case "enabled":
+		network.WildcardRoutes = true
+	default:
+		errs = append(errs, fmt.Errorf("%s: invalid value %q, expected \"enabled\" or \"disabled\"", RouteWildcardKey, value))
+	}
+
+	if value := cm.Data[RouteLabelsKey]; value != "" {
+		if routeLabels, err := labels.ConvertSelectorToLabelsMap(value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: invalid value %q: %w", RouteLabelsKey, value, err))
+		} else {
+			network.RouteLabels = routeLabels
+		}
+	}
+
+	if value := cm.Data[ClusterLocalRouteLabelsKey]; value != "" {
+		if routeLabels, err := labels.ConvertSelectorToLabelsMap(value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: invalid value %q: %w", ClusterLocalRouteLabelsKey, value, err))
+		} else {
+			network.ClusterLocalRouteLabels = routeLabels
+		}
+	}
+	if value := strings.Trim(cm.Data[ClusterLocalRouteDomainKey], ". "); value != "" {
+		if invalid := validation.IsDNS1123Subdomain(value); len(invalid) > 0 {
+			errs = append(errs, fmt.Errorf("%s: invalid value %q: %s", ClusterLocalRouteDomainKey, value, strings.Join(invalid, ", ")))
+		} else {
+			network.ClusterLocalRouteDomain = value
+		}
+	}
+
+	if value := cm.Data[RouteTimeoutKey]; value != "" {
+		if seconds, err := strconv.ParseInt(value, 10, 64); err != nil || seconds <= 0 {
+			errs = append(errs, fmt.Errorf("%s: invalid value %q, expected a positive number of seconds", RouteTimeoutKey, value))
+		} else {
+			network.RouteTimeout = fmt.Sprintf("%ds", seconds)
+		}
+	}
+	return network, errors.Join(errs...)
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	netcfg "knative.dev/networking/pkg/config"
+)
+
+func TestNewNetworkFromConfigMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    map[string]string
+		want    *Network
+		wantErr bool
+	}{{
+		name: "defaults",
+		want: &Network{RouteTLSTermination: routev1.TLSTerminationPassthrough},
+	}, {
+		name: "system-internal-tls",
+		data: map[string]string{netcfg.SystemInternalTLSKey: "enabled"},
+		want: &Network{InternalTLS: true, RouteTLSTermination: routev1.TLSTerminationPassthrough},
+	}, {
+		name: "reencrypt",
+		data: map[string]string{
+			netcfg.SystemInternalTLSKey: "enabled",
+			RouteTLSTerminationKey:      "Reencrypt",
+		},
+		want: &Network{InternalTLS: true, RouteTLSTermination: routev1.TLSTerminationReencrypt},
+	}, {
+		name: "route labels",
+		data: map[string]string{RouteLabelsKey: "router=internal, tenant=a"},
+		want: &Network{
+			RouteTLSTermination: routev1.TLSTerminationPassthrough,
+			RouteLabels:         map[string]string{"router": "internal", "tenant": "a"},
+		},
+	}, {
+		name:    "invalid route labels",
+		data:    map[string]string{RouteLabelsKey: "router"},
+		wantErr: true,
+	}, {
+		name: "wildcard routes",
+		data: map[string]string{RouteWildcardKey: "Enabled"},
+		want: &Network{RouteTLSTermination: routev1.TLSTerminationPassthrough, WildcardRoutes: true},
+	}, {
+		name:    "invalid wildcard routes",
+		data:    map[string]string{RouteWildcardKey: "true"},
+		wantErr: true,
+	}, {
+		name: "cluster-local routes",
+		data: map[string]string{
+			ClusterLocalRouteLabelsKey: "router=private",
+			ClusterLocalRouteDomainKey: "private.example.com.",
+		},
+		want: &Network{
+			RouteTLSTermination:     routev1.TLSTerminationPassthrough,
+			ClusterLocalRouteLabels: map[string]string{"router": "private"},
+			ClusterLocalRouteDomain: "private.example.com",
+		},
+	}, {
+		name:    "invalid cluster-local route labels",
+		data:    map[string]string{ClusterLocalRouteLabelsKey: "router"},
+		wantErr: true,
+	}, {
+		name:    "invalid cluster-local route domain",
+		data:    map[string]string{ClusterLocalRouteDomainKey: "private_example.com"},
+		wantErr: true,
+	}, {
+		name: "route timeout",
+		data: map[string]string{RouteTimeoutKey: "3600"},
+		want: &Network{RouteTLSTermination: routev1.TLSTerminationPassthrough, RouteTimeout: "3600s"},
+	}, {
+		name:    "invalid route timeout",
+		data:    map[string]string{RouteTimeoutKey: "1h"},
+		wantErr: true,
+	}, {
+		name:    "negative route timeout",
+		data:    map[string]string{RouteTimeoutKey: "-1"},
+		wantErr: true,
+	}, {
+		name:    "invalid termination",
+		data:    map[string]string{RouteTLSTerminationKey: "edge"},
+		wantErr: true,
+	}, {
+		name:    "only invalid keys fall back to their defaults",
+		data:    map[string]string{RouteTimeoutKey: "1h", RouteWildcardKey: "enabled"},
+		want:    &Network{RouteTLSTermination: routev1.TLSTerminationPassthrough, WildcardRoutes: true},
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := NewNetworkFromConfigMap(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: netcfg.ConfigMapName},
+				Data:       test.data,
+			})
+			if (err != nil) != test.wantErr {
+				t.Fatalf("NewNetworkFromConfigMap() = %v, wantErr %v", err, test.wantErr)
+			}
+			// The invalid downstream keys fall back to their defaults.
+			want := test.want
+			if want == nil {
+				want = defaultNetwork()
+			}
+			if !cmp.Equal(got, want) {
+				t.Errorf("NewNetworkFromConfigMap() = %s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
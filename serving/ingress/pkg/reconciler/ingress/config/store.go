@@ -0,0 +1,115 @@
+package config
+
+import (
+	"context"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	netcfg "knative.dev/networking/pkg/config"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/configmap/informer"
+)
+
+const (
+	servingNamespaceEnv     = "REQUIRED_SERVING_NAMESPACE"
+	defaultServingNamespace = "knative-serving"
+)
+
+type cfgKey struct{}
+
+// Config is the configuration of Knative Serving the ingress controller depends on.
+type Config struct {
+	Network *Network
+}
+
+// FromContext extracts a Config from the provided context.
+func FromContext(ctx context.Context) *Config {
+	x, ok := ctx.Value(cfgKey{}).(*Config)
+	if ok {
+		return x
+	}
+	return nil
+}
+
+// FromContextOrDefaults is like FromContext, but when no Config is attached it returns a Config
+// populated with the defaults.
+func FromContextOrDefaults(ctx context.Context) *Config {
+	if cfg := FromContext(ctx); cfg != nil {
+		return cfg
+	}
+	return &Config{
+		Network: defaultNetwork(),
+	}
+}
+
+// ToContext attaches the provided Config to the provided context, returning the new context
+// with the Config attached.
+func ToContext(ctx context.Context, c *Config) context.Context {
+	return context.WithValue(ctx, cfgKey{}, c)
+}
+
+// Store is a typed wrapper around configmap.UntypedStore to handle our configmaps.
+type Store struct {
+	*configmap.UntypedStore
+}
+
+// NewStore creates a new store of Configs and optionally calls functions when ConfigMaps are updated.
+func NewStore(logger configmap.Logger, onAfterStore ...func(name string, value interface{})) *Store {
+	return &Store{
+		UntypedStore: configmap.NewUntypedStore(
+			"ingress",
+			logger,
+			configmap.Constructors{
+				netcfg.ConfigMapName: func(cm *corev1.ConfigMap) (*Network, error) {
+					// A typo in the downstream keys must not crash the controller, which fails on
+					// an invalid configuration at startup.
+					network, err := NewNetworkFromConfigMap(cm)
+					if network != nil && err != nil {
+						logger.Errorf("Falling back to the defaults of invalid keys of %s: %v", netcfg.ConfigMapName, err)
+						return network, nil
+					}
+					return network, err
+				},
+			},
+			onAfterStore...,
+		),
+	}
+}
+
+// ToContext attaches the current Config state to the provided context.
+func (s *Store) ToContext(ctx context.Context) context.Context {
+	return ToContext(ctx, s.Load())
+}
+
+// Load creates a Config from the current config state of the Store.
+func (s *Store) Load() *Config {
+	network, ok := s.UntypedLoad(netcfg.ConfigMapName).(*Network)
+	if !ok {
+		network = defaultNetwork()
+	}
+	n := *network
+	return &Config{
+		Network: &n,
+	}
+}
+
+// WatchServingConfigs starts watching the ConfigMaps of the Knative Serving namespace, which
+// differs from the namespace of the ingress controller. It falls back to the defaults as long
+// as Knative Serving isn't installed.
+func (s *Store) WatchServingConfigs(ctx context.Context) error {
+	ns := os.Getenv(servingNamespaceEnv)
+	if ns == "" {
+		ns = defaultServingNamespace
+	}
+
+	watcher := informer.NewInformedWatcher(kubeclient.Get(ctx), ns)
+	watcher.WatchWithDefault(corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      netcfg.ConfigMapName,
+			Namespace: ns,
+		},
+	}, s.OnConfigChanged)
+	return watcher.Start(ctx.Done())
+}
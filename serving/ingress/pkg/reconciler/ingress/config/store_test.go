@@ -0,0 +1,47 @@
+package config
+
+import (
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	netcfg "knative.dev/networking/pkg/config"
+)
+
+func TestStoreInvalidDownstreamKey(t *testing.T) {
+	logger := &recordingLogger{}
+	store := NewStore(logger)
+	store.OnConfigChanged(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: netcfg.ConfigMapName},
+		Data:       map[string]string{RouteTimeoutKey: "1h", RouteWildcardKey: "enabled"},
+	})
+
+	if len(logger.fatal) > 0 {
+		t.Fatalf("Store failed on an invalid downstream key: %v", logger.fatal)
+	}
+	if len(logger.errors) != 1 {
+		t.Errorf("Got errors %v, want the invalid key logged", logger.errors)
+	}
+	if network := store.Load().Network; !network.WildcardRoutes || network.RouteTimeout != "" {
+		t.Errorf("Got %+v, want the valid keys applied and the invalid one defaulted", network)
+	}
+}
+
+// recordingLogger records the errors and fatal errors of a Store instead of exiting.
+type recordingLogger struct {
+	errors []string
+	fatal  []string
+}
+
+func (l *recordingLogger) Debugf(string, ...interface{}) {}
+
+func (l *recordingLogger) Infof(string, ...interface{}) {}
+
+func (l *recordingLogger) Errorf(format string, args ...interface{}) {
+	l.errors = append(l.errors, fmt.Sprintf(format, args...))
+}
+
+func (l *recordingLogger) Fatalf(format string, args ...interface{}) {
+	l.fatal = append(l.fatal, fmt.Sprintf(format, args...))
+}
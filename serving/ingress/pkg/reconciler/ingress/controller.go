@@ -2,18 +2,33 @@ package ingress
 
 import (
 	"context"
+	"strings"
 
+	routev1 "github.com/openshift/api/route/v1"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/tools/cache"
 	"knative.dev/networking/pkg/apis/networking"
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+	networkingclient "knative.dev/networking/pkg/client/injection/client"
 	ingressinformer "knative.dev/networking/pkg/client/injection/informers/networking/v1alpha1/ingress"
 	ingressreconciler "knative.dev/networking/pkg/client/injection/reconciler/networking/v1alpha1/ingress"
+	networkinglisters "knative.dev/networking/pkg/client/listers/networking/v1alpha1"
+	knativeservinginformer "knative.dev/operator/pkg/client/injection/informers/operator/v1beta1/knativeserving"
+	namespaceinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/namespace"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection/clients/dynamicclient"
 	"knative.dev/pkg/logging"
 	"knative.dev/pkg/reconciler"
+	"knative.dev/pkg/tracker"
 
 	routeclient "github.com/openshift-knative/serverless-operator/pkg/client/route/injection/client"
 	routeinformer "github.com/openshift-knative/serverless-operator/pkg/client/route/injection/informers/route/v1/route"
+	ingresscontrollerinformer "github.com/openshift-knative/serverless-operator/serving/ingress/pkg/informers/ingresscontroller"
+	secretinformer "github.com/openshift-knative/serverless-operator/serving/ingress/pkg/informers/secret"
+	ingressconfig "github.com/openshift-knative/serverless-operator/serving/ingress/pkg/reconciler/ingress/config"
 	"github.com/openshift-knative/serverless-operator/serving/ingress/pkg/reconciler/ingress/resources"
 )
 
@@ -31,14 +46,30 @@ func NewIstioController(
 
 	ingressInformer := ingressinformer.Get(ctx)
 	routeInformer := routeinformer.Get(ctx)
+	secretInformer := secretinformer.Get(ctx)
+	ingressControllerInformer := ingresscontrollerinformer.Get(ctx)
+	namespaceInformer := namespaceinformer.Get(ctx)
+
+	m, err := newMetrics(nil)
+	if err != nil {
+		logger.Fatalw("Failed to register the ingress metrics", zap.Error(err))
+	}
 
 	c := &Reconciler{
-		routeLister: routeInformer.Lister(),
-		routeClient: routeclient.Get(ctx).RouteV1(),
+		routeLister:   routeInformer.Lister(),
+		routeClient:   routeclient.Get(ctx).RouteV1(),
+		ingressClient: networkingclient.Get(ctx).NetworkingV1alpha1(),
+		secretLister:  secretInformer.Lister(),
+		dynamicClient: dynamicclient.Get(ctx),
+		metrics:       m,
+
+		ingressControllerLister: ingressControllerInformer.Lister(),
+		namespaceLister:         namespaceInformer.Lister(),
 	}
 
-	impl := ingressreconciler.NewImpl(ctx, c, istioIngressClassName, func(_ *controller.Impl) controller.Options {
+	impl := ingressreconciler.NewImpl(ctx, c, istioIngressClassName, func(impl *controller.Impl) controller.Options {
 		return controller.Options{
+			ConfigStore:       newConfigStore(ctx, impl, ingressInformer.Informer()),
 			SkipStatusUpdates: true,
 			FinalizerName:     "ocp-ingress",
 		}
@@ -59,9 +90,100 @@ func NewIstioController(
 		)),
 	})
 
+	// The wildcard Routes are shared, their admission affects the ingresses in their subdomain.
+	routeInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: reconciler.LabelExistsFilterFunc(resources.WildcardRouteLabelKey),
+		Handler:    controller.HandleAll(enqueueWildcardIngresses(impl, ingressInformer.Lister(), istioIngressClassName)),
+	})
+
+	// The router shards are shared, their changes affect all the ingresses.
+	ingressControllerInformer.Informer().AddEventHandler(controller.HandleAll(func(interface{}) {
+		impl.GlobalResync(ingressInformer.Informer())
+	}))
+	// The namespace selectors of the router shards match the labels of the namespaces of the Routes.
+	namespaceInformer.Informer().AddEventHandler(resyncOnNamespaceLabels(impl, ingressInformer.Informer()))
+
+	impl.Tracker = tracker.New(impl.EnqueueKey, controller.GetTrackerLease(ctx))
+	c.tracker = impl.Tracker
+	// Update the certificate of the Routes when the Secret is rotated.
+	secretInformer.Informer().AddEventHandler(controller.HandleAll(
+		controller.EnsureTypeMeta(impl.Tracker.OnChanged, corev1.SchemeGroupVersion.WithKind("Secret")),
+	))
+
 	return impl
 }
 
+// resyncOnNamespaceLabels returns a handler of the namespaces resyncing all the ingresses when
+// the labels of a namespace change.
+func resyncOnNamespaceLabels(impl *controller.Impl, ingressInformer cache.SharedInformer) cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldNs, ok := oldObj.(*corev1.Namespace)
+			if !ok {
+				return
+			}
+			newNs, ok := newObj.(*corev1.Namespace)
+			if !ok || labels.Equals(oldNs.Labels, newNs.Labels) {
+				return
+			}
+			impl.GlobalResync(ingressInformer)
+		},
+	}
+}
+
+// enqueueWildcardIngresses returns a handler of the wildcard Routes enqueuing the ingresses of the
+// given class with a host in their subdomain.
+func enqueueWildcardIngresses(impl *controller.Impl, ingressLister networkinglisters.IngressLister, class string) func(interface{}) {
+	isClass := reconciler.AnnotationFilterFunc(networking.IngressClassAnnotationKey, class, false)
+	return func(obj interface{}) {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		route, ok := obj.(*routev1.Route)
+		if !ok {
+			return
+		}
+		ingresses, err := ingressLister.List(labels.Everything())
+		if err != nil {
+			return
+		}
+		subdomain := "." + resources.WildcardSubdomain(route)
+		for _, ing := range ingresses {
+			if isClass(ing) && hasHostIn(ing, subdomain) {
+				impl.Enqueue(ing)
+			}
+		}
+	}
+}
+
+// hasHostIn returns whether the ingress has an external host ending with the given suffix.
+func hasHostIn(ing *v1alpha1.Ingress, suffix string) bool {
+	for _, rule := range ing.Spec.Rules {
+		if rule.Visibility == v1alpha1.IngressVisibilityClusterLocal {
+			continue
+		}
+		for _, host := range rule.Hosts {
+			if strings.HasSuffix(host, suffix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// newConfigStore returns a store of the Knative Serving configuration, resyncing all the ingresses
+// when it changes.
+func newConfigStore(ctx context.Context, impl *controller.Impl, ingressInformer cache.SharedIndexInformer) *ingressconfig.Store {
+	logger := logging.FromContext(ctx)
+	configStore := ingressconfig.NewStore(logger.Named("config-store"), func(string, interface{}) {
+		impl.GlobalResync(ingressInformer)
+	})
+	if err := configStore.WatchServingConfigs(ctx); err != nil {
+		logger.Fatalw("Failed to watch the Knative Serving configuration", zap.Error(err))
+	}
+	return configStore
+}
+
 // NewKourierController returns a new Ingress controller for Ingress on Openshift.
 func NewKourierController(
 	ctx context.Context,
@@ -71,14 +193,32 @@ func NewKourierController(
 
 	ingressInformer := ingressinformer.Get(ctx)
 	routeInformer := routeinformer.Get(ctx)
+	secretInformer := secretinformer.Get(ctx)
+	ingressControllerInformer := ingresscontrollerinformer.Get(ctx)
+	namespaceInformer := namespaceinformer.Get(ctx)
+	knativeServingInformer := knativeservinginformer.Get(ctx)
+
+	m, err := newMetrics(nil)
+	if err != nil {
+		logger.Fatalw("Failed to register the ingress metrics", zap.Error(err))
+	}
 
 	c := &Reconciler{
-		routeLister: routeInformer.Lister(),
-		routeClient: routeclient.Get(ctx).RouteV1(),
+		routeLister:          routeInformer.Lister(),
+		routeClient:          routeclient.Get(ctx).RouteV1(),
+		ingressClient:        networkingclient.Get(ctx).NetworkingV1alpha1(),
+		secretLister:         secretInformer.Lister(),
+		dynamicClient:        dynamicclient.Get(ctx),
+		knativeServingLister: knativeServingInformer.Lister(),
+		metrics:              m,
+
+		ingressControllerLister: ingressControllerInformer.Lister(),
+		namespaceLister:         namespaceInformer.Lister(),
 	}
 
-	impl := ingressreconciler.NewImpl(ctx, c, kourierIngressClassName, func(_ *controller.Impl) controller.Options {
+	impl := ingressreconciler.NewImpl(ctx, c, kourierIngressClassName, func(impl *controller.Impl) controller.Options {
 		return controller.Options{
+			ConfigStore:       newConfigStore(ctx, impl, ingressInformer.Informer()),
 			SkipStatusUpdates: true,
 			FinalizerName:     "ocp-ingress",
 		}
@@ -99,5 +239,36 @@ func NewKourierController(
 		)),
 	})
 
+	// The wildcard Routes are shared, their admission affects the ingresses in their subdomain.
+	routeInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: reconciler.LabelExistsFilterFunc(resources.WildcardRouteLabelKey),
+		Handler:    controller.HandleAll(enqueueWildcardIngresses(impl, ingressInformer.Lister(), kourierIngressClassName)),
+	})
+
+	// The router shards are shared, their changes affect all the ingresses.
+	ingressControllerInformer.Informer().AddEventHandler(controller.HandleAll(func(interface{}) {
+		impl.GlobalResync(ingressInformer.Informer())
+	}))
+	// The namespace selectors of the router shards match the labels of the namespaces of the Routes.
+	namespaceInformer.Informer().AddEventHandler(resyncOnNamespaceLabels(impl, ingressInformer.Informer()))
+
+	impl.Tracker = tracker.New(impl.EnqueueKey, controller.GetTrackerLease(ctx))
+	c.tracker = impl.Tracker
+	// Update the certificate of the Routes when the Secret is rotated.
+	secretInformer.Informer().AddEventHandler(controller.HandleAll(
+		controller.EnsureTypeMeta(impl.Tracker.OnChanged, corev1.SchemeGroupVersion.WithKind("Secret")),
+	))
+
+	// The HTTPRoutes are only watched once a Gateway is configured.
+	c.httpRoutes = newHTTPRouteInformer(ctx, c.dynamicClient, controller.HandleAll(impl.EnqueueLabelOfNamespaceScopedResource(
+		resources.OpenShiftIngressNamespaceLabelKey,
+		resources.OpenShiftIngressLabelKey,
+	)))
+
+	// Switching between Routes and HTTPRoutes affects all the ingresses.
+	knativeServingInformer.Informer().AddEventHandler(controller.HandleAll(func(interface{}) {
+		impl.GlobalResync(ingressInformer.Informer())
+	}))
+
 	return impl
 }
@@ -0,0 +1,39 @@
+package ingress
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	operatorv1beta1listers "knative.dev/operator/pkg/client/listers/operator/v1beta1"
+)
+
+// GatewayAnnotationKey is set on the KnativeServing to expose the Kourier ingresses through
+// Gateway API HTTPRoutes attached to the given Gateway, as "<namespace>/<name>", instead of
+// OpenShift Routes.
+const GatewayAnnotationKey = "serverless.openshift.io/ingress-gateway"
+
+// gatewayOf returns the Gateway the HTTPRoutes attach to, nil if the ingresses are exposed
+// through OpenShift Routes.
+func gatewayOf(lister operatorv1beta1listers.KnativeServingLister) (*types.NamespacedName, error) {
+	if lister == nil {
+		return nil, nil
+	}
+	kss, err := lister.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list KnativeServings: %w", err)
+	}
+	for _, ks := range kss {
+		ref, ok := ks.GetAnnotations()[GatewayAnnotationKey]
+		if !ok {
+			continue
+		}
+		namespace, name, err := cache.SplitMetaNamespaceKey(ref)
+		if err != nil || namespace == "" || name == "" {
+			return nil, fmt.Errorf("invalid %s annotation %q, expected <namespace>/<name>", GatewayAnnotationKey, ref)
+		}
+		return &types.NamespacedName{Namespace: namespace, Name: name}, nil
+	}
+	return nil, nil
+}
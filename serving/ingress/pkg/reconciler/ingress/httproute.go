@@ -0,0 +1,233 @@
+package ingress
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/openshift-knative/serverless-operator/serving/ingress/pkg/reconciler/ingress/resources"
+)
+
+// httpRouteSyncTimeout bounds the wait for the HTTPRoute informer to sync, e.g. when the
+// Gateway API isn't installed.
+const httpRouteSyncTimeout = 30 * time.Second
+
+var httpRouteResource = gatewayv1.SchemeGroupVersion.WithResource("httproutes")
+
+// httpRouteInformer caches the HTTPRoutes of the ingresses. It's only started once a Gateway
+// is configured, as the Gateway API might not be installed otherwise.
+type httpRouteInformer struct {
+	informer cache.SharedIndexInformer
+	stopCh   <-chan struct{}
+
+	mu      sync.Mutex
+	started bool
+}
+
+// newHTTPRouteInformer returns an informer of the HTTPRoutes labeled with their ingress, calling
+// the given handler on their changes once started.
+func newHTTPRouteInformer(ctx context.Context, client dynamic.Interface, handler cache.ResourceEventHandler) *httpRouteInformer {
+	informer := cache.NewSharedIndexInformer(&cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			opts.LabelSelector = resources.OpenShiftIngressLabelKey
+			return client.Resource(httpRouteResource).List(ctx, opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			opts.LabelSelector = resources.OpenShiftIngressLabelKey
+			return client.Resource(httpRouteResource).Watch(ctx, opts)
+		},
+	}, &unstructured.Unstructured{}, controller.GetResyncPeriod(ctx), cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	informer.AddEventHandler(handler)
+	return &httpRouteInformer{informer: informer, stopCh: ctx.Done()}
+}
+
+// lister returns a lister of the HTTPRoutes, starting the informer if start is set. It returns
+// nil if the informer isn't started.
+func (i *httpRouteInformer) lister(ctx context.Context, start bool) (cache.GenericLister, error) {
+	if i == nil {
+		return nil, nil
+	}
+	i.mu.Lock()
+	if !i.started && start {
+		go i.informer.Run(i.stopCh)
+		i.started = true
+	}
+	started := i.started
+	i.mu.Unlock()
+	if !started {
+		return nil, nil
+	}
+
+	if !i.informer.HasSynced() {
+		ctx, cancel := context.WithTimeout(ctx, httpRouteSyncTimeout)
+		defer cancel()
+		if !cache.WaitForCacheSync(ctx.Done(), i.informer.HasSynced) {
+			return nil, fmt.Errorf("failed to sync the HTTPRoutes, is the Gateway API installed?")
+		}
+	}
+	return cache.NewGenericLister(i.informer.GetIndexer(), httpRouteResource.GroupResource()), nil
+}
+
+// reconcileHTTPRoutes exposes the ingress through HTTPRoutes attached to the given Gateway.
+func (r *Reconciler) reconcileHTTPRoutes(ctx context.Context, ing *v1alpha1.Ingress, gateway types.NamespacedName) error {
+	logger := logging.FromContext(ctx)
+
+	lister, err := r.httpRoutes.lister(ctx, true)
+	if err != nil || lister == nil {
+		return err
+	}
+	existingMap, err := httpRouteList(lister, ing)
+	if err != nil {
+		return fmt.Errorf("failed to list HTTPRoutes: %w", err)
+	}
+
+	routes, err := resources.MakeHTTPRoutes(ctx, ing, gateway)
+	if err != nil {
+		logger.Warnf("Failed to generate HTTPRoutes from ingress %v", err)
+		// Returning nil aborts the reconciliation. It will be retriggered once the status of the ingress changes.
+		return nil
+	}
+	for _, route := range routes {
+		if err := r.reconcileHTTPRoute(ctx, lister, route); err != nil {
+			return err
+		}
+		delete(existingMap, route.Name)
+	}
+	// If HTTPRoutes remain in existingMap, they must be obsolete. Clean them up.
+	for _, route := range existingMap {
+		if err := r.deleteHTTPRoute(ctx, route); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteHTTPRoutes deletes the HTTPRoutes of the ingress. They are only looked up if a Gateway
+// is configured, or was since the controller started.
+func (r *Reconciler) deleteHTTPRoutes(ctx context.Context, ing *v1alpha1.Ingress, gatewayConfigured bool) error {
+	lister, err := r.httpRoutes.lister(ctx, gatewayConfigured)
+	if err != nil || lister == nil {
+		return err
+	}
+	routes, err := httpRouteList(lister, ing)
+	if err != nil {
+		return fmt.Errorf("failed to list HTTPRoutes for deletion: %w", err)
+	}
+	for _, route := range routes {
+		if err := r.deleteHTTPRoute(ctx, route); err != nil {
+			return fmt.Errorf("failed to delete HTTPRoutes: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *Reconciler) deleteHTTPRoute(ctx context.Context, route *gatewayv1.HTTPRoute) error {
+	logger := logging.FromContext(ctx)
+	logger.Infof("Deleting HTTPRoute %s(%v)", route.Name, route.Spec.Hostnames)
+	if err := r.dynamicClient.Resource(httpRouteResource).Namespace(route.Namespace).Delete(ctx, route.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete HTTPRoute: %w", err)
+	}
+	return nil
+}
+
+func (r *Reconciler) reconcileHTTPRoute(ctx context.Context, lister cache.GenericLister, desired *gatewayv1.HTTPRoute) error {
+	logger := logging.FromContext(ctx)
+	client := r.dynamicClient.Resource(httpRouteResource).Namespace(desired.Namespace)
+
+	// Check if this HTTPRoute already exists
+	obj, err := lister.ByNamespace(desired.Namespace).Get(desired.Name)
+	if apierrors.IsNotFound(err) {
+		logger.Infof("Creating HTTPRoute %s(%v)", desired.Name, desired.Spec.Hostnames)
+		u, err := toUnstructured(desired)
+		if err != nil {
+			return err
+		}
+		if _, err := client.Create(ctx, u, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create HTTPRoute: %w", err)
+		}
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get HTTPRoute: %w", err)
+	}
+
+	// The conversion copies the informer's object.
+	route, err := fromUnstructured(obj.(*unstructured.Unstructured))
+	if err != nil {
+		return err
+	}
+	if !equality.Semantic.DeepEqual(route.Spec, desired.Spec) ||
+		!equality.Semantic.DeepEqual(route.Annotations, desired.Annotations) ||
+		!equality.Semantic.DeepEqual(route.Labels, desired.Labels) {
+		route.Spec = desired.Spec
+		route.Annotations = desired.Annotations
+		route.Labels = desired.Labels
+
+		u, err := toUnstructured(route)
+		if err != nil {
+			return err
+		}
+		if _, err := client.Update(ctx, u, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to update HTTPRoute: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func httpRouteList(lister cache.GenericLister, ing *v1alpha1.Ingress) (map[string]*gatewayv1.HTTPRoute, error) {
+	routes := make(map[string]*gatewayv1.HTTPRoute)
+
+	// List HTTPRoutes by the downstream label.
+	objs, err := lister.List(labels.SelectorFromSet(map[string]string{
+		resources.OpenShiftIngressLabelKey:          ing.GetName(),
+		resources.OpenShiftIngressNamespaceLabelKey: ing.GetNamespace(),
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, obj := range objs {
+		route, err := fromUnstructured(obj.(*unstructured.Unstructured))
+		if err != nil {
+			return nil, err
+		}
+		routes[route.Name] = route
+	}
+	return routes, nil
+}
+
+func toUnstructured(route *gatewayv1.HTTPRoute) (*unstructured.Unstructured, error) {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(route)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert HTTPRoute: %w", err)
+	}
+	u := &unstructured.Unstructured{Object: obj}
+	// The status is owned by the Gateway controller.
+	unstructured.RemoveNestedField(u.Object, "status")
+	u.SetGroupVersionKind(gatewayv1.SchemeGroupVersion.WithKind("HTTPRoute"))
+	return u, nil
+}
+
+func fromUnstructured(u *unstructured.Unstructured) (*gatewayv1.HTTPRoute, error) {
+	route := &gatewayv1.HTTPRoute{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, route); err != nil {
+		return nil, fmt.Errorf("failed to convert HTTPRoute %s: %w", u.GetName(), err)
+	}
+	return route, nil
+}
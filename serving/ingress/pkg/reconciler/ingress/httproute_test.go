@@ -0,0 +1,209 @@
+package ingress
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clientgotesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+	logtesting "knative.dev/pkg/logging/testing"
+
+	"github.com/openshift-knative/serverless-operator/serving/ingress/pkg/reconciler/ingress/resources"
+)
+
+func TestHTTPRouteReconcile(t *testing.T) {
+	gateway := types.NamespacedName{Namespace: "openshift-ingress", Name: "knative"}
+
+	tests := []struct {
+		name       string
+		ingress    *v1alpha1.Ingress
+		existing   []runtime.Object
+		wantVerbs  []string
+		wantRoutes sets.Set[string]
+	}{{
+		name:       "create HTTPRoute",
+		ingress:    ing(ingNamespace, ingName),
+		wantVerbs:  []string{"create"},
+		wantRoutes: sets.New(routeName),
+	}, {
+		name: "create redirecting HTTPRoutes",
+		ingress: ing(ingNamespace, ingName, func(i *v1alpha1.Ingress) {
+			i.Spec.HTTPOption = v1alpha1.HTTPOptionRedirected
+		}),
+		wantVerbs:  []string{"create", "create"},
+		wantRoutes: sets.New(routeName, routeName+"-redirect"),
+	}, {
+		name:       "remove outdated HTTPRoutes",
+		ingress:    ing(ingNamespace, ingName),
+		existing:   []runtime.Object{staleHTTPRoute()},
+		wantVerbs:  []string{"create", "delete"},
+		wantRoutes: sets.New(routeName),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(logtesting.TestContextWithLogger(t))
+			defer cancel()
+			r, client := newHTTPRouteReconciler(ctx, test.existing...)
+
+			if err := r.reconcileHTTPRoutes(ctx, test.ingress, gateway); err != nil {
+				t.Fatalf("reconcileHTTPRoutes() = %v", err)
+			}
+			if got := writeVerbs(client.Actions()); !cmp.Equal(got, test.wantVerbs) {
+				t.Errorf("actions = %v, want %v", got, test.wantVerbs)
+			}
+			if got := httpRouteNames(ctx, t, client); !got.Equal(test.wantRoutes) {
+				t.Errorf("HTTPRoutes = %v, want %v", sets.List(got), sets.List(test.wantRoutes))
+			}
+
+			// A second reconcile, once the informer caught up, doesn't change anything.
+			waitForHTTPRoutes(ctx, t, r, test.wantRoutes)
+			client.ClearActions()
+			if err := r.reconcileHTTPRoutes(ctx, test.ingress, gateway); err != nil {
+				t.Fatalf("reconcileHTTPRoutes() = %v", err)
+			}
+			if got := writeVerbs(client.Actions()); len(got) > 0 {
+				t.Errorf("actions = %v on a steady state, want none", got)
+			}
+		})
+	}
+}
+
+func TestDeleteHTTPRoutes(t *testing.T) {
+	tests := []struct {
+		name              string
+		gatewayConfigured bool
+		// started is set if a Gateway was configured since the controller started.
+		started    bool
+		wantVerbs  []string
+		wantRoutes sets.Set[string]
+	}{{
+		name:       "no Gateway configured",
+		wantVerbs:  []string{},
+		wantRoutes: sets.New("stale"),
+	}, {
+		name:       "Gateway configured since the controller started",
+		started:    true,
+		wantVerbs:  []string{"delete", "list", "watch"},
+		wantRoutes: sets.New[string](),
+	}, {
+		name:              "Gateway configured",
+		gatewayConfigured: true,
+		wantVerbs:         []string{"delete", "list", "watch"},
+		wantRoutes:        sets.New[string](),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(logtesting.TestContextWithLogger(t))
+			defer cancel()
+			r, client := newHTTPRouteReconciler(ctx, staleHTTPRoute())
+			if test.started {
+				if _, err := r.httpRoutes.lister(ctx, true); err != nil {
+					t.Fatalf("lister() = %v", err)
+				}
+			}
+
+			if err := r.deleteHTTPRoutes(ctx, ing(ingNamespace, ingName), test.gatewayConfigured); err != nil {
+				t.Fatalf("deleteHTTPRoutes() = %v", err)
+			}
+			got := verbs(client.Actions())
+			sort.Strings(got)
+			if !cmp.Equal(got, test.wantVerbs) {
+				t.Errorf("actions = %v, want %v", got, test.wantVerbs)
+			}
+			if got := httpRouteNames(ctx, t, client); !got.Equal(test.wantRoutes) {
+				t.Errorf("HTTPRoutes = %v, want %v", sets.List(got), sets.List(test.wantRoutes))
+			}
+		})
+	}
+}
+
+func newHTTPRouteReconciler(ctx context.Context, objs ...runtime.Object) (*Reconciler, *dynamicfake.FakeDynamicClient) {
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{httpRouteResource: "HTTPRouteList"}, objs...)
+	return &Reconciler{
+		dynamicClient: client,
+		httpRoutes:    newHTTPRouteInformer(ctx, client, cache.ResourceEventHandlerFuncs{}),
+	}, client
+}
+
+func staleHTTPRoute() *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(httpRouteResource.GroupVersion().WithKind("HTTPRoute"))
+	u.SetNamespace(ingressNamespace)
+	u.SetName("stale")
+	u.SetLabels(map[string]string{
+		resources.OpenShiftIngressLabelKey:          ingName,
+		resources.OpenShiftIngressNamespaceLabelKey: ingNamespace,
+	})
+	return u
+}
+
+// waitForHTTPRoutes waits for the informer of the reconciler to cache the given HTTPRoutes.
+func waitForHTTPRoutes(ctx context.Context, t *testing.T, r *Reconciler, want sets.Set[string]) {
+	t.Helper()
+	lister, err := r.httpRoutes.lister(ctx, true)
+	if err != nil {
+		t.Fatalf("lister() = %v", err)
+	}
+	err = wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, 5*time.Second, true, func(context.Context) (bool, error) {
+		objs, err := lister.List(labels.Everything())
+		if err != nil {
+			return false, err
+		}
+		got := sets.New[string]()
+		for _, obj := range objs {
+			got.Insert(obj.(*unstructured.Unstructured).GetName())
+		}
+		return got.Equal(want), nil
+	})
+	if err != nil {
+		t.Fatalf("HTTPRoutes %v not cached: %v", sets.List(want), err)
+	}
+}
+
+func httpRouteNames(ctx context.Context, t *testing.T, client *dynamicfake.FakeDynamicClient) sets.Set[string] {
+	t.Helper()
+	list, err := client.Resource(httpRouteResource).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("List() = %v", err)
+	}
+	names := sets.New[string]()
+	for _, u := range list.Items {
+		names.Insert(u.GetName())
+	}
+	return names
+}
+
+// writeVerbs returns the verbs of the given actions, leaving out the ones of the informer.
+func writeVerbs(actions []clientgotesting.Action) []string {
+	verbs := make([]string, 0, len(actions))
+	for _, action := range actions {
+		if verb := action.GetVerb(); verb != "list" && verb != "watch" {
+			verbs = append(verbs, verb)
+		}
+	}
+	return verbs
+}
+
+func verbs(actions []clientgotesting.Action) []string {
+	verbs := make([]string, 0, len(actions))
+	for _, action := range actions {
+		verbs = append(verbs, action.GetVerb())
+	}
+	return verbs
+}
@@ -2,17 +2,30 @@ package ingress
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
+	"sync"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
 	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+	networkingv1alpha1client "knative.dev/networking/pkg/client/clientset/versioned/typed/networking/v1alpha1"
 	ingressreconciler "knative.dev/networking/pkg/client/injection/reconciler/networking/v1alpha1/ingress"
+	operatorv1beta1listers "knative.dev/operator/pkg/client/listers/operator/v1beta1"
+	"knative.dev/pkg/controller"
 	"knative.dev/pkg/logging"
 	"knative.dev/pkg/reconciler"
+	"knative.dev/pkg/tracker"
 
+	ingressconfig "github.com/openshift-knative/serverless-operator/serving/ingress/pkg/reconciler/ingress/config"
 	"github.com/openshift-knative/serverless-operator/serving/ingress/pkg/reconciler/ingress/resources"
 	routev1 "github.com/openshift/api/route/v1"
 	routev1client "github.com/openshift/client-go/route/clientset/versioned/typed/route/v1"
@@ -21,8 +34,25 @@ import (
 
 // Reconciler implements controller.Reconciler for Ingress resources.
 type Reconciler struct {
-	routeLister routev1lister.RouteLister
-	routeClient routev1client.RouteV1Interface
+	routeLister   routev1lister.RouteLister
+	routeClient   routev1client.RouteV1Interface
+	ingressClient networkingv1alpha1client.NetworkingV1alpha1Interface
+	secretLister  corev1listers.SecretLister
+	tracker       tracker.Interface
+	dynamicClient dynamic.Interface
+	metrics       *metrics
+
+	// ingressControllerLister lists the router shards, whose namespace selectors are matched
+	// against the namespaces of namespaceLister. The shard warnings last recorded for each
+	// ingress are kept to only record them again when they change.
+	ingressControllerLister cache.GenericLister
+	namespaceLister         corev1listers.NamespaceLister
+	reportedShardWarnings   sync.Map
+
+	// knativeServingLister and httpRoutes are set if the ingresses can be exposed through
+	// HTTPRoutes instead.
+	knativeServingLister operatorv1beta1listers.KnativeServingLister
+	httpRoutes           *httpRouteInformer
 }
 
 var _ ingressreconciler.Interface = (*Reconciler)(nil)
@@ -30,6 +60,19 @@ var _ ingressreconciler.Finalizer = (*Reconciler)(nil)
 
 // FinalizeKind finalizes ingress resource.
 func (r *Reconciler) FinalizeKind(ctx context.Context, ing *v1alpha1.Ingress) reconciler.Event {
+	gateway, err := gatewayOf(r.knativeServingLister)
+	if err != nil {
+		return err
+	}
+	if err := r.deleteRoutes(ctx, ing); err != nil {
+		return err
+	}
+	r.reportedShardWarnings.Delete(types.NamespacedName{Namespace: ing.Namespace, Name: ing.Name})
+	return r.deleteHTTPRoutes(ctx, ing, gateway != nil)
+}
+
+// deleteRoutes deletes the Routes of the ingress.
+func (r *Reconciler) deleteRoutes(ctx context.Context, ing *v1alpha1.Ingress) error {
 	routes, err := r.routeList(ing)
 	if err != nil {
 		return fmt.Errorf("failed to list routes for deletion: %w", err)
@@ -43,17 +86,79 @@ func (r *Reconciler) FinalizeKind(ctx context.Context, ing *v1alpha1.Ingress) re
 	return nil
 }
 
+// deleteClusterLocalRoutes deletes the Routes exposing cluster-local hosts among the given Routes.
+func (r *Reconciler) deleteClusterLocalRoutes(ctx context.Context, routes map[string]*routev1.Route) error {
+	localLabels := ingressconfig.FromContextOrDefaults(ctx).Network.ClusterLocalRouteLabels
+	if len(localLabels) == 0 {
+		return nil
+	}
+	for _, route := range routes {
+		if labels.SelectorFromSet(localLabels).Matches(labels.Set(route.Labels)) {
+			if err := r.deleteRoute(ctx, route); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // ReconcileKind reconciles ingress resource.
 func (r *Reconciler) ReconcileKind(ctx context.Context, ing *v1alpha1.Ingress) reconciler.Event {
 	logger := logging.FromContext(ctx)
 
+	start := time.Now()
+	defer func() {
+		r.metrics.recordReconcileDuration(ctx, ing, time.Since(start))
+	}()
+
+	gateway, err := gatewayOf(r.knativeServingLister)
+	if err != nil {
+		return err
+	}
+	if gateway != nil {
+		// The ingress is exposed through HTTPRoutes, clean up its Routes.
+		if err := r.deleteRoutes(ctx, ing); err != nil {
+			return err
+		}
+		if err := r.reconcileHTTPRoutes(ctx, ing, *gateway); err != nil {
+			return err
+		}
+		return r.reconcileAdmission(ctx, ing, nil)
+	}
+
 	existingMap, err := r.routeList(ing)
 	if err != nil {
 		return fmt.Errorf("failed to list routes: %w", err)
 	}
 
-	routes, err := resources.MakeRoutes(ing)
+	tlsSecret, err := r.tlsSecret(ing)
 	if err != nil {
+		return err
+	}
+
+	routes, err := r.wildcardRoutes(ctx, ing)
+	if err != nil {
+		return err
+	}
+	if routes == nil {
+		routes, err = resources.MakeRoutes(ctx, ing, tlsSecret)
+	}
+	if err == nil {
+		err = r.validateClusterLocalShards(ctx, routes)
+	}
+	if err != nil {
+		r.metrics.recordRouteGenerationError(ctx, err)
+	}
+	if stderrors.Is(err, resources.ErrClusterLocalRoutesExposed) {
+		// Don't leave the cluster-local hosts exposed by the Routes created before.
+		if err := r.deleteClusterLocalRoutes(ctx, existingMap); err != nil {
+			return err
+		}
+	}
+	if reason := invalidAnnotationReason(err); reason != "" {
+		// The ingress is reconciled again once the annotation or the router shards are fixed.
+		return controller.NewPermanentError(reconciler.NewEvent(corev1.EventTypeWarning, reason, err.Error()))
+	} else if err != nil {
 		logger.Warnf("Failed to generate routes from ingress %v", err)
 		// Returning nil aborts the reconciliation. It will be retriggered once the status of the ingress changes.
 		return nil
@@ -64,14 +169,55 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, ing *v1alpha1.Ingress) r
 		}
 		delete(existingMap, route.Name)
 	}
-	// If routes remains in existingMap, it must be obsoleted routes. Clean them up.
+	// If routes remains in existingMap, it must be obsoleted routes, e.g. replaced by wildcard Routes. Clean them up.
 	for _, rt := range existingMap {
 		if err := r.deleteRoute(ctx, rt); err != nil {
 			return err
 		}
 	}
+	// The ingress is exposed through Routes, clean up its HTTPRoutes if a Gateway was configured.
+	if err := r.deleteHTTPRoutes(ctx, ing, false); err != nil {
+		return err
+	}
 
-	return nil
+	if err := r.validateShards(ctx, ing, routes); err != nil {
+		return err
+	}
+
+	return r.reconcileAdmission(ctx, ing, routes)
+}
+
+// wildcardRoutes returns the wildcard Routes exposing the ingress, nil if the ingress needs Routes
+// of its own or the router shards don't allow wildcard Routes.
+func (r *Reconciler) wildcardRoutes(ctx context.Context, ing *v1alpha1.Ingress) ([]*routev1.Route, error) {
+	routes, ok, err := resources.MakeWildcardRoutes(ctx, ing)
+	if err != nil || !ok {
+		// MakeRoutes fails the same way, it reports the error.
+		return nil, nil
+	}
+	allowed, err := r.wildcardsAllowed(ctx, routes)
+	if err != nil || !allowed {
+		return nil, err
+	}
+	return routes, nil
+}
+
+// invalidAnnotationReason returns the event reason of an error caused by an invalid annotation
+// of the ingress or by router shards it can't be exposed on, empty for any other error.
+func invalidAnnotationReason(err error) string {
+	switch {
+	case stderrors.Is(err, resources.ErrInvalidTLSTermination):
+		return "InvalidTLSTermination"
+	case stderrors.Is(err, resources.ErrInvalidRouteLabels):
+		return "InvalidRouteLabels"
+	case stderrors.Is(err, resources.ErrInvalidRouteOption):
+		return "InvalidRouteOption"
+	case stderrors.Is(err, resources.ErrClusterLocalRoutesNotConfigured):
+		return "ClusterLocalRoutesNotConfigured"
+	case stderrors.Is(err, resources.ErrClusterLocalRoutesExposed):
+		return "ClusterLocalRoutesExposed"
+	}
+	return ""
 }
 
 func (r *Reconciler) deleteRoute(ctx context.Context, route *routev1.Route) error {
@@ -80,6 +226,7 @@ func (r *Reconciler) deleteRoute(ctx context.Context, route *routev1.Route) erro
 	if err := r.routeClient.Routes(route.Namespace).Delete(ctx, route.Name, metav1.DeleteOptions{}); err != nil {
 		return fmt.Errorf("failed to delete route: %w", err)
 	}
+	r.metrics.recordRouteOperation(ctx, operationDelete)
 	return nil
 }
 
@@ -93,6 +240,7 @@ func (r *Reconciler) reconcileRoute(ctx context.Context, desired *routev1.Route)
 		if _, err := r.routeClient.Routes(desired.Namespace).Create(ctx, desired, metav1.CreateOptions{}); err != nil {
 			return fmt.Errorf("failed to create route :%w", err)
 		}
+		r.metrics.recordRouteOperation(ctx, operationCreate)
 	} else if err != nil {
 		return fmt.Errorf("failed to get route: %w", err)
 	} else if !equality.Semantic.DeepEqual(route.Spec, desired.Spec) ||
@@ -107,6 +255,7 @@ func (r *Reconciler) reconcileRoute(ctx context.Context, desired *routev1.Route)
 		if _, err := r.routeClient.Routes(existing.Namespace).Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
 			return fmt.Errorf("failed to update route :%w", err)
 		}
+		r.metrics.recordRouteOperation(ctx, operationUpdate)
 	}
 
 	return nil
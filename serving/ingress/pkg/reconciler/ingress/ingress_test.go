@@ -9,6 +9,7 @@ import (
 	routev1 "github.com/openshift/api/route/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	clientgotesting "k8s.io/client-go/testing"
@@ -16,12 +17,15 @@ import (
 	"knative.dev/networking/pkg/apis/networking/v1alpha1"
 	networkingclient "knative.dev/networking/pkg/client/injection/client/fake"
 	ingressreconciler "knative.dev/networking/pkg/client/injection/reconciler/networking/v1alpha1/ingress"
+	operatorv1beta1 "knative.dev/operator/pkg/apis/operator/v1beta1"
+	"knative.dev/pkg/apis"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/logging"
 	"knative.dev/pkg/ptr"
 	"knative.dev/serving/pkg/apis/serving"
 
+	ingressconfig "github.com/openshift-knative/serverless-operator/serving/ingress/pkg/reconciler/ingress/config"
 	"github.com/openshift-knative/serverless-operator/serving/ingress/pkg/reconciler/ingress/resources"
 	sotesting "github.com/openshift-knative/serverless-operator/serving/ingress/pkg/reconciler/testing"
 	rectesting "knative.dev/pkg/reconciler/testing"
@@ -41,6 +45,7 @@ const (
 
 func TestKourierReconcile(t *testing.T) {
 	key := ingNamespace + "/" + ingName
+	rejection := `Route ` + ingressNamespace + `/` + routeName + ` for host "` + domainName + `" was rejected by router shard "default": route foo already exposes ` + domainName
 
 	table := rectesting.TableTest{{
 		Name: "bad workqueue key",
@@ -182,12 +187,255 @@ func TestKourierReconcile(t *testing.T) {
 		WantEvents: []string{
 			rectesting.Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", ingName),
 		},
+	}, {
+		Name:                    "remove routes when exposed through HTTPRoutes",
+		SkipNamespaceValidation: true,
+		Key:                     key,
+		Objects: []runtime.Object{
+			ing(ingNamespace, ingName),
+			route(ingressNamespace, routeName),
+			knativeServingWithGateway("openshift-ingress/knative"),
+		},
+		WantDeletes: []clientgotesting.DeleteActionImpl{{
+			ActionImpl: clientgotesting.ActionImpl{
+				Namespace: ingressNamespace,
+				Resource:  routev1.GroupVersion.WithResource("routes"),
+			},
+			Name: routeName,
+		}},
+	}, {
+		Name:    "invalid gateway",
+		Key:     key,
+		WantErr: true,
+		Objects: []runtime.Object{
+			ing(ingNamespace, ingName),
+			route(ingressNamespace, routeName),
+			knativeServingWithGateway("knative"),
+		},
+		WantEvents: []string{
+			rectesting.Eventf(corev1.EventTypeWarning, "InternalError", `invalid %s annotation "knative", expected <namespace>/<name>`, GatewayAnnotationKey),
+		},
+	}, {
+		Name:                    "route rejected",
+		SkipNamespaceValidation: true,
+		Key:                     key,
+		Objects: []runtime.Object{
+			ing(ingNamespace, ingName, withOwner),
+			route(ingressNamespace, routeName, withAdmission(corev1.ConditionFalse, "HostAlreadyClaimed", "route foo already exposes "+domainName)),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: ing(ingNamespace, ingName, withOwner, withRouteAdmitted(corev1.ConditionFalse, "HostAlreadyClaimed", rejection),
+				withLoadBalancerFailed("HostAlreadyClaimed", rejection)),
+		}},
+		WantEvents: []string{
+			rectesting.Eventf(corev1.EventTypeWarning, "HostAlreadyClaimed", `Route %s/%s for host %q was rejected by router shard "default": route foo already exposes %s`,
+				ingressNamespace, routeName, domainName, domainName),
+			rectesting.Eventf(corev1.EventTypeWarning, "HostAlreadyClaimed", `Route %s/%s for host %q was rejected by router shard "default": route foo already exposes %s`,
+				ingressNamespace, routeName, domainName, domainName),
+		},
+	}, {
+		Name:                    "route admitted",
+		SkipNamespaceValidation: true,
+		Key:                     key,
+		Objects: []runtime.Object{
+			ing(ingNamespace, ingName, withRouteAdmitted(corev1.ConditionFalse, "HostAlreadyClaimed", "rejected")),
+			route(ingressNamespace, routeName, withAdmission(corev1.ConditionTrue, "", "")),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: ing(ingNamespace, ingName, withRouteAdmitted(corev1.ConditionTrue, "", "")),
+		}},
+	}, {
+		Name: "route rejected steady state",
+		Key:  key,
+		Objects: []runtime.Object{
+			// The networking layer marked the load balancer ready again.
+			ing(ingNamespace, ingName, withOwner, withRouteAdmitted(corev1.ConditionFalse, "HostAlreadyClaimed", rejection)),
+			route(ingressNamespace, routeName, withAdmission(corev1.ConditionFalse, "HostAlreadyClaimed", "route foo already exposes "+domainName)),
+		},
+	}, {
+		Name:                    "route admitted after rejection",
+		SkipNamespaceValidation: true,
+		Key:                     key,
+		Objects: []runtime.Object{
+			ing(ingNamespace, ingName, withRouteAdmitted(corev1.ConditionFalse, "HostAlreadyClaimed", rejection),
+				withLoadBalancerFailed("HostAlreadyClaimed", rejection)),
+			route(ingressNamespace, routeName, withAdmission(corev1.ConditionTrue, "", "")),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: ing(ingNamespace, ingName, withRouteAdmitted(corev1.ConditionTrue, "", ""), func(i *v1alpha1.Ingress) {
+				i.Status.MarkLoadBalancerNotReady()
+			}),
+		}},
+	}, {
+		Name: "route admitted steady state",
+		Key:  key,
+		Objects: []runtime.Object{
+			ing(ingNamespace, ingName, withRouteAdmitted(corev1.ConditionTrue, "", "")),
+			route(ingressNamespace, routeName, withAdmission(corev1.ConditionTrue, "", "")),
+		},
+	}, {
+		Name:                    "remove route admission when exposed through HTTPRoutes",
+		SkipNamespaceValidation: true,
+		Key:                     key,
+		Objects: []runtime.Object{
+			ing(ingNamespace, ingName, withRouteAdmitted(corev1.ConditionTrue, "", "")),
+			route(ingressNamespace, routeName, withAdmission(corev1.ConditionTrue, "", "")),
+			knativeServingWithGateway("openshift-ingress/knative"),
+		},
+		WantDeletes: []clientgotesting.DeleteActionImpl{{
+			ActionImpl: clientgotesting.ActionImpl{
+				Namespace: ingressNamespace,
+				Resource:  routev1.GroupVersion.WithResource("routes"),
+			},
+			Name: routeName,
+		}},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: ing(ingNamespace, ingName),
+		}},
+	}, {
+		Name:                    "create route with certificate",
+		SkipNamespaceValidation: true,
+		Key:                     key,
+		Objects: []runtime.Object{
+			ing(ingNamespace, ingName, withTLSSecretAnnotation),
+			tlsSecret("cert"),
+		},
+		WantCreates: []runtime.Object{
+			route(ingressNamespace, routeName, withTLSSecretAnnotationOnRoute, withCertificate("cert")),
+		},
+	}, {
+		Name:                    "update rotated certificate",
+		SkipNamespaceValidation: true,
+		Key:                     key,
+		Objects: []runtime.Object{
+			ing(ingNamespace, ingName, withTLSSecretAnnotation),
+			tlsSecret("rotated"),
+			route(ingressNamespace, routeName, withTLSSecretAnnotationOnRoute, withCertificate("cert")),
+		},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: route(ingressNamespace, routeName, withTLSSecretAnnotationOnRoute, withCertificate("rotated")),
+		}},
+	}, {
+		Name:                    "create reencrypt route with system-internal-tls",
+		SkipNamespaceValidation: true,
+		Key:                     key,
+		Ctx: ingressconfig.ToContext(context.Background(), &ingressconfig.Config{
+			Network: &ingressconfig.Network{
+				InternalTLS:         true,
+				RouteTLSTermination: routev1.TLSTerminationReencrypt,
+			},
+		}),
+		Objects: []runtime.Object{ing(ingNamespace, ingName)},
+		WantCreates: []runtime.Object{
+			route(ingressNamespace, routeName, func(r *routev1.Route) {
+				r.Spec.Port.TargetPort = intstr.FromString(resources.HTTPSPort)
+				r.Spec.TLS.Termination = routev1.TLSTerminationReencrypt
+			}),
+		},
+	}, {
+		Name:                    "replace routes by a wildcard route",
+		SkipNamespaceValidation: true,
+		Key:                     key,
+		Ctx: ingressconfig.ToContext(context.Background(), &ingressconfig.Config{
+			Network: &ingressconfig.Network{WildcardRoutes: true},
+		}),
+		Objects: []runtime.Object{ing(ingNamespace, ingName), route(ingressNamespace, routeName)},
+		WantCreates: []runtime.Object{
+			wildcardRoute(ingNamespace + ".default.domainName"),
+		},
+		WantDeletes: []clientgotesting.DeleteActionImpl{{
+			ActionImpl: clientgotesting.ActionImpl{
+				Namespace: ingressNamespace,
+				Resource:  routev1.GroupVersion.WithResource("routes"),
+			},
+			Name: routeName,
+		}},
+	}, {
+		Name:                    "keep routes of ingresses with route options in wildcard mode",
+		SkipNamespaceValidation: true,
+		Key:                     key,
+		Ctx: ingressconfig.ToContext(context.Background(), &ingressconfig.Config{
+			Network: &ingressconfig.Network{WildcardRoutes: true},
+		}),
+		Objects: []runtime.Object{
+			ing(ingNamespace, ingName, func(i *v1alpha1.Ingress) {
+				i.Annotations[resources.BalanceAnnotation] = "leastconn"
+			}),
+			route(ingressNamespace, routeName, func(r *routev1.Route) {
+				r.Annotations[resources.BalanceAnnotation] = "leastconn"
+				r.Annotations["haproxy.router.openshift.io/balance"] = "leastconn"
+			}),
+		},
+	}, {
+		Name:                    "create route with HAProxy options",
+		SkipNamespaceValidation: true,
+		Key:                     key,
+		Objects: []runtime.Object{
+			ing(ingNamespace, ingName, func(i *v1alpha1.Ingress) {
+				i.Annotations[resources.BalanceAnnotation] = "leastconn"
+			}),
+		},
+		WantCreates: []runtime.Object{
+			route(ingressNamespace, routeName, func(r *routev1.Route) {
+				r.Annotations[resources.BalanceAnnotation] = "leastconn"
+				r.Annotations["haproxy.router.openshift.io/balance"] = "leastconn"
+			}),
+		},
+	}, {
+		Name:    "reject invalid HAProxy options",
+		Key:     key,
+		WantErr: true,
+		Objects: []runtime.Object{
+			ing(ingNamespace, ingName, func(i *v1alpha1.Ingress) {
+				i.Annotations[resources.RateLimitHTTPAnnotation] = "many"
+			}),
+			route(ingressNamespace, routeName),
+		},
+		WantEvents: []string{
+			rectesting.Eventf(corev1.EventTypeWarning, "InvalidRouteOption",
+				`invalid route option: %s value "many": expected a positive integer`, resources.RateLimitHTTPAnnotation),
+		},
+	}, {
+		Name:    "reject cluster-local routes without internal router shard",
+		Key:     key,
+		WantErr: true,
+		Objects: []runtime.Object{
+			ing(ingNamespace, ingName, func(i *v1alpha1.Ingress) {
+				i.Annotations[resources.ExposeClusterLocalAnnotation] = "true"
+			}),
+			route(ingressNamespace, routeName),
+		},
+		WantEvents: []string{
+			rectesting.Eventf(corev1.EventTypeWarning, "ClusterLocalRoutesNotConfigured",
+				"%s: %s requires %s and %s of config-network", resources.ErrClusterLocalRoutesNotConfigured,
+				resources.ExposeClusterLocalAnnotation, ingressconfig.ClusterLocalRouteLabelsKey, ingressconfig.ClusterLocalRouteDomainKey),
+		},
+	}, {
+		Name:    "certificate secret not found",
+		Key:     key,
+		WantErr: true,
+		Objects: []runtime.Object{
+			ing(ingNamespace, ingName, withTLSSecretAnnotation),
+			route(ingressNamespace, routeName),
+		},
+		WantEvents: []string{
+			rectesting.Eventf(corev1.EventTypeWarning, "TLSSecretNotFound",
+				"Secret %s/cert referenced by the %s annotation doesn't exist or isn't labeled with %s",
+				ingNamespace, resources.TLSSecretAnnotation, resources.TLSSecretLabelKey),
+		},
 	}}
 
 	table.Test(t, sotesting.MakeFactory(func(ctx context.Context, listers *sotesting.Listers, _ configmap.Watcher) controller.Reconciler {
 		r := &Reconciler{
-			routeClient: fakerouteclient.Get(ctx).RouteV1(),
-			routeLister: listers.GetRouteLister(),
+			routeClient:          fakerouteclient.Get(ctx).RouteV1(),
+			ingressClient:        networkingclient.Get(ctx).NetworkingV1alpha1(),
+			secretLister:         listers.GetSecretLister(),
+			tracker:              &rectesting.NullTracker{},
+			metrics:              testMetrics(t, nil),
+			routeLister:          listers.GetRouteLister(),
+			knativeServingLister: listers.GetKnativeServingLister(),
+			// The default router allows wildcard Routes.
+			ingressControllerLister: ingressControllerLister(wildcardIngressController()),
 		}
 
 		ingr := ingressreconciler.NewReconciler(ctx, logging.FromContext(ctx), networkingclient.Get(ctx),
@@ -349,8 +597,12 @@ func TestIstioReconcile(t *testing.T) {
 
 	table.Test(t, sotesting.MakeFactory(func(ctx context.Context, listers *sotesting.Listers, _ configmap.Watcher) controller.Reconciler {
 		r := &Reconciler{
-			routeClient: fakerouteclient.Get(ctx).RouteV1(),
-			routeLister: listers.GetRouteLister(),
+			routeClient:   fakerouteclient.Get(ctx).RouteV1(),
+			routeLister:   listers.GetRouteLister(),
+			ingressClient: networkingclient.Get(ctx).NetworkingV1alpha1(),
+			secretLister:  listers.GetSecretLister(),
+			tracker:       &rectesting.NullTracker{},
+			metrics:       testMetrics(t, nil),
 		}
 
 		ingr := ingressreconciler.NewReconciler(ctx, logging.FromContext(ctx), networkingclient.Get(ctx),
@@ -424,8 +676,80 @@ func ingIstio(ns, name string, opts ...ingressOption) *v1alpha1.Ingress {
 	return i
 }
 
+func withOwner(i *v1alpha1.Ingress) {
+	i.OwnerReferences = []metav1.OwnerReference{{
+		APIVersion: "serving.knative.dev/v1",
+		Kind:       "Route",
+		Name:       ingName,
+		UID:        "e2b7e3b6-3b36-4a46-9d4c-2ef3f0f1a1f5",
+		Controller: ptr.Bool(true),
+	}}
+}
+
+func withRouteAdmitted(status corev1.ConditionStatus, reason, message string) ingressOption {
+	return func(i *v1alpha1.Ingress) {
+		i.Status.SetConditions(apis.Conditions{{
+			Type:     IngressConditionRouteAdmitted,
+			Status:   status,
+			Severity: apis.ConditionSeverityInfo,
+			Reason:   reason,
+			Message:  message,
+		}})
+	}
+}
+
+func withLoadBalancerFailed(reason, message string) ingressOption {
+	return func(i *v1alpha1.Ingress) {
+		i.Status.MarkLoadBalancerFailed(reason, message)
+	}
+}
+
+func withTLSSecretAnnotation(i *v1alpha1.Ingress) {
+	i.Annotations[resources.TLSSecretAnnotation] = "cert"
+}
+
+func tlsSecret(cert string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cert",
+			Namespace: ingNamespace,
+			Labels:    map[string]string{resources.TLSSecretLabelKey: ""},
+		},
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       []byte(cert),
+			corev1.TLSPrivateKeyKey: []byte("key"),
+		},
+	}
+}
+
 type routeOption func(*routev1.Route)
 
+func withTLSSecretAnnotationOnRoute(r *routev1.Route) {
+	r.Annotations[resources.TLSSecretAnnotation] = "cert"
+}
+
+func withCertificate(cert string) routeOption {
+	return func(r *routev1.Route) {
+		r.Spec.TLS.Certificate = cert
+		r.Spec.TLS.Key = "key"
+	}
+}
+
+func withAdmission(status corev1.ConditionStatus, reason, message string) routeOption {
+	return func(r *routev1.Route) {
+		r.Status.Ingress = []routev1.RouteIngress{{
+			Host:       r.Spec.Host,
+			RouterName: "default",
+			Conditions: []routev1.RouteIngressCondition{{
+				Type:    routev1.RouteAdmitted,
+				Status:  status,
+				Reason:  reason,
+				Message: message,
+			}},
+		}}
+	}
+}
+
 func route(ns, name string, opts ...routeOption) *routev1.Route {
 	r := &routev1.Route{
 		ObjectMeta: metav1.ObjectMeta{
@@ -466,6 +790,42 @@ func route(ns, name string, opts ...routeOption) *routev1.Route {
 	return r
 }
 
+func wildcardRoute(subdomain string) *routev1.Route {
+	return &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resources.WildcardRouteName(svcName, subdomain),
+			Namespace: ingressNamespace,
+			Labels:    map[string]string{resources.WildcardRouteLabelKey: "true"},
+			Annotations: map[string]string{
+				resources.TimeoutAnnotation:          resources.DefaultTimeout,
+				networking.IngressClassAnnotationKey: kourierIngressClassName,
+			},
+		},
+		Spec: routev1.RouteSpec{
+			Host: "wildcard." + subdomain,
+			Port: &routev1.RoutePort{
+				TargetPort: intstr.FromString(resources.HTTPPort),
+			},
+			To: routev1.RouteTargetReference{
+				Kind:   "Service",
+				Name:   svcName,
+				Weight: ptr.Int32(100),
+			},
+			TLS: &routev1.TLSConfig{
+				Termination:                   routev1.TLSTerminationEdge,
+				InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyAllow,
+			},
+			WildcardPolicy: routev1.WildcardPolicySubdomain,
+		},
+	}
+}
+
+func wildcardIngressController() *unstructured.Unstructured {
+	ic := ingressController("default", nil, "", "default.domainName")
+	_ = unstructured.SetNestedField(ic.Object, wildcardsAllowedPolicy, "spec", "routeAdmission", "wildcardPolicy")
+	return ic
+}
+
 func routeIstio(ns, name string, opts ...routeOption) *routev1.Route {
 	r := &routev1.Route{
 		ObjectMeta: metav1.ObjectMeta{
@@ -505,3 +865,13 @@ func routeIstio(ns, name string, opts ...routeOption) *routev1.Route {
 	}
 	return r
 }
+
+func knativeServingWithGateway(gateway string) *operatorv1beta1.KnativeServing {
+	return &operatorv1beta1.KnativeServing{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "knative-serving",
+			Namespace:   "knative-serving",
+			Annotations: map[string]string{GatewayAnnotationKey: gateway},
+		},
+	}
+}
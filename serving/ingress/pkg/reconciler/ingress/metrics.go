@@ -0,0 +1,155 @@
+package ingress
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	routev1lister "github.com/openshift/client-go/route/listers/route/v1"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"knative.dev/networking/pkg/apis/networking"
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+	"knative.dev/pkg/observability/attributekey"
+
+	"github.com/openshift-knative/serverless-operator/serving/ingress/pkg/reconciler/ingress/resources"
+)
+
+const scopeName = "github.com/openshift-knative/serverless-operator/serving/ingress"
+
+const (
+	operationCreate = "create"
+	operationUpdate = "update"
+	operationDelete = "delete"
+)
+
+var (
+	// operationAttr is the operation on a Route, either create, update or delete.
+	operationAttr = attributekey.String("ocp.ingress.route.operation")
+	// ingressClassAttr is the class of the reconciled ingress.
+	ingressClassAttr = attributekey.String("ocp.ingress.class")
+	// errorTypeAttr is the reason the Routes of an ingress couldn't be generated.
+	errorTypeAttr = attributekey.String("error.type")
+	// namespaceAttr is the namespace of the Routes, i.e. of the ingress gateway.
+	namespaceAttr = attributekey.String("k8s.namespace.name")
+)
+
+type metrics struct {
+	routeOperations       metric.Int64Counter
+	orphanedRoutesDeleted metric.Int64Counter
+	routeGenerationErrors metric.Int64Counter
+	reconcileDuration     metric.Float64Histogram
+}
+
+// newMetrics registers the instruments of the ingress reconcilers with the given provider, the
+// global one if nil.
+func newMetrics(provider metric.MeterProvider) (*metrics, error) {
+	if provider == nil {
+		provider = otel.GetMeterProvider()
+	}
+	meter := provider.Meter(scopeName)
+
+	var (
+		m   metrics
+		err error
+	)
+	m.routeOperations, err = meter.Int64Counter(
+		"ocp.ingress.route.operations",
+		metric.WithDescription("The number of Routes created, updated and deleted"),
+		metric.WithUnit("{operation}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	m.orphanedRoutesDeleted, err = meter.Int64Counter(
+		"ocp.ingress.route.orphaned.deleted",
+		metric.WithDescription("The number of Routes deleted because their Ingress doesn't exist anymore"),
+		metric.WithUnit("{route}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	m.routeGenerationErrors, err = meter.Int64Counter(
+		"ocp.ingress.route.generation.errors",
+		metric.WithDescription("The number of failures to generate the Routes of an Ingress"),
+		metric.WithUnit("{error}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	m.reconcileDuration, err = meter.Float64Histogram(
+		"ocp.ingress.reconcile.duration",
+		metric.WithDescription("The duration of the reconciliation of an Ingress"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (m *metrics) recordRouteOperation(ctx context.Context, operation string) {
+	m.routeOperations.Add(ctx, 1, metric.WithAttributes(operationAttr.With(operation)))
+}
+
+func (m *metrics) recordReconcileDuration(ctx context.Context, ing *v1alpha1.Ingress, d time.Duration) {
+	m.reconcileDuration.Record(ctx, d.Seconds(),
+		metric.WithAttributes(ingressClassAttr.With(ing.GetAnnotations()[networking.IngressClassAnnotationKey])))
+}
+
+func (m *metrics) recordRouteGenerationError(ctx context.Context, err error) {
+	m.routeGenerationErrors.Add(ctx, 1, metric.WithAttributes(errorTypeAttr.With(routeGenerationErrorType(err))))
+}
+
+// routeGenerationErrorType returns a bounded value describing the given error of MakeRoutes.
+func routeGenerationErrorType(err error) string {
+	if reason := invalidAnnotationReason(err); reason != "" {
+		return reason
+	}
+	if stderrors.Is(err, resources.ErrNoValidLoadbalancerDomain) {
+		return "NoValidLoadBalancerDomain"
+	}
+	return "Other"
+}
+
+// observeRoutes reports the number of Routes generated for the ingresses, per namespace.
+func observeRoutes(provider metric.MeterProvider, routeLister routev1lister.RouteLister) error {
+	if provider == nil {
+		provider = otel.GetMeterProvider()
+	}
+	meter := provider.Meter(scopeName)
+
+	routes, err := meter.Int64ObservableGauge(
+		"ocp.ingress.routes",
+		metric.WithDescription("The number of Routes exposing Ingresses"),
+		metric.WithUnit("{route}"),
+	)
+	if err != nil {
+		return err
+	}
+
+	owned, _ := labels.NewRequirement(resources.OpenShiftIngressLabelKey, selection.Exists, nil)
+	wildcard, _ := labels.NewRequirement(resources.WildcardRouteLabelKey, selection.Exists, nil)
+	selectors := []labels.Selector{labels.NewSelector().Add(*owned), labels.NewSelector().Add(*wildcard)}
+
+	_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		counts := make(map[string]int64)
+		for _, selector := range selectors {
+			list, err := routeLister.List(selector)
+			if err != nil {
+				return err
+			}
+			for _, route := range list {
+				counts[route.Namespace]++
+			}
+		}
+		for namespace, count := range counts {
+			o.ObserveInt64(routes, count, metric.WithAttributes(namespaceAttr.With(namespace)))
+		}
+		return nil
+	}, routes)
+	return err
+}
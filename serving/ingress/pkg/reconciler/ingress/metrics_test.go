@@ -0,0 +1,91 @@
+package ingress
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/openshift-knative/serverless-operator/serving/ingress/pkg/reconciler/ingress/resources"
+	rtesting "github.com/openshift-knative/serverless-operator/serving/ingress/pkg/reconciler/testing"
+)
+
+func TestRouteGenerationErrorType(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{{
+		err:  resources.ErrNoValidLoadbalancerDomain,
+		want: "NoValidLoadBalancerDomain",
+	}, {
+		err:  fmt.Errorf("%w: bad value", resources.ErrInvalidRouteOption),
+		want: "InvalidRouteOption",
+	}, {
+		err:  fmt.Errorf("invalid timeout value: %s", "foo"),
+		want: "Other",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.want, func(t *testing.T) {
+			if got := routeGenerationErrorType(test.err); got != test.want {
+				t.Errorf("routeGenerationErrorType() = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestObserveRoutes(t *testing.T) {
+	unlabeled := route(ingressNamespace, "unlabeled")
+	delete(unlabeled.Labels, resources.OpenShiftIngressLabelKey)
+	wildcard := route("other-ingress-namespace", "wildcard")
+	delete(wildcard.Labels, resources.OpenShiftIngressLabelKey)
+	wildcard.Labels[resources.WildcardRouteLabelKey] = "true"
+
+	listers := rtesting.NewListers([]runtime.Object{
+		route(ingressNamespace, "route1"),
+		route(ingressNamespace, "route2"),
+		unlabeled,
+		wildcard,
+	})
+	reader := sdkmetric.NewManualReader()
+	if err := observeRoutes(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)), listers.GetRouteLister()); err != nil {
+		t.Fatalf("observeRoutes() = %v", err)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect() = %v", err)
+	}
+	got := make(map[string]int64)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			gauge, ok := m.Data.(metricdata.Gauge[int64])
+			if !ok || m.Name != "ocp.ingress.routes" {
+				continue
+			}
+			for _, dp := range gauge.DataPoints {
+				namespace, _ := dp.Attributes.Value(attribute.Key(namespaceAttr))
+				got[namespace.AsString()] = dp.Value
+			}
+		}
+	}
+	want := map[string]int64{ingressNamespace: 2, "other-ingress-namespace": 1}
+	if !cmp.Equal(got, want) {
+		t.Errorf("ocp.ingress.routes = %s", cmp.Diff(want, got))
+	}
+}
+
+func testMetrics(t *testing.T, provider metric.MeterProvider) *metrics {
+	t.Helper()
+	m, err := newMetrics(provider)
+	if err != nil {
+		t.Fatalf("newMetrics() = %v", err)
+	}
+	return m
+}
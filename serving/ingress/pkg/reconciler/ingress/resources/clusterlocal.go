@@ -0,0 +1,124 @@
+package resources
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	socommon "github.com/openshift-knative/serverless-operator/pkg/common"
+	ingressconfig "github.com/openshift-knative/serverless-operator/serving/ingress/pkg/reconciler/ingress/config"
+	routev1 "github.com/openshift/api/route/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	networkingv1alpha1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
+	"knative.dev/pkg/kmap"
+	"knative.dev/pkg/ptr"
+)
+
+// ExposeClusterLocalAnnotation opts a Knative Service in to exposing its cluster-local hosts, e.g.
+// "hello.ns.svc.cluster.local", on the internal router shard configured in config-network, as
+// "hello.ns.<openshift-cluster-local-route-domain>". The Routes target the private load balancer
+// and are labeled with the internal shard labels only, keeping them out of the public router.
+const ExposeClusterLocalAnnotation = socommon.ServingDownstreamDomain + "/exposeClusterLocal"
+
+// ErrClusterLocalRoutesNotConfigured indicates that ExposeClusterLocalAnnotation is set while no
+// internal router shard is configured in config-network.
+var ErrClusterLocalRoutesNotConfigured = errors.New("no internal router shard configured for cluster-local routes")
+
+// ErrClusterLocalRoutesExposed indicates that a router shard other than the internal one selects
+// the labels of the Routes exposing cluster-local hosts, which would expose them publicly.
+var ErrClusterLocalRoutesExposed = errors.New("cluster-local routes selected by a router shard other than the internal one")
+
+// exposesClusterLocal returns whether the cluster-local rules of the given ingress are exposed on
+// the internal router shard.
+func exposesClusterLocal(ci *networkingv1alpha1.Ingress, network *ingressconfig.Network) (bool, error) {
+	if !strings.EqualFold(ci.GetAnnotations()[ExposeClusterLocalAnnotation], "true") {
+		return false, nil
+	}
+	if network.ClusterLocalRouteDomain == "" || len(network.ClusterLocalRouteLabels) == 0 {
+		return false, fmt.Errorf("%w: %s requires %s and %s of config-network", ErrClusterLocalRoutesNotConfigured,
+			ExposeClusterLocalAnnotation, ingressconfig.ClusterLocalRouteLabelsKey, ingressconfig.ClusterLocalRouteDomainKey)
+	}
+	return true, nil
+}
+
+// makeClusterLocalRoute creates the Route exposing a cluster-local rule on the internal router shard.
+// The router rewrites the Host header to the cluster-local host, which the private load balancer
+// routes by.
+func makeClusterLocalRoute(ci *networkingv1alpha1.Ingress, rule networkingv1alpha1.IngressRule, network *ingressconfig.Network) (*routev1.Route, error) {
+	annotations := ci.GetAnnotations()
+	if _, ok := annotations[DisableRouteAnnotation]; ok {
+		return nil, nil
+	}
+
+	// Take the fully qualified host, e.g. hello.ns.svc.cluster.local over hello.ns.
+	localHost := ""
+	for _, host := range rule.Hosts {
+		if len(host) > len(localHost) {
+			localHost = host
+		}
+	}
+	parts := strings.Split(localHost, ".")
+	if len(parts) < 2 {
+		return nil, nil
+	}
+	host := parts[0] + "." + parts[1] + "." + network.ClusterLocalRouteDomain
+
+	annotations, err := routeAnnotations(ci, network, annotations)
+	if err != nil {
+		return nil, err
+	}
+	serviceName, namespace, err := privateLoadBalancer(ci)
+	if err != nil {
+		return nil, err
+	}
+
+	terminationPolicy := routev1.InsecureEdgeTerminationPolicyAllow
+	if ci.Spec.HTTPOption == networkingv1alpha1.HTTPOptionRedirected {
+		terminationPolicy = routev1.InsecureEdgeTerminationPolicyRedirect
+	}
+
+	route := &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        routeName(string(ci.GetUID()), host),
+			Namespace:   namespace,
+			Labels:      kmap.Union(network.ClusterLocalRouteLabels, ownerLabels(ci)),
+			Annotations: annotations,
+		},
+		Spec: routev1.RouteSpec{
+			Host: host,
+			Port: &routev1.RoutePort{
+				TargetPort: intstr.FromString(HTTPPort),
+			},
+			To: routev1.RouteTargetReference{
+				Kind:   "Service",
+				Name:   serviceName,
+				Weight: ptr.Int32(100),
+			},
+			TLS: &routev1.TLSConfig{
+				Termination:                   routev1.TLSTerminationEdge,
+				InsecureEdgeTerminationPolicy: terminationPolicy,
+			},
+			HTTPHeaders: &routev1.RouteHTTPHeaders{
+				Actions: routev1.RouteHTTPHeaderActions{
+					Request: []routev1.RouteHTTPHeader{{
+						Name: "Host",
+						Action: routev1.RouteHTTPHeaderActionUnion{
+							Type: routev1.Set,
+							Set:  &routev1.RouteSetHTTPHeader{Value: localHost},
+						},
+					}},
+				},
+			},
+			WildcardPolicy: routev1.WildcardPolicyNone,
+		},
+	}
+
+	// The router can't rewrite headers of passthrough Routes, so re-encrypt the traffic to the
+	// private load balancer when it serves TLS.
+	if network.InternalTLS || isTLSDestination(rule) {
+		route.Spec.Port.TargetPort = intstr.FromString(HTTPSPort)
+		route.Spec.TLS.Termination = routev1.TLSTerminationReencrypt
+	}
+	return route, nil
+}
@@ -0,0 +1,150 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	routev1 "github.com/openshift/api/route/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"knative.dev/networking/pkg/apis/networking"
+	networkingv1alpha1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
+	"knative.dev/pkg/ptr"
+	"knative.dev/serving/pkg/apis/serving"
+
+	ingressconfig "github.com/openshift-knative/serverless-operator/serving/ingress/pkg/reconciler/ingress/config"
+)
+
+func TestMakeClusterLocalRoutes(t *testing.T) {
+	const (
+		privateService = "lb-service-internal"
+		internalDomain = "private.example.com"
+		internalHost   = "test.default." + internalDomain
+	)
+	internalShard := map[string]string{"router": "private"}
+	configured := &ingressconfig.Network{
+		RouteTLSTermination:     routev1.TLSTerminationPassthrough,
+		ClusterLocalRouteLabels: internalShard,
+		ClusterLocalRouteDomain: internalDomain,
+	}
+	withPrivateLoadBalancer := func(ing *networkingv1alpha1.Ingress) {
+		ing.Status.PrivateLoadBalancer = &networkingv1alpha1.LoadBalancerStatus{
+			Ingress: []networkingv1alpha1.LoadBalancerIngressStatus{{
+				DomainInternal: fmt.Sprintf("%s.%s.svc.cluster.local", privateService, lbNamespace),
+			}},
+		}
+	}
+	withExposeClusterLocal := func(ing *networkingv1alpha1.Ingress) {
+		annos := ing.GetAnnotations()
+		if annos == nil {
+			annos = map[string]string{}
+		}
+		annos[ExposeClusterLocalAnnotation] = "true"
+		ing.SetAnnotations(annos)
+	}
+	localRule := rule(withLocalVisibilityRule, withHosts([]string{"test.default", "test.default.svc", localDomain}))
+	internalRoute := func(termination routev1.TLSTerminationType, port string) *routev1.Route {
+		return &routev1.Route{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      routeName(uid, internalHost),
+				Namespace: lbNamespace,
+				Labels: map[string]string{
+					"router":                          "private",
+					networking.IngressLabelKey:        "ingress",
+					serving.RouteLabelKey:             "route1",
+					serving.RouteNamespaceLabelKey:    "default",
+					OpenShiftIngressLabelKey:          "ingress",
+					OpenShiftIngressNamespaceLabelKey: "default",
+				},
+				Annotations: map[string]string{
+					ExposeClusterLocalAnnotation: "true",
+					TimeoutAnnotation:            DefaultTimeout,
+				},
+			},
+			Spec: routev1.RouteSpec{
+				Host: internalHost,
+				Port: &routev1.RoutePort{TargetPort: intstr.FromString(port)},
+				To: routev1.RouteTargetReference{
+					Kind:   "Service",
+					Name:   privateService,
+					Weight: ptr.Int32(100),
+				},
+				TLS: &routev1.TLSConfig{
+					Termination:                   termination,
+					InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyAllow,
+				},
+				HTTPHeaders: &routev1.RouteHTTPHeaders{
+					Actions: routev1.RouteHTTPHeaderActions{
+						Request: []routev1.RouteHTTPHeader{{
+							Name: "Host",
+							Action: routev1.RouteHTTPHeaderActionUnion{
+								Type: routev1.Set,
+								Set:  &routev1.RouteSetHTTPHeader{Value: localDomain},
+							},
+						}},
+					},
+				},
+				WildcardPolicy: routev1.WildcardPolicyNone,
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		network *ingressconfig.Network
+		ingress *networkingv1alpha1.Ingress
+		want    []*routev1.Route
+		wantErr error
+	}{{
+		name:    "cluster-local rule without annotation",
+		network: configured,
+		ingress: ingress(withPrivateLoadBalancer, withRules(localRule)),
+		want:    []*routev1.Route{},
+	}, {
+		name:    "cluster-local rule exposed on the internal shard",
+		network: configured,
+		ingress: ingress(withPrivateLoadBalancer, withExposeClusterLocal, withRules(localRule)),
+		want:    []*routev1.Route{internalRoute(routev1.TLSTerminationEdge, HTTPPort)},
+	}, {
+		name: "cluster-local rule re-encrypted with internal TLS",
+		network: &ingressconfig.Network{
+			RouteTLSTermination:     routev1.TLSTerminationPassthrough,
+			InternalTLS:             true,
+			ClusterLocalRouteLabels: internalShard,
+			ClusterLocalRouteDomain: internalDomain,
+		},
+		ingress: ingress(withPrivateLoadBalancer, withExposeClusterLocal, withRules(localRule)),
+		want:    []*routev1.Route{internalRoute(routev1.TLSTerminationReencrypt, HTTPSPort)},
+	}, {
+		name:    "cluster-local rule of a disabled route",
+		network: configured,
+		ingress: ingress(withPrivateLoadBalancer, withExposeClusterLocal, withDisabledAnnotation, withRules(localRule)),
+		want:    []*routev1.Route{},
+	}, {
+		name:    "internal shard not configured",
+		network: &ingressconfig.Network{RouteTLSTermination: routev1.TLSTerminationPassthrough},
+		ingress: ingress(withPrivateLoadBalancer, withExposeClusterLocal, withRules(localRule)),
+		wantErr: ErrClusterLocalRoutesNotConfigured,
+	}, {
+		name:    "no private load balancer",
+		network: configured,
+		ingress: ingress(withExposeClusterLocal, withRules(localRule)),
+		wantErr: ErrNoValidLoadbalancerDomain,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := ingressconfig.ToContext(context.Background(), &ingressconfig.Config{Network: test.network})
+			routes, err := MakeRoutes(ctx, test.ingress, nil)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("MakeRoutes() error = %v, want %v", err, test.wantErr)
+			}
+			if !cmp.Equal(routes, test.want) {
+				t.Error("Unexpected routes (-want, +got):", cmp.Diff(test.want, routes))
+			}
+		})
+	}
+}
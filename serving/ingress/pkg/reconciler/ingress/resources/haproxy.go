@@ -0,0 +1,138 @@
+package resources
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	socommon "github.com/openshift-knative/serverless-operator/pkg/common"
+)
+
+// Typed annotations of Knative Services setting the HAProxy options of their Routes. Their values are
+// validated before being translated to the respective router annotations.
+const (
+	// RateLimitConcurrentTCPAnnotation limits the number of concurrent TCP connections per client IP.
+	RateLimitConcurrentTCPAnnotation = socommon.ServingDownstreamDomain + "/rateLimitConcurrentTCP"
+	// RateLimitHTTPAnnotation limits the number of HTTP requests per client IP in 3 seconds.
+	RateLimitHTTPAnnotation = socommon.ServingDownstreamDomain + "/rateLimitHTTP"
+	// RateLimitTCPAnnotation limits the number of TCP connections per client IP in 3 seconds.
+	RateLimitTCPAnnotation = socommon.ServingDownstreamDomain + "/rateLimitTCP"
+	// IPAllowlistAnnotation restricts the access to the given IPs and CIDRs, separated by commas or spaces.
+	IPAllowlistAnnotation = socommon.ServingDownstreamDomain + "/ipAllowlist"
+	// StickyCookieAnnotation names the cookie pinning a client to a backend.
+	StickyCookieAnnotation = socommon.ServingDownstreamDomain + "/stickyCookie"
+	// HSTSAnnotation sets the Strict-Transport-Security header, e.g. "max-age=31536000;includeSubDomains".
+	HSTSAnnotation = socommon.ServingDownstreamDomain + "/hsts"
+	// BalanceAnnotation sets the load-balancing algorithm across the ingress gateway replicas.
+	BalanceAnnotation = socommon.ServingDownstreamDomain + "/balance"
+)
+
+const (
+	haproxyRateLimitAnnotation              = "haproxy.router.openshift.io/rate-limit-connections"
+	haproxyRateLimitConcurrentTCPAnnotation = haproxyRateLimitAnnotation + ".concurrent-tcp"
+	haproxyRateLimitHTTPAnnotation          = haproxyRateLimitAnnotation + ".rate-http"
+	haproxyRateLimitTCPAnnotation           = haproxyRateLimitAnnotation + ".rate-tcp"
+	haproxyIPAllowlistAnnotation            = "haproxy.router.openshift.io/ip_allowlist"
+	haproxyCookieNameAnnotation             = "router.openshift.io/cookie_name"
+	haproxyHSTSAnnotation                   = "haproxy.router.openshift.io/hsts_header"
+	haproxyBalanceAnnotation                = "haproxy.router.openshift.io/balance"
+
+	// maxIPAllowlistEntries is the maximum number of entries the router accepts.
+	maxIPAllowlistEntries = 61
+)
+
+// ErrInvalidRouteOption indicates that one of the typed HAProxy annotations has an invalid value.
+var ErrInvalidRouteOption = errors.New("invalid route option")
+
+var (
+	cookieNameRegexp = regexp.MustCompile("^[A-Za-z0-9!#$%&'*+.^_`|~-]+$")
+	hstsRegexp       = regexp.MustCompile(`(?i)^max-age=\d+(;\s*includeSubDomains)?(;\s*preload)?$`)
+	balanceValues    = []string{"roundrobin", "leastconn", "source", "random"}
+)
+
+// routeOption translates the value of a typed annotation to router annotations.
+type routeOption func(value string) (map[string]string, error)
+
+var routeOptions = map[string]routeOption{
+	RateLimitConcurrentTCPAnnotation: rateLimit(haproxyRateLimitConcurrentTCPAnnotation),
+	RateLimitHTTPAnnotation:          rateLimit(haproxyRateLimitHTTPAnnotation),
+	RateLimitTCPAnnotation:           rateLimit(haproxyRateLimitTCPAnnotation),
+	IPAllowlistAnnotation:            ipAllowlist,
+	StickyCookieAnnotation:           stickyCookie,
+	HSTSAnnotation:                   hsts,
+	BalanceAnnotation:                balance,
+}
+
+// haproxyAnnotations returns the router annotations set by the typed annotations of the ingress.
+func haproxyAnnotations(annotations map[string]string) (map[string]string, error) {
+	result := make(map[string]string)
+	for key, option := range routeOptions {
+		value, ok := annotations[key]
+		if !ok {
+			continue
+		}
+		translated, err := option(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s value %q: %w", ErrInvalidRouteOption, key, value, err)
+		}
+		for k, v := range translated {
+			result[k] = v
+		}
+	}
+	return result, nil
+}
+
+func rateLimit(key string) routeOption {
+	return func(value string) (map[string]string, error) {
+		if limit, err := strconv.ParseUint(value, 10, 32); err != nil || limit == 0 {
+			return nil, errors.New("expected a positive integer")
+		}
+		return map[string]string{
+			haproxyRateLimitAnnotation: "true",
+			key:                        value,
+		}, nil
+	}
+}
+
+func ipAllowlist(value string) (map[string]string, error) {
+	entries := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+	if len(entries) == 0 || len(entries) > maxIPAllowlistEntries {
+		return nil, fmt.Errorf("expected 1 to %d IPs or CIDRs", maxIPAllowlistEntries)
+	}
+	for _, entry := range entries {
+		if net.ParseIP(entry) == nil {
+			if _, _, err := net.ParseCIDR(entry); err != nil {
+				return nil, fmt.Errorf("%q is neither an IP nor a CIDR", entry)
+			}
+		}
+	}
+	return map[string]string{haproxyIPAllowlistAnnotation: strings.Join(entries, " ")}, nil
+}
+
+func stickyCookie(value string) (map[string]string, error) {
+	if !cookieNameRegexp.MatchString(value) {
+		return nil, errors.New("expected a cookie name")
+	}
+	return map[string]string{haproxyCookieNameAnnotation: value}, nil
+}
+
+func hsts(value string) (map[string]string, error) {
+	if !hstsRegexp.MatchString(value) {
+		return nil, errors.New("expected max-age=<seconds>[;includeSubDomains][;preload]")
+	}
+	return map[string]string{haproxyHSTSAnnotation: value}, nil
+}
+
+func balance(value string) (map[string]string, error) {
+	for _, b := range balanceValues {
+		if value == b {
+			return map[string]string{haproxyBalanceAnnotation: value}, nil
+		}
+	}
+	return nil, fmt.Errorf("expected one of %v", balanceValues)
+}
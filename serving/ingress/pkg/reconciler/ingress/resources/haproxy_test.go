@@ -0,0 +1,85 @@
+package resources
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHAProxyAnnotations(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        map[string]string
+		wantErr     bool
+	}{{
+		name:        "no options",
+		annotations: map[string]string{"foo": "bar"},
+		want:        map[string]string{},
+	}, {
+		name: "rate limits",
+		annotations: map[string]string{
+			RateLimitConcurrentTCPAnnotation: "10",
+			RateLimitHTTPAnnotation:          " 100 ",
+			RateLimitTCPAnnotation:           "20",
+		},
+		want: map[string]string{
+			haproxyRateLimitAnnotation:              "true",
+			haproxyRateLimitConcurrentTCPAnnotation: "10",
+			haproxyRateLimitHTTPAnnotation:          "100",
+			haproxyRateLimitTCPAnnotation:           "20",
+		},
+	}, {
+		name:        "invalid rate limit",
+		annotations: map[string]string{RateLimitHTTPAnnotation: "0"},
+		wantErr:     true,
+	}, {
+		name:        "ip allowlist",
+		annotations: map[string]string{IPAllowlistAnnotation: "192.168.1.1, 10.0.0.0/8 2001:db8::/32"},
+		want:        map[string]string{haproxyIPAllowlistAnnotation: "192.168.1.1 10.0.0.0/8 2001:db8::/32"},
+	}, {
+		name:        "invalid ip allowlist",
+		annotations: map[string]string{IPAllowlistAnnotation: "192.168.1.1,example.com"},
+		wantErr:     true,
+	}, {
+		name:        "sticky cookie",
+		annotations: map[string]string{StickyCookieAnnotation: "session"},
+		want:        map[string]string{haproxyCookieNameAnnotation: "session"},
+	}, {
+		name:        "invalid sticky cookie",
+		annotations: map[string]string{StickyCookieAnnotation: "my session"},
+		wantErr:     true,
+	}, {
+		name:        "hsts",
+		annotations: map[string]string{HSTSAnnotation: "max-age=31536000;includeSubDomains;preload"},
+		want:        map[string]string{haproxyHSTSAnnotation: "max-age=31536000;includeSubDomains;preload"},
+	}, {
+		name:        "invalid hsts",
+		annotations: map[string]string{HSTSAnnotation: "31536000"},
+		wantErr:     true,
+	}, {
+		name:        "balance",
+		annotations: map[string]string{BalanceAnnotation: "leastconn"},
+		want:        map[string]string{haproxyBalanceAnnotation: "leastconn"},
+	}, {
+		name:        "invalid balance",
+		annotations: map[string]string{BalanceAnnotation: "first"},
+		wantErr:     true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := haproxyAnnotations(test.annotations)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("haproxyAnnotations() = %v, wantErr %v", err, test.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidRouteOption) {
+				t.Errorf("haproxyAnnotations() = %v, want %v", err, ErrInvalidRouteOption)
+			}
+			if !cmp.Equal(got, test.want) {
+				t.Errorf("haproxyAnnotations() = %s", cmp.Diff(test.want, got))
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	ingressconfig "github.com/openshift-knative/serverless-operator/serving/ingress/pkg/reconciler/ingress/config"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	networkingv1alpha1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const (
+	// HTTPListenerName and HTTPSListenerName are the names of the listeners of the Gateway
+	// that the HTTPRoutes of the ingresses redirecting HTTP to HTTPS attach to.
+	HTTPListenerName  = "http"
+	HTTPSListenerName = "https"
+
+	redirectSuffix = "-redirect"
+)
+
+// ErrPassthroughUnsupported indicates that the ingress requires TLS passthrough to the
+// load balancer, which HTTPRoutes cannot express.
+var ErrPassthroughUnsupported = errors.New("TLS passthrough is not supported by Gateway API HTTPRoutes")
+
+// MakeHTTPRoutes creates Gateway API HTTPRoutes attached to the given Gateway from a Knative Ingress.
+func MakeHTTPRoutes(ctx context.Context, ci *networkingv1alpha1.Ingress, gateway types.NamespacedName) ([]*gatewayv1.HTTPRoute, error) {
+	routes := []*gatewayv1.HTTPRoute{}
+	network := ingressconfig.FromContextOrDefaults(ctx).Network
+
+	if _, ok := ci.GetAnnotations()[DisableRouteAnnotation]; ok {
+		return routes, nil
+	}
+
+	for _, rule := range ci.Spec.Rules {
+		// Skip route creation for cluster-local visibility.
+		if rule.Visibility == networkingv1alpha1.IngressVisibilityClusterLocal {
+			continue
+		}
+		for _, host := range rule.Hosts {
+			// Ignore domains like myksvc.myproject.svc.cluster.local
+			parts := strings.Split(host, ".")
+			if len(parts) == 2 || (len(parts) > 2 && parts[2] != "svc") {
+				hostRoutes, err := makeHTTPRoutes(ci, host, rule, gateway, network)
+				if err != nil {
+					return nil, err
+				}
+				routes = append(routes, hostRoutes...)
+			}
+		}
+	}
+
+	return routes, nil
+}
+
+func makeHTTPRoutes(ci *networkingv1alpha1.Ingress, host string, rule networkingv1alpha1.IngressRule, gateway types.NamespacedName, network *ingressconfig.Network) ([]*gatewayv1.HTTPRoute, error) {
+	if _, ok := ci.GetAnnotations()[EnablePassthroughRouteAnnotation]; ok ||
+		len(ci.GetIngressTLSForVisibility(networkingv1alpha1.IngressVisibilityExternalIP)) > 0 ||
+		isTLSDestination(rule) {
+		return nil, ErrPassthroughUnsupported
+	}
+
+	timeout, err := getHAProxyTimeout(ci, network)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceName, namespace, err := publicLoadBalancer(ci)
+	if err != nil {
+		return nil, err
+	}
+
+	name := routeName(string(ci.GetUID()), host)
+	route := makeHTTPRoute(ci, name, namespace, host, gateway, "", gatewayv1.HTTPRouteRule{
+		Matches: []gatewayv1.HTTPRouteMatch{pathPrefixMatch()},
+		BackendRefs: []gatewayv1.HTTPBackendRef{{
+			BackendRef: gatewayv1.BackendRef{
+				BackendObjectReference: gatewayv1.BackendObjectReference{
+					Group: ptr.To(gatewayv1.Group("")),
+					Kind:  ptr.To(gatewayv1.Kind("Service")),
+					Name:  gatewayv1.ObjectName(serviceName),
+					Port:  ptr.To(gatewayv1.PortNumber(80)),
+				},
+				Weight: ptr.To[int32](100),
+			},
+		}},
+		Timeouts: &gatewayv1.HTTPRouteTimeouts{
+			Request: ptr.To(gatewayv1.Duration(timeout)),
+		},
+	})
+	if ci.Spec.HTTPOption != networkingv1alpha1.HTTPOptionRedirected {
+		return []*gatewayv1.HTTPRoute{route}, nil
+	}
+
+	// Serve the HTTPS listener only and redirect the requests of the HTTP listener.
+	route.Spec.ParentRefs[0].SectionName = ptr.To(gatewayv1.SectionName(HTTPSListenerName))
+	redirect := makeHTTPRoute(ci, name+redirectSuffix, namespace, host, gateway, HTTPListenerName, gatewayv1.HTTPRouteRule{
+		Matches: []gatewayv1.HTTPRouteMatch{pathPrefixMatch()},
+		Filters: []gatewayv1.HTTPRouteFilter{{
+			Type: gatewayv1.HTTPRouteFilterRequestRedirect,
+			RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{
+				Scheme:     ptr.To("https"),
+				StatusCode: ptr.To(301),
+			},
+		}},
+	})
+	return []*gatewayv1.HTTPRoute{route, redirect}, nil
+}
+
+func makeHTTPRoute(ci *networkingv1alpha1.Ingress, name, namespace, host string, gateway types.NamespacedName, listener string, rule gatewayv1.HTTPRouteRule) *gatewayv1.HTTPRoute {
+	parent := gatewayv1.ParentReference{
+		Group:     ptr.To(gatewayv1.Group(gatewayv1.GroupName)),
+		Kind:      ptr.To(gatewayv1.Kind("Gateway")),
+		Namespace: ptr.To(gatewayv1.Namespace(gateway.Namespace)),
+		Name:      gatewayv1.ObjectName(gateway.Name),
+	}
+	if listener != "" {
+		parent.SectionName = ptr.To(gatewayv1.SectionName(listener))
+	}
+
+	return &gatewayv1.HTTPRoute{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: gatewayv1.GroupVersion.String(),
+			Kind:       "HTTPRoute",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Labels:      ownerLabels(ci),
+			Annotations: cleanArgoCDAnnotations(ci.GetAnnotations()),
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: []gatewayv1.ParentReference{parent},
+			},
+			Hostnames: []gatewayv1.Hostname{gatewayv1.Hostname(host)},
+			Rules:     []gatewayv1.HTTPRouteRule{rule},
+		},
+	}
+}
+
+// pathPrefixMatch matches all the requests. It is the default match of a rule, set
+// explicitly to compare the generated HTTPRoutes with the defaulted ones.
+func pathPrefixMatch() gatewayv1.HTTPRouteMatch {
+	return gatewayv1.HTTPRouteMatch{
+		Path: &gatewayv1.HTTPPathMatch{
+			Type:  ptr.To(gatewayv1.PathMatchPathPrefix),
+			Value: ptr.To("/"),
+		},
+	}
+}
@@ -0,0 +1,131 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"knative.dev/networking/pkg/apis/networking"
+	networkingv1alpha1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
+	"knative.dev/serving/pkg/apis/serving"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestMakeHTTPRoutes(t *testing.T) {
+	gateway := types.NamespacedName{Namespace: "openshift-ingress", Name: "knative"}
+	parent := gatewayv1.ParentReference{
+		Group:     ptr.To(gatewayv1.Group(gatewayv1.GroupName)),
+		Kind:      ptr.To(gatewayv1.Kind("Gateway")),
+		Namespace: ptr.To(gatewayv1.Namespace("openshift-ingress")),
+		Name:      "knative",
+	}
+	match := gatewayv1.HTTPRouteMatch{
+		Path: &gatewayv1.HTTPPathMatch{
+			Type:  ptr.To(gatewayv1.PathMatchPathPrefix),
+			Value: ptr.To("/"),
+		},
+	}
+	httpRoute := func(name string, parent gatewayv1.ParentReference, rule gatewayv1.HTTPRouteRule) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			TypeMeta: metav1.TypeMeta{APIVersion: "gateway.networking.k8s.io/v1", Kind: "HTTPRoute"},
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					networking.IngressLabelKey:        "ingress",
+					serving.RouteLabelKey:             "route1",
+					serving.RouteNamespaceLabelKey:    "default",
+					OpenShiftIngressLabelKey:          "ingress",
+					OpenShiftIngressNamespaceLabelKey: "default",
+				},
+				Annotations: map[string]string{},
+				Namespace:   lbNamespace,
+				Name:        name,
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{parent}},
+				Hostnames:       []gatewayv1.Hostname{externalDomain},
+				Rules:           []gatewayv1.HTTPRouteRule{rule},
+			},
+		}
+	}
+	backendRule := gatewayv1.HTTPRouteRule{
+		Matches: []gatewayv1.HTTPRouteMatch{match},
+		BackendRefs: []gatewayv1.HTTPBackendRef{{
+			BackendRef: gatewayv1.BackendRef{
+				BackendObjectReference: gatewayv1.BackendObjectReference{
+					Group: ptr.To(gatewayv1.Group("")),
+					Kind:  ptr.To(gatewayv1.Kind("Service")),
+					Name:  lbService,
+					Port:  ptr.To(gatewayv1.PortNumber(80)),
+				},
+				Weight: ptr.To[int32](100),
+			},
+		}},
+		Timeouts: &gatewayv1.HTTPRouteTimeouts{Request: ptr.To(gatewayv1.Duration(DefaultTimeout))},
+	}
+	httpsParent := *parent.DeepCopy()
+	httpsParent.SectionName = ptr.To(gatewayv1.SectionName(HTTPSListenerName))
+	httpParent := *parent.DeepCopy()
+	httpParent.SectionName = ptr.To(gatewayv1.SectionName(HTTPListenerName))
+
+	tests := []struct {
+		name    string
+		ingress *networkingv1alpha1.Ingress
+		want    []*gatewayv1.HTTPRoute
+		wantErr error
+	}{{
+		name: "skip internal host name and cluster-local rules",
+		ingress: ingress(withRules(
+			rule(withHosts([]string{localDomain})),
+			rule(withHosts([]string{externalDomain2}), withLocalVisibilityRule),
+		)),
+		want: []*gatewayv1.HTTPRoute{},
+	}, {
+		name:    "valid",
+		ingress: ingress(withArgoCDMetadata, withRules(rule(withHosts([]string{localDomain, externalDomain})))),
+		want:    []*gatewayv1.HTTPRoute{httpRoute(routeName0, parent, backendRule)},
+	}, {
+		name:    "redirected",
+		ingress: ingress(withRedirect(), withRules(rule(withHosts([]string{externalDomain})))),
+		want: []*gatewayv1.HTTPRoute{
+			httpRoute(routeName0, httpsParent, backendRule),
+			httpRoute(routeName0+"-redirect", httpParent, gatewayv1.HTTPRouteRule{
+				Matches: []gatewayv1.HTTPRouteMatch{match},
+				Filters: []gatewayv1.HTTPRouteFilter{{
+					Type: gatewayv1.HTTPRouteFilterRequestRedirect,
+					RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{
+						Scheme:     ptr.To("https"),
+						StatusCode: ptr.To(301),
+					},
+				}},
+			}),
+		},
+	}, {
+		name:    "disabled",
+		ingress: ingress(withDisabledAnnotation, withRules(rule(withHosts([]string{externalDomain})))),
+		want:    []*gatewayv1.HTTPRoute{},
+	}, {
+		name:    "passthrough",
+		ingress: ingress(withRules(rule(withHosts([]string{externalDomain}), withHTTPSBackendService()))),
+		wantErr: ErrPassthroughUnsupported,
+	}, {
+		name:    "no valid load balancer",
+		ingress: ingress(withLBInternalDomain(""), withRules(rule(withHosts([]string{externalDomain})))),
+		wantErr: ErrNoValidLoadbalancerDomain,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := MakeHTTPRoutes(context.Background(), test.ingress, gateway)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("MakeHTTPRoutes() = %v, want %v", err, test.wantErr)
+			}
+			if !cmp.Equal(got, test.want) {
+				t.Errorf("MakeHTTPRoutes() = %s", cmp.Diff(test.want, got))
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package resources
 
 import (
+	"context"
 	"crypto/sha256"
 	"errors"
 	"fmt"
@@ -9,8 +10,11 @@ import (
 	"strings"
 
 	socommon "github.com/openshift-knative/serverless-operator/pkg/common"
+	ingressconfig "github.com/openshift-knative/serverless-operator/serving/ingress/pkg/reconciler/ingress/config"
 	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"knative.dev/networking/pkg/apis/networking"
 	networkingv1alpha1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
@@ -36,6 +40,24 @@ const (
 	HAProxyTimeoutEnv = "ROUTE_HAPROXY_TIMEOUT"
 )
 
+const (
+	// TLSSecretAnnotation names the Secret, in the namespace of the ingress, holding the
+	// certificate, key and CA certificate the Route terminates TLS with. The Secret must be
+	// labeled with TLSSecretLabelKey.
+	TLSSecretAnnotation = socommon.ServingDownstreamDomain + "/tlsSecret"
+	// TLSTerminationAnnotation selects the termination of the Routes using the certificate of
+	// TLSSecretAnnotation, either "edge" (the default) or "reencrypt".
+	TLSTerminationAnnotation = socommon.ServingDownstreamDomain + "/tlsTermination"
+	TLSSecretLabelKey        = socommon.ServingDownstreamDomain + "/routeCertificate"
+	// RouteLabelsAnnotation sets the labels, as "key=value,...", of the Routes of a Knative Service,
+	// to expose it through a router shard selecting Routes by label. It replaces the default labels
+	// of config-network, an empty value drops them.
+	RouteLabelsAnnotation = socommon.ServingDownstreamDomain + "/routeLabels"
+	// DestinationCACertKey is the optional key of the Secret holding the CA certificate the
+	// router validates the ingress gateway's certificate with, when re-encrypting.
+	DestinationCACertKey = "destination-ca.crt"
+)
+
 // DefaultTimeout is set by DefaultMaxRevisionTimeoutSeconds. So, the OpenShift Route's timeout
 // should not have any effect on Knative services by default.
 var DefaultTimeout = fmt.Sprintf("%vs", config.DefaultMaxRevisionTimeoutSeconds)
@@ -44,20 +66,47 @@ var DefaultTimeout = fmt.Sprintf("%vs", config.DefaultMaxRevisionTimeoutSeconds)
 // said field does not contain a value we can work with.
 var ErrNoValidLoadbalancerDomain = errors.New("unable to find Ingress LoadBalancer with DomainInternal set")
 
-// MakeRoutes creates OpenShift Routes from a Knative Ingress
-func MakeRoutes(ci *networkingv1alpha1.Ingress) ([]*routev1.Route, error) {
+// ErrInvalidTLSTermination indicates that the TLSTerminationAnnotation has an unsupported value.
+var ErrInvalidTLSTermination = errors.New("invalid TLS termination")
+
+// ErrInvalidRouteLabels indicates that the RouteLabelsAnnotation has an invalid value.
+var ErrInvalidRouteLabels = errors.New("invalid route labels")
+
+// MakeRoutes creates OpenShift Routes from a Knative Ingress. The Routes terminate TLS with the
+// certificate of tlsSecret, if not nil, and the router's default certificate otherwise.
+func MakeRoutes(ctx context.Context, ci *networkingv1alpha1.Ingress, tlsSecret *corev1.Secret) ([]*routev1.Route, error) {
 	routes := []*routev1.Route{}
+	network := ingressconfig.FromContextOrDefaults(ctx).Network
+	shardLabels, err := ShardLabels(ctx, ci)
+	if err != nil {
+		return nil, err
+	}
+
+	exposeClusterLocal, err := exposesClusterLocal(ci, network)
+	if err != nil {
+		return nil, err
+	}
 
 	for _, rule := range ci.Spec.Rules {
-		// Skip route creation for cluster-local visibility.
+		// Skip route creation for cluster-local visibility, unless exposed on the internal router shard.
 		if rule.Visibility == networkingv1alpha1.IngressVisibilityClusterLocal {
+			if !exposeClusterLocal {
+				continue
+			}
+			route, err := makeClusterLocalRoute(ci, rule, network)
+			if err != nil {
+				return nil, err
+			}
+			if route != nil {
+				routes = append(routes, route)
+			}
 			continue
 		}
 		for _, host := range rule.Hosts {
 			// Ignore domains like myksvc.myproject.svc.cluster.local
 			parts := strings.Split(host, ".")
 			if len(parts) == 2 || (len(parts) > 2 && parts[2] != "svc") {
-				route, err := makeRoute(ci, host, rule)
+				route, err := makeRoute(ci, host, rule, network, shardLabels, tlsSecret)
 				if err != nil {
 					return nil, err
 				}
@@ -72,7 +121,7 @@ func MakeRoutes(ci *networkingv1alpha1.Ingress) ([]*routev1.Route, error) {
 	return routes, nil
 }
 
-func makeRoute(ci *networkingv1alpha1.Ingress, host string, rule networkingv1alpha1.IngressRule) (*routev1.Route, error) {
+func makeRoute(ci *networkingv1alpha1.Ingress, host string, rule networkingv1alpha1.IngressRule, network *ingressconfig.Network, shardLabels map[string]string, tlsSecret *corev1.Secret) (*routev1.Route, error) {
 	// Take over annotations from ingress.
 	annotations := ci.GetAnnotations()
 	if annotations == nil {
@@ -89,42 +138,17 @@ func makeRoute(ci *networkingv1alpha1.Ingress, host string, rule networkingv1alp
 		return nil, nil
 	}
 
-	annotations = cleanArgoCDAnnotations(annotations)
-
-	// Set timeout for OpenShift Route
-	timeout, err := getHAProxyTimeout(ci)
+	annotations, err := routeAnnotations(ci, network, annotations)
 	if err != nil {
 		return nil, err
 	}
-	annotations[TimeoutAnnotation] = timeout
 
-	labels := kmap.Union(ci.Labels, map[string]string{
-		networking.IngressLabelKey:        ci.GetName(),
-		OpenShiftIngressLabelKey:          ci.GetName(),
-		OpenShiftIngressNamespaceLabelKey: ci.GetNamespace(),
-	})
-
-	labels = cleanArgoCDLabels(labels)
+	labels := kmap.Union(shardLabels, ownerLabels(ci))
 
 	name := routeName(string(ci.GetUID()), host)
-	serviceName := ""
-	namespace := ""
-	if ci.Status.PublicLoadBalancer != nil {
-		for _, lbIngress := range ci.Status.PublicLoadBalancer.Ingress {
-			if lbIngress.DomainInternal != "" {
-				// DomainInternal should look something like:
-				// kourier.knative-serving-ingress.svc.cluster.local
-				parts := strings.Split(lbIngress.DomainInternal, ".")
-				if len(parts) > 2 && parts[2] == "svc" {
-					serviceName = parts[0]
-					namespace = parts[1]
-				}
-			}
-		}
-	}
-
-	if serviceName == "" || namespace == "" {
-		return nil, ErrNoValidLoadbalancerDomain
+	serviceName, namespace, err := publicLoadBalancer(ci)
+	if err != nil {
+		return nil, err
 	}
 
 	terminationPolicy := routev1.InsecureEdgeTerminationPolicyAllow
@@ -160,22 +184,132 @@ func makeRoute(ci *networkingv1alpha1.Ingress, host string, rule networkingv1alp
 	// Target the HTTPS port and configure passthrough when:
 	// * the passthrough annotation is set.
 	// * the ingress.spec.tls is set for an external domain (e.g. DomainMapping with BYP cert.)
-	// * the destination service uses a https port.
-	if _, ok := annotations[EnablePassthroughRouteAnnotation]; ok ||
-		len(ci.GetIngressTLSForVisibility(networkingv1alpha1.IngressVisibilityExternalIP)) > 0 ||
-		isTLSDestination(rule) {
+	// * the destination service uses a https port, unless the Route terminates TLS with its own certificate
+	//   or re-encrypts the traffic.
+	_, passthrough := annotations[EnablePassthroughRouteAnnotation]
+	tlsDestination := tlsSecret == nil && (network.InternalTLS || isTLSDestination(rule))
+	if passthrough || len(ci.GetIngressTLSForVisibility(networkingv1alpha1.IngressVisibilityExternalIP)) > 0 ||
+		(tlsDestination && network.RouteTLSTermination != routev1.TLSTerminationReencrypt) {
 
 		route.Spec.Port.TargetPort = intstr.FromString(HTTPSPort)
 		route.Spec.TLS.Termination = routev1.TLSTerminationPassthrough
 		route.Spec.TLS.InsecureEdgeTerminationPolicy = routev1.InsecureEdgeTerminationPolicyRedirect
+	} else if tlsDestination {
+		// Terminate the public certificate at the router and re-encrypt the traffic to the ingress
+		// gateway. The router trusts the service CA without a destination CA certificate.
+		route.Spec.Port.TargetPort = intstr.FromString(HTTPSPort)
+		route.Spec.TLS.Termination = routev1.TLSTerminationReencrypt
+	} else if tlsSecret != nil {
+		if err := setCertificate(route, annotations[TLSTerminationAnnotation], tlsSecret); err != nil {
+			return nil, err
+		}
 	}
 
 	return route, nil
 }
 
-// isTLSDestination determines whether the target service is using https or not.
-// In general, we can determine it by the value system-internal-tls/cluster-local-domain-tls in config-network, however the serverless ingress does not
-// watch the ConfigMap. Therefore, we determine it by ServiceHTTPSPort(443) port for the backend in Kingress.
+// routeAnnotations returns the annotations of the Routes of the given ingress, taken over from
+// its annotations with the timeout and the typed HAProxy options.
+func routeAnnotations(ci *networkingv1alpha1.Ingress, network *ingressconfig.Network, annotations map[string]string) (map[string]string, error) {
+	annotations = cleanArgoCDAnnotations(annotations)
+
+	// Set timeout for OpenShift Route
+	timeout, err := getHAProxyTimeout(ci, network)
+	if err != nil {
+		return nil, err
+	}
+	annotations[TimeoutAnnotation] = timeout
+
+	// Translate the typed HAProxy options, overriding the router annotations set directly.
+	options, err := haproxyAnnotations(annotations)
+	if err != nil {
+		return nil, err
+	}
+	return kmap.Union(annotations, options), nil
+}
+
+// setCertificate configures the Route to terminate TLS with the certificate of the given Secret.
+func setCertificate(route *routev1.Route, termination string, secret *corev1.Secret) error {
+	switch routev1.TLSTerminationType(termination) {
+	case "", routev1.TLSTerminationEdge:
+	case routev1.TLSTerminationReencrypt:
+		route.Spec.Port.TargetPort = intstr.FromString(HTTPSPort)
+		route.Spec.TLS.Termination = routev1.TLSTerminationReencrypt
+		route.Spec.TLS.DestinationCACertificate = string(secret.Data[DestinationCACertKey])
+	default:
+		return fmt.Errorf("%w: %s value %q, expected %q or %q", ErrInvalidTLSTermination, TLSTerminationAnnotation,
+			termination, routev1.TLSTerminationEdge, routev1.TLSTerminationReencrypt)
+	}
+
+	route.Spec.TLS.Certificate = string(secret.Data[corev1.TLSCertKey])
+	route.Spec.TLS.Key = string(secret.Data[corev1.TLSPrivateKeyKey])
+	route.Spec.TLS.CACertificate = string(secret.Data[corev1.ServiceAccountRootCAKey])
+	return nil
+}
+
+// ShardLabels returns the labels of the Routes of the given ingress selecting the router shard
+// exposing them.
+func ShardLabels(ctx context.Context, ci *networkingv1alpha1.Ingress) (map[string]string, error) {
+	value, ok := ci.GetAnnotations()[RouteLabelsAnnotation]
+	if !ok {
+		return ingressconfig.FromContextOrDefaults(ctx).Network.RouteLabels, nil
+	}
+	shardLabels, err := labels.ConvertSelectorToLabelsMap(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s value %q: %w", ErrInvalidRouteLabels, RouteLabelsAnnotation, value, err)
+	}
+	return shardLabels, nil
+}
+
+// ownerLabels returns the labels of the resources generated for the given ingress, which
+// identify the ingress owning them.
+func ownerLabels(ci *networkingv1alpha1.Ingress) map[string]string {
+	labels := kmap.Union(ci.Labels, map[string]string{
+		networking.IngressLabelKey:        ci.GetName(),
+		OpenShiftIngressLabelKey:          ci.GetName(),
+		OpenShiftIngressNamespaceLabelKey: ci.GetNamespace(),
+	})
+	return cleanArgoCDLabels(labels)
+}
+
+// publicLoadBalancer returns the name and namespace of the service of the public load
+// balancer of the given ingress.
+func publicLoadBalancer(ci *networkingv1alpha1.Ingress) (string, string, error) {
+	return loadBalancerService(ci.Status.PublicLoadBalancer)
+}
+
+// privateLoadBalancer returns the name and namespace of the service of the private load
+// balancer of the given ingress.
+func privateLoadBalancer(ci *networkingv1alpha1.Ingress) (string, string, error) {
+	return loadBalancerService(ci.Status.PrivateLoadBalancer)
+}
+
+func loadBalancerService(lb *networkingv1alpha1.LoadBalancerStatus) (string, string, error) {
+	serviceName := ""
+	namespace := ""
+	if lb != nil {
+		for _, lbIngress := range lb.Ingress {
+			if lbIngress.DomainInternal != "" {
+				// DomainInternal should look something like:
+				// kourier.knative-serving-ingress.svc.cluster.local
+				parts := strings.Split(lbIngress.DomainInternal, ".")
+				if len(parts) > 2 && parts[2] == "svc" {
+					serviceName = parts[0]
+					namespace = parts[1]
+				}
+			}
+		}
+	}
+
+	if serviceName == "" || namespace == "" {
+		return "", "", ErrNoValidLoadbalancerDomain
+	}
+	return serviceName, namespace, nil
+}
+
+// isTLSDestination determines whether the target service is using https or not, by the ServiceHTTPSPort(443)
+// port for the backend in Kingress. It complements system-internal-tls of config-network, which isn't known
+// before the ConfigMap is observed.
 func isTLSDestination(rule networkingv1alpha1.IngressRule) bool {
 	if rule.HTTP == nil {
 		return false
@@ -198,7 +332,9 @@ func hashHost(host string) string {
 	return fmt.Sprintf("%x", sha256.Sum256([]byte(host)))[0:6]
 }
 
-func getHAProxyTimeout(ci *networkingv1alpha1.Ingress) (string, error) {
+// getHAProxyTimeout returns the timeout of the Routes of the given ingress, taken from its
+// setRouteTimeout annotation, config-network or the environment, in this order.
+func getHAProxyTimeout(ci *networkingv1alpha1.Ingress, network *ingressconfig.Network) (string, error) {
 	var timeout string
 	if ci != nil && ci.Annotations[SetRouteTimeoutAnnotation] != "" {
 		timeout = ci.Annotations[SetRouteTimeoutAnnotation]
@@ -207,6 +343,9 @@ func getHAProxyTimeout(ci *networkingv1alpha1.Ingress) (string, error) {
 		}
 		return fmt.Sprintf("%vs", timeout), nil
 	}
+	if network != nil && network.RouteTimeout != "" {
+		return network.RouteTimeout, nil
+	}
 	timeout = os.Getenv(HAProxyTimeoutEnv)
 	if timeout != "" {
 		if _, err := strconv.ParseInt(timeout, 10, 64); err != nil {
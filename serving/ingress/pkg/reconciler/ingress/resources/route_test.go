@@ -1,18 +1,22 @@
 package resources
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"knative.dev/networking/pkg/apis/networking"
 	networkingv1alpha1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
 	"knative.dev/pkg/ptr"
 	"knative.dev/serving/pkg/apis/serving"
+
+	ingressconfig "github.com/openshift-knative/serverless-operator/serving/ingress/pkg/reconciler/ingress/config"
 )
 
 const (
@@ -31,11 +35,13 @@ const (
 
 func TestMakeRoute(t *testing.T) {
 	tests := []struct {
-		name    string
-		ingress *networkingv1alpha1.Ingress
-		want    []*routev1.Route
-		wantErr error
-		timeout string
+		name      string
+		ingress   *networkingv1alpha1.Ingress
+		want      []*routev1.Route
+		wantErr   error
+		timeout   string
+		tlsSecret *corev1.Secret
+		network   *ingressconfig.Network
 	}{
 		{
 			name:    "no rules",
@@ -124,6 +130,45 @@ func TestMakeRoute(t *testing.T) {
 				},
 			}},
 			timeout: "900",
+		}, {
+			name: "valid, default timeout modified by config-network over env var",
+			ingress: ingress(withRules(
+				rule(withHosts([]string{localDomain, externalDomain}))),
+			),
+			want: []*routev1.Route{{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						networking.IngressLabelKey:        "ingress",
+						serving.RouteLabelKey:             "route1",
+						serving.RouteNamespaceLabelKey:    "default",
+						OpenShiftIngressLabelKey:          "ingress",
+						OpenShiftIngressNamespaceLabelKey: "default",
+					},
+					Annotations: map[string]string{
+						TimeoutAnnotation: "3600s",
+					},
+					Namespace: lbNamespace,
+					Name:      routeName0,
+				},
+				Spec: routev1.RouteSpec{
+					Host: externalDomain,
+					To: routev1.RouteTargetReference{
+						Kind:   "Service",
+						Name:   lbService,
+						Weight: ptr.Int32(100),
+					},
+					Port: &routev1.RoutePort{
+						TargetPort: intstr.FromString(HTTPPort),
+					},
+					TLS: &routev1.TLSConfig{
+						Termination:                   routev1.TLSTerminationEdge,
+						InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyAllow,
+					},
+					WildcardPolicy: routev1.WildcardPolicyNone,
+				},
+			}},
+			timeout: "900",
+			network: &ingressconfig.Network{RouteTimeout: "3600s"},
 		},
 		{
 			name: "valid but disabled",
@@ -410,6 +455,137 @@ func TestMakeRoute(t *testing.T) {
 				},
 			}},
 		},
+		{
+			name: "edge termination with a certificate",
+			ingress: ingress(withTLSSecret(""),
+				withRules(rule(withHosts([]string{localDomain, externalDomain}))),
+			),
+			tlsSecret: certificateSecret(),
+			want: []*routev1.Route{
+				certificateRoute(routev1.TLSTerminationEdge, HTTPPort, "", map[string]string{
+					TLSSecretAnnotation: "cert",
+				}),
+			},
+		},
+		{
+			name: "edge termination with a certificate and system-internal-tls",
+			ingress: ingress(withTLSSecret(routev1.TLSTerminationEdge),
+				withRules(rule(withHosts([]string{localDomain, externalDomain}), withHTTPSBackendService())),
+			),
+			tlsSecret: certificateSecret(),
+			want: []*routev1.Route{
+				certificateRoute(routev1.TLSTerminationEdge, HTTPPort, "", map[string]string{
+					TLSSecretAnnotation:      "cert",
+					TLSTerminationAnnotation: "edge",
+				}),
+			},
+		},
+		{
+			name: "reencrypt termination with a certificate",
+			ingress: ingress(withTLSSecret(routev1.TLSTerminationReencrypt),
+				withRules(rule(withHosts([]string{localDomain, externalDomain}))),
+			),
+			tlsSecret: certificateSecret(),
+			want: []*routev1.Route{
+				certificateRoute(routev1.TLSTerminationReencrypt, HTTPSPort, "destination-ca", map[string]string{
+					TLSSecretAnnotation:      "cert",
+					TLSTerminationAnnotation: "reencrypt",
+				}),
+			},
+		},
+		{
+			name: "system-internal-tls is enabled in config-network",
+			ingress: ingress(
+				withRules(rule(withHosts([]string{localDomain, externalDomain}))),
+			),
+			network: &ingressconfig.Network{
+				InternalTLS:         true,
+				RouteTLSTermination: routev1.TLSTerminationPassthrough,
+			},
+			want: []*routev1.Route{
+				tlsRoute(routev1.TLSTerminationPassthrough, routev1.InsecureEdgeTerminationPolicyRedirect),
+			},
+		},
+		{
+			name: "system-internal-tls is enabled with reencrypt termination",
+			ingress: ingress(
+				withRules(rule(withHosts([]string{localDomain, externalDomain}), withHTTPSBackendService())),
+			),
+			network: &ingressconfig.Network{
+				InternalTLS:         true,
+				RouteTLSTermination: routev1.TLSTerminationReencrypt,
+			},
+			want: []*routev1.Route{
+				tlsRoute(routev1.TLSTerminationReencrypt, routev1.InsecureEdgeTerminationPolicyAllow),
+			},
+		},
+		{
+			name: "reencrypt termination of an https backend with a redirect",
+			ingress: ingress(withRedirect(),
+				withRules(rule(withHosts([]string{localDomain, externalDomain}), withHTTPSBackendService())),
+			),
+			network: &ingressconfig.Network{
+				RouteTLSTermination: routev1.TLSTerminationReencrypt,
+			},
+			want: []*routev1.Route{
+				tlsRoute(routev1.TLSTerminationReencrypt, routev1.InsecureEdgeTerminationPolicyRedirect),
+			},
+		},
+		{
+			name: "default shard labels",
+			ingress: ingress(
+				withRules(rule(withHosts([]string{localDomain, externalDomain}))),
+			),
+			network: &ingressconfig.Network{
+				RouteTLSTermination: routev1.TLSTerminationPassthrough,
+				RouteLabels:         map[string]string{"router": "internal"},
+			},
+			want: []*routev1.Route{
+				shardRoute(map[string]string{"router": "internal"}, map[string]string{}),
+			},
+		},
+		{
+			name: "shard labels of the service",
+			ingress: ingress(withRouteLabels("router=public,tenant=a"),
+				withRules(rule(withHosts([]string{localDomain, externalDomain}))),
+			),
+			network: &ingressconfig.Network{
+				RouteTLSTermination: routev1.TLSTerminationPassthrough,
+				RouteLabels:         map[string]string{"router": "internal"},
+			},
+			want: []*routev1.Route{
+				shardRoute(map[string]string{"router": "public", "tenant": "a"},
+					map[string]string{RouteLabelsAnnotation: "router=public,tenant=a"}),
+			},
+		},
+		{
+			name: "service opting out of the default shard",
+			ingress: ingress(withRouteLabels(""),
+				withRules(rule(withHosts([]string{localDomain, externalDomain}))),
+			),
+			network: &ingressconfig.Network{
+				RouteTLSTermination: routev1.TLSTerminationPassthrough,
+				RouteLabels:         map[string]string{"router": "internal"},
+			},
+			want: []*routev1.Route{
+				shardRoute(map[string]string{}, map[string]string{RouteLabelsAnnotation: ""}),
+			},
+		},
+		{
+			name: "invalid shard labels",
+			ingress: ingress(withRouteLabels("router"),
+				withRules(rule(withHosts([]string{localDomain, externalDomain}))),
+			),
+			wantErr: ErrInvalidRouteLabels,
+		},
+		{
+			name: "invalid termination with a certificate",
+			ingress: ingress(withTLSSecret(routev1.TLSTerminationPassthrough),
+				withRules(rule(withHosts([]string{localDomain, externalDomain}))),
+			),
+			tlsSecret: certificateSecret(),
+			wantErr:   ErrInvalidTLSTermination,
+		},
 	}
 
 	for _, test := range tests {
@@ -420,7 +596,11 @@ func TestMakeRoute(t *testing.T) {
 					t.Setenv(HAProxyTimeoutEnv, "")
 				}()
 			}
-			routes, err := MakeRoutes(test.ingress)
+			ctx := context.Background()
+			if test.network != nil {
+				ctx = ingressconfig.ToContext(ctx, &ingressconfig.Config{Network: test.network})
+			}
+			routes, err := MakeRoutes(ctx, test.ingress, test.tlsSecret)
 			if test.want != nil && !cmp.Equal(routes, test.want) {
 				t.Errorf("got = %v, want: %v, diff: %s", routes, test.want, cmp.Diff(routes, test.want))
 			}
@@ -518,6 +698,157 @@ func withLBInternalDomain(domain string) ingressOption {
 	}
 }
 
+func withTLSSecret(termination routev1.TLSTerminationType) ingressOption {
+	return func(ing *networkingv1alpha1.Ingress) {
+		annos := ing.GetAnnotations()
+		if annos == nil {
+			annos = map[string]string{}
+		}
+		annos[TLSSecretAnnotation] = "cert"
+		if termination != "" {
+			annos[TLSTerminationAnnotation] = string(termination)
+		}
+		ing.SetAnnotations(annos)
+	}
+}
+
+func certificateSecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "cert",
+			Labels: map[string]string{TLSSecretLabelKey: ""},
+		},
+		Data: map[string][]byte{
+			corev1.TLSCertKey:              []byte("cert"),
+			corev1.TLSPrivateKeyKey:        []byte("key"),
+			corev1.ServiceAccountRootCAKey: []byte("ca"),
+			DestinationCACertKey:           []byte("destination-ca"),
+		},
+	}
+}
+
+func certificateRoute(termination routev1.TLSTerminationType, port, destinationCA string, annotations map[string]string) *routev1.Route {
+	annotations[TimeoutAnnotation] = DefaultTimeout
+	return &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				networking.IngressLabelKey:        "ingress",
+				serving.RouteLabelKey:             "route1",
+				serving.RouteNamespaceLabelKey:    "default",
+				OpenShiftIngressLabelKey:          "ingress",
+				OpenShiftIngressNamespaceLabelKey: "default",
+			},
+			Annotations: annotations,
+			Namespace:   lbNamespace,
+			Name:        routeName0,
+		},
+		Spec: routev1.RouteSpec{
+			Host: externalDomain,
+			To: routev1.RouteTargetReference{
+				Kind:   "Service",
+				Name:   lbService,
+				Weight: ptr.Int32(100),
+			},
+			Port: &routev1.RoutePort{
+				TargetPort: intstr.FromString(port),
+			},
+			TLS: &routev1.TLSConfig{
+				Termination:                   termination,
+				InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyAllow,
+				Certificate:                   "cert",
+				Key:                           "key",
+				CACertificate:                 "ca",
+				DestinationCACertificate:      destinationCA,
+			},
+			WildcardPolicy: routev1.WildcardPolicyNone,
+		},
+	}
+}
+
+func tlsRoute(termination routev1.TLSTerminationType, policy routev1.InsecureEdgeTerminationPolicyType) *routev1.Route {
+	return &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				networking.IngressLabelKey:        "ingress",
+				serving.RouteLabelKey:             "route1",
+				serving.RouteNamespaceLabelKey:    "default",
+				OpenShiftIngressLabelKey:          "ingress",
+				OpenShiftIngressNamespaceLabelKey: "default",
+			},
+			Annotations: map[string]string{
+				TimeoutAnnotation: DefaultTimeout,
+			},
+			Namespace: lbNamespace,
+			Name:      routeName0,
+		},
+		Spec: routev1.RouteSpec{
+			Host: externalDomain,
+			To: routev1.RouteTargetReference{
+				Kind:   "Service",
+				Name:   lbService,
+				Weight: ptr.Int32(100),
+			},
+			Port: &routev1.RoutePort{
+				TargetPort: intstr.FromString(HTTPSPort),
+			},
+			TLS: &routev1.TLSConfig{
+				Termination:                   termination,
+				InsecureEdgeTerminationPolicy: policy,
+			},
+			WildcardPolicy: routev1.WildcardPolicyNone,
+		},
+	}
+}
+
+func withRouteLabels(value string) ingressOption {
+	return func(ing *networkingv1alpha1.Ingress) {
+		annos := ing.GetAnnotations()
+		if annos == nil {
+			annos = map[string]string{}
+		}
+		annos[RouteLabelsAnnotation] = value
+		ing.SetAnnotations(annos)
+	}
+}
+
+func shardRoute(shardLabels, annotations map[string]string) *routev1.Route {
+	labels := map[string]string{
+		networking.IngressLabelKey:        "ingress",
+		serving.RouteLabelKey:             "route1",
+		serving.RouteNamespaceLabelKey:    "default",
+		OpenShiftIngressLabelKey:          "ingress",
+		OpenShiftIngressNamespaceLabelKey: "default",
+	}
+	for k, v := range shardLabels {
+		labels[k] = v
+	}
+	annotations[TimeoutAnnotation] = DefaultTimeout
+	return &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:      labels,
+			Annotations: annotations,
+			Namespace:   lbNamespace,
+			Name:        routeName0,
+		},
+		Spec: routev1.RouteSpec{
+			Host: externalDomain,
+			To: routev1.RouteTargetReference{
+				Kind:   "Service",
+				Name:   lbService,
+				Weight: ptr.Int32(100),
+			},
+			Port: &routev1.RoutePort{
+				TargetPort: intstr.FromString(HTTPPort),
+			},
+			TLS: &routev1.TLSConfig{
+				Termination:                   routev1.TLSTerminationEdge,
+				InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyAllow,
+			},
+			WildcardPolicy: routev1.WildcardPolicyNone,
+		},
+	}
+}
+
 type ruleOption func(*networkingv1alpha1.IngressRule)
 
 func withLocalVisibilityRule(rule *networkingv1alpha1.IngressRule) {
@@ -0,0 +1,177 @@
+package resources
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	socommon "github.com/openshift-knative/serverless-operator/pkg/common"
+	ingressconfig "github.com/openshift-knative/serverless-operator/serving/ingress/pkg/reconciler/ingress/config"
+	routev1 "github.com/openshift/api/route/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"knative.dev/networking/pkg/apis/networking"
+	networkingv1alpha1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
+	"knative.dev/pkg/kmap"
+	"knative.dev/pkg/ptr"
+)
+
+// WildcardRouteLabelKey labels the wildcard Routes, which are shared by all the ingresses with
+// a host in their subdomain and thus aren't labeled with an owning ingress.
+const WildcardRouteLabelKey = socommon.ServingDownstreamDomain + "/wildcardRoute"
+
+// routerAnnotationPrefixes are the prefixes of the router annotations, which are specific to
+// the Routes of an ingress.
+var routerAnnotationPrefixes = []string{"haproxy.router.openshift.io/", "router.openshift.io/"}
+
+// routeSpecificAnnotations are the annotations customizing the Routes of an ingress.
+var routeSpecificAnnotations = []string{
+	DisableRouteAnnotation,
+	EnablePassthroughRouteAnnotation,
+	SetRouteTimeoutAnnotation,
+	TLSSecretAnnotation,
+	TLSTerminationAnnotation,
+	RouteLabelsAnnotation,
+	ExposeClusterLocalAnnotation,
+}
+
+// MakeWildcardRoutes creates the wildcard Routes exposing the hosts of a Knative Ingress, one per
+// subdomain, e.g. a Route for "*.ns.apps.example.com" exposes "foo.ns.apps.example.com". It returns
+// false if the ingress can't be exposed through wildcard Routes as it needs Routes of its own,
+// i.e. it customizes its Routes, redirects HTTP, brings its own certificate or has a host without
+// a subdomain.
+// The router shards must admit the wildcard Routes, which requires their subdomains to be strictly
+// below the domain of the shard, which the reconciler checks against the IngressControllers.
+func MakeWildcardRoutes(ctx context.Context, ci *networkingv1alpha1.Ingress) ([]*routev1.Route, bool, error) {
+	network := ingressconfig.FromContextOrDefaults(ctx).Network
+	if !network.WildcardRoutes || !wildcardEligible(ci, network) {
+		return nil, false, nil
+	}
+
+	subdomains := sets.New[string]()
+	for _, rule := range ci.Spec.Rules {
+		if rule.Visibility == networkingv1alpha1.IngressVisibilityClusterLocal {
+			continue
+		}
+		for _, host := range rule.Hosts {
+			parts := strings.Split(host, ".")
+			if len(parts) > 2 && parts[2] == "svc" {
+				continue
+			}
+			// Wildcards of top-level domains aren't admitted.
+			if len(parts) < 3 {
+				return nil, false, nil
+			}
+			subdomains.Insert(strings.Join(parts[1:], "."))
+		}
+	}
+	if subdomains.Len() == 0 {
+		return nil, false, nil
+	}
+
+	serviceName, namespace, err := publicLoadBalancer(ci)
+	if err != nil {
+		return nil, false, err
+	}
+	timeout, err := getHAProxyTimeout(nil, network)
+	if err != nil {
+		return nil, false, err
+	}
+
+	routes := make([]*routev1.Route, 0, subdomains.Len())
+	for _, subdomain := range sets.List(subdomains) {
+		routes = append(routes, makeWildcardRoute(subdomain, serviceName, namespace, timeout, ci, network))
+	}
+	return routes, true, nil
+}
+
+func makeWildcardRoute(subdomain, serviceName, namespace, timeout string, ci *networkingv1alpha1.Ingress, network *ingressconfig.Network) *routev1.Route {
+	route := &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      WildcardRouteName(serviceName, subdomain),
+			Namespace: namespace,
+			Labels: kmap.Union(network.RouteLabels, map[string]string{
+				WildcardRouteLabelKey: "true",
+			}),
+			Annotations: map[string]string{
+				TimeoutAnnotation:                    timeout,
+				networking.IngressClassAnnotationKey: ci.GetAnnotations()[networking.IngressClassAnnotationKey],
+			},
+		},
+		Spec: routev1.RouteSpec{
+			// The router exposes all the hosts of the subdomain of a Route with the Subdomain policy.
+			Host: "wildcard." + subdomain,
+			Port: &routev1.RoutePort{
+				TargetPort: intstr.FromString(HTTPPort),
+			},
+			To: routev1.RouteTargetReference{
+				Kind:   "Service",
+				Name:   serviceName,
+				Weight: ptr.Int32(100),
+			},
+			TLS: &routev1.TLSConfig{
+				Termination:                   routev1.TLSTerminationEdge,
+				InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyAllow,
+			},
+			WildcardPolicy: routev1.WildcardPolicySubdomain,
+		},
+	}
+
+	if network.InternalTLS {
+		route.Spec.Port.TargetPort = intstr.FromString(HTTPSPort)
+		if network.RouteTLSTermination == routev1.TLSTerminationReencrypt {
+			route.Spec.TLS.Termination = routev1.TLSTerminationReencrypt
+		} else {
+			route.Spec.TLS.Termination = routev1.TLSTerminationPassthrough
+			route.Spec.TLS.InsecureEdgeTerminationPolicy = routev1.InsecureEdgeTerminationPolicyRedirect
+		}
+	}
+	return route
+}
+
+// wildcardEligible returns whether the ingress can share the wildcard Routes, which have the
+// default options for all of their hosts.
+func wildcardEligible(ci *networkingv1alpha1.Ingress, network *ingressconfig.Network) bool {
+	if ci.Spec.HTTPOption == networkingv1alpha1.HTTPOptionRedirected ||
+		len(ci.GetIngressTLSForVisibility(networkingv1alpha1.IngressVisibilityExternalIP)) > 0 {
+		return false
+	}
+
+	annotations := ci.GetAnnotations()
+	for _, key := range routeSpecificAnnotations {
+		if _, ok := annotations[key]; ok {
+			return false
+		}
+	}
+	for key := range annotations {
+		if _, ok := routeOptions[key]; ok {
+			return false
+		}
+		for _, prefix := range routerAnnotationPrefixes {
+			if strings.HasPrefix(key, prefix) {
+				return false
+			}
+		}
+	}
+
+	if !network.InternalTLS {
+		for _, rule := range ci.Spec.Rules {
+			if isTLSDestination(rule) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// WildcardRouteName returns the name of the wildcard Route of the subdomain to the given service.
+func WildcardRouteName(serviceName, subdomain string) string {
+	return fmt.Sprintf("route-wildcard-%x", sha256.Sum256([]byte(serviceName+"/"+subdomain)))[0:31]
+}
+
+// WildcardSubdomain returns the subdomain exposed by the given wildcard Route.
+func WildcardSubdomain(route *routev1.Route) string {
+	return strings.TrimPrefix(route.Spec.Host, "wildcard.")
+}
@@ -0,0 +1,154 @@
+package resources
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	routev1 "github.com/openshift/api/route/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"knative.dev/networking/pkg/apis/networking"
+	networkingv1alpha1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
+	"knative.dev/pkg/ptr"
+
+	ingressconfig "github.com/openshift-knative/serverless-operator/serving/ingress/pkg/reconciler/ingress/config"
+)
+
+func TestMakeWildcardRoutes(t *testing.T) {
+	const ingressClass = "kourier.ingress.networking.knative.dev"
+	wildcards := &ingressconfig.Network{RouteTLSTermination: routev1.TLSTerminationPassthrough, WildcardRoutes: true}
+	withClass := func(ing *networkingv1alpha1.Ingress) {
+		ing.Annotations = map[string]string{networking.IngressClassAnnotationKey: ingressClass}
+	}
+	withAnnotation := func(key, value string) ingressOption {
+		return func(ing *networkingv1alpha1.Ingress) {
+			ing.Annotations[key] = value
+		}
+	}
+	wildcardRoute := func(subdomain string, termination routev1.TLSTerminationType, port string) *routev1.Route {
+		policy := routev1.InsecureEdgeTerminationPolicyAllow
+		if termination == routev1.TLSTerminationPassthrough {
+			policy = routev1.InsecureEdgeTerminationPolicyRedirect
+		}
+		return &routev1.Route{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      WildcardRouteName(lbService, subdomain),
+				Namespace: lbNamespace,
+				Labels:    map[string]string{WildcardRouteLabelKey: "true"},
+				Annotations: map[string]string{
+					TimeoutAnnotation:                    DefaultTimeout,
+					networking.IngressClassAnnotationKey: ingressClass,
+				},
+			},
+			Spec: routev1.RouteSpec{
+				Host: "wildcard." + subdomain,
+				Port: &routev1.RoutePort{TargetPort: intstr.FromString(port)},
+				To: routev1.RouteTargetReference{
+					Kind:   "Service",
+					Name:   lbService,
+					Weight: ptr.Int32(100),
+				},
+				TLS: &routev1.TLSConfig{
+					Termination:                   termination,
+					InsecureEdgeTerminationPolicy: policy,
+				},
+				WildcardPolicy: routev1.WildcardPolicySubdomain,
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		ingress *networkingv1alpha1.Ingress
+		network *ingressconfig.Network
+		want    []*routev1.Route
+		wantOK  bool
+	}{{
+		name:    "disabled",
+		ingress: ingress(withClass, withRules(rule(withHosts([]string{externalDomain})))),
+		network: &ingressconfig.Network{RouteTLSTermination: routev1.TLSTerminationPassthrough},
+	}, {
+		name: "a route per subdomain",
+		ingress: ingress(withClass, withRules(
+			rule(withHosts([]string{localDomain, externalDomain, "tag-" + externalDomain})),
+			rule(withHosts([]string{externalDomain2})),
+			rule(withHosts([]string{"private.default.domainName"}), withLocalVisibilityRule),
+		)),
+		network: wildcards,
+		want: []*routev1.Route{
+			wildcardRoute("default.domainName", routev1.TLSTerminationEdge, HTTPPort),
+			wildcardRoute("public.default.domainName", routev1.TLSTerminationEdge, HTTPPort),
+		},
+		wantOK: true,
+	}, {
+		name:    "system-internal-tls",
+		ingress: ingress(withClass, withRules(rule(withHosts([]string{externalDomain}), withHTTPSBackendService()))),
+		network: &ingressconfig.Network{
+			InternalTLS:         true,
+			RouteTLSTermination: routev1.TLSTerminationReencrypt,
+			WildcardRoutes:      true,
+		},
+		want:   []*routev1.Route{wildcardRoute("default.domainName", routev1.TLSTerminationReencrypt, HTTPSPort)},
+		wantOK: true,
+	}, {
+		name:    "only cluster-local hosts",
+		ingress: ingress(withClass, withRules(rule(withHosts([]string{localDomain})))),
+		network: wildcards,
+	}, {
+		name:    "host without subdomain",
+		ingress: ingress(withClass, withRules(rule(withHosts([]string{externalDomain, "example.com"})))),
+		network: wildcards,
+	}, {
+		name:    "redirected",
+		ingress: ingress(withClass, withRedirect(), withRules(rule(withHosts([]string{externalDomain})))),
+		network: wildcards,
+	}, {
+		name: "own certificate",
+		ingress: ingress(withClass, withTLS(networkingv1alpha1.IngressTLS{Hosts: []string{externalDomain}}),
+			withRules(rule(withHosts([]string{externalDomain})))),
+		network: wildcards,
+	}, {
+		name:    "https backend",
+		ingress: ingress(withClass, withRules(rule(withHosts([]string{externalDomain}), withHTTPSBackendService()))),
+		network: wildcards,
+	}, {
+		name:    "route timeout",
+		ingress: ingress(withClass, withAnnotation(SetRouteTimeoutAnnotation, "5"), withRules(rule(withHosts([]string{externalDomain})))),
+		network: wildcards,
+	}, {
+		name:    "typed HAProxy option",
+		ingress: ingress(withClass, withAnnotation(BalanceAnnotation, "source"), withRules(rule(withHosts([]string{externalDomain})))),
+		network: wildcards,
+	}, {
+		name:    "router annotation",
+		ingress: ingress(withClass, withAnnotation(TimeoutAnnotation, "5s"), withRules(rule(withHosts([]string{externalDomain})))),
+		network: wildcards,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := ingressconfig.ToContext(context.Background(), &ingressconfig.Config{Network: test.network})
+			got, ok, err := MakeWildcardRoutes(ctx, test.ingress)
+			if err != nil {
+				t.Fatalf("MakeWildcardRoutes() = %v", err)
+			}
+			if ok != test.wantOK {
+				t.Errorf("MakeWildcardRoutes() ok = %v, want %v", ok, test.wantOK)
+			}
+			if !cmp.Equal(got, test.want) {
+				t.Errorf("MakeWildcardRoutes() = %s", cmp.Diff(test.want, got))
+			}
+		})
+	}
+}
+
+func TestMakeWildcardRoutesNoLoadBalancer(t *testing.T) {
+	ctx := ingressconfig.ToContext(context.Background(), &ingressconfig.Config{
+		Network: &ingressconfig.Network{WildcardRoutes: true},
+	})
+	ing := ingress(withLBInternalDomain(""), withRules(rule(withHosts([]string{externalDomain}))))
+	if _, _, err := MakeWildcardRoutes(ctx, ing); err != ErrNoValidLoadbalancerDomain {
+		t.Errorf("MakeWildcardRoutes() = %v, want %v", err, ErrNoValidLoadbalancerDomain)
+	}
+}
@@ -0,0 +1,284 @@
+package ingress
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+	"knative.dev/pkg/controller"
+
+	ingresscontrollerinformer "github.com/openshift-knative/serverless-operator/serving/ingress/pkg/informers/ingresscontroller"
+	ingressconfig "github.com/openshift-knative/serverless-operator/serving/ingress/pkg/reconciler/ingress/config"
+	"github.com/openshift-knative/serverless-operator/serving/ingress/pkg/reconciler/ingress/resources"
+)
+
+const wildcardsAllowedPolicy = "WildcardsAllowed"
+
+// routerShard is the part of an IngressController selecting and exposing Routes.
+type routerShard struct {
+	name     string
+	selector labels.Selector
+	// namespaceSelector selects the namespaces of the Routes exposed by the shard.
+	namespaceSelector labels.Selector
+	domain            string
+	// wildcards is true if the shard admits wildcard Routes.
+	wildcards bool
+}
+
+// selects returns whether the shard selects the Route, given the labels of its namespace.
+func (s routerShard) selects(route *routev1.Route, namespaceLabels labels.Set) bool {
+	return s.selector.Matches(labels.Set(route.Labels)) && s.namespaceSelector.Matches(namespaceLabels)
+}
+
+// admits returns whether the shard exposes the host, i.e. the host is in the domain of the shard.
+func (s routerShard) admits(host string) bool {
+	return s.domain == "" || host == s.domain || strings.HasSuffix(host, "."+s.domain)
+}
+
+// admitsWildcard returns whether the shard exposes the wildcard Route of the subdomain. Only
+// subdomains strictly below the domain of the shard are admitted, as the wildcard Route of the
+// domain itself would claim all the hosts of the shard, e.g. with the default domain template.
+func (s routerShard) admitsWildcard(subdomain string) bool {
+	return s.wildcards && s.domain != "" && strings.HasSuffix(subdomain, "."+s.domain)
+}
+
+// validateShards warns when the Routes of an ingress with shard labels aren't selected by any
+// router shard, or only by shards whose domain doesn't include their host. The Routes are
+// reconciled anyway, as the IngressControllers might be changed later on. The warnings are only
+// recorded when they change, not on every reconciliation.
+func (r *Reconciler) validateShards(ctx context.Context, ing *v1alpha1.Ingress, routes []*routev1.Route) error {
+	if r.ingressControllerLister == nil {
+		return nil
+	}
+	warnings, err := r.shardWarnings(ctx, ing, routes)
+	if err != nil {
+		return err
+	}
+
+	key := types.NamespacedName{Namespace: ing.Namespace, Name: ing.Name}
+	if len(warnings) == 0 {
+		r.reportedShardWarnings.Delete(key)
+		return nil
+	}
+	if reported, ok := r.reportedShardWarnings.Load(key); ok && slices.Equal(reported.([]shardWarning), warnings) {
+		return nil
+	}
+	recorder := controller.GetEventRecorder(ctx)
+	for _, warning := range warnings {
+		recorder.Event(ing, corev1.EventTypeWarning, warning.reason, warning.message)
+	}
+	r.reportedShardWarnings.Store(key, warnings)
+	return nil
+}
+
+// shardWarning is a misconfiguration of the router shards exposing a Route.
+type shardWarning struct {
+	reason  string
+	message string
+}
+
+// shardWarnings returns the warnings about the router shards exposing the given Routes.
+func (r *Reconciler) shardWarnings(ctx context.Context, ing *v1alpha1.Ingress, routes []*routev1.Route) ([]shardWarning, error) {
+	if len(routes) == 0 {
+		return nil, nil
+	}
+	shardLabels, err := resources.ShardLabels(ctx, ing)
+	if err != nil {
+		return nil, err
+	}
+	localShardLabels := ingressconfig.FromContextOrDefaults(ctx).Network.ClusterLocalRouteLabels
+	if len(shardLabels) == 0 && len(localShardLabels) == 0 {
+		// The Routes are exposed through the default router.
+		return nil, nil
+	}
+
+	shards, err := r.routerShards()
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings []shardWarning
+	for _, route := range routes {
+		// The Routes of cluster-local rules are labeled for the internal router shard.
+		routeShardLabels := shardLabels
+		if len(localShardLabels) > 0 && labels.SelectorFromSet(localShardLabels).Matches(labels.Set(route.Labels)) {
+			routeShardLabels = localShardLabels
+		}
+		if len(routeShardLabels) == 0 {
+			continue
+		}
+		namespaceLabels, err := r.namespaceLabels(route.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		var selecting []string
+		admitted := false
+		for _, shard := range shards {
+			if shard.selects(route, namespaceLabels) {
+				selecting = append(selecting, shard.name)
+				if shard.admits(route.Spec.Host) {
+					admitted = true
+				}
+			}
+		}
+		switch {
+		case len(selecting) == 0:
+			warnings = append(warnings, shardWarning{reason: "NoRouterShard", message: fmt.Sprintf(
+				"No router shard selects the labels %v of the Route for host %q", routeShardLabels, route.Spec.Host)})
+		case !admitted:
+			warnings = append(warnings, shardWarning{reason: "RouterShardDomainMismatch", message: fmt.Sprintf(
+				"Host %q isn't in the domain of the router shards %v selecting the labels %v", route.Spec.Host, selecting, routeShardLabels)})
+		}
+	}
+	return warnings, nil
+}
+
+// validateClusterLocalShards returns an error if a router shard other than the internal one, i.e.
+// the shard of the cluster-local route domain, selects the Routes exposing cluster-local hosts.
+// All the IngressControllers are checked, as the default one selects all the Routes unless it's
+// given a route selector.
+func (r *Reconciler) validateClusterLocalShards(ctx context.Context, routes []*routev1.Route) error {
+	network := ingressconfig.FromContextOrDefaults(ctx).Network
+	if r.ingressControllerLister == nil || len(network.ClusterLocalRouteLabels) == 0 {
+		return nil
+	}
+	localSelector := labels.SelectorFromSet(network.ClusterLocalRouteLabels)
+
+	var shards []routerShard
+	exposing := sets.New[string]()
+	for _, route := range routes {
+		if !localSelector.Matches(labels.Set(route.Labels)) {
+			continue
+		}
+		if shards == nil {
+			var err error
+			if shards, err = r.routerShards(); err != nil {
+				return err
+			}
+		}
+		namespaceLabels, err := r.namespaceLabels(route.Namespace)
+		if err != nil {
+			return err
+		}
+		for _, shard := range shards {
+			if shard.domain != network.ClusterLocalRouteDomain && shard.selects(route, namespaceLabels) {
+				exposing.Insert(shard.name)
+			}
+		}
+	}
+	if exposing.Len() > 0 {
+		return fmt.Errorf("%w: router shards %v select the labels %v of %s, exclude them with a route selector",
+			resources.ErrClusterLocalRoutesExposed, sets.List(exposing), network.ClusterLocalRouteLabels, ingressconfig.ClusterLocalRouteLabelsKey)
+	}
+	return nil
+}
+
+// wildcardsAllowed returns whether each of the given wildcard Routes is selected by a router shard
+// which allows wildcard Routes and has their subdomain strictly below its domain.
+func (r *Reconciler) wildcardsAllowed(ctx context.Context, routes []*routev1.Route) (bool, error) {
+	if r.ingressControllerLister == nil {
+		return false, nil
+	}
+	shards, err := r.routerShards()
+	if err != nil {
+		return false, err
+	}
+
+	for _, route := range routes {
+		namespaceLabels, err := r.namespaceLabels(route.Namespace)
+		if err != nil {
+			return false, err
+		}
+		allowed := false
+		for _, shard := range shards {
+			if shard.selects(route, namespaceLabels) && shard.admitsWildcard(resources.WildcardSubdomain(route)) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// routerShards lists the router shards of the cluster from the IngressController informer.
+func (r *Reconciler) routerShards() ([]routerShard, error) {
+	objs, err := r.ingressControllerLister.ByNamespace(ingresscontrollerinformer.Namespace).List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list IngressControllers: %w", err)
+	}
+
+	shards := make([]routerShard, 0, len(objs))
+	for _, obj := range objs {
+		ic := obj.(*unstructured.Unstructured)
+		selector, err := labelSelector(ic, "routeSelector")
+		if err != nil {
+			return nil, err
+		}
+		namespaceSelector, err := labelSelector(ic, "namespaceSelector")
+		if err != nil {
+			return nil, err
+		}
+
+		// The status holds the domain of the default IngressController, which has none in its spec.
+		domain, _, _ := unstructured.NestedString(ic.Object, "status", "domain")
+		if domain == "" {
+			domain, _, _ = unstructured.NestedString(ic.Object, "spec", "domain")
+		}
+		wildcardPolicy, _, _ := unstructured.NestedString(ic.Object, "spec", "routeAdmission", "wildcardPolicy")
+		shards = append(shards, routerShard{
+			name:              ic.GetName(),
+			selector:          selector,
+			namespaceSelector: namespaceSelector,
+			domain:            domain,
+			wildcards:         wildcardPolicy == wildcardsAllowedPolicy,
+		})
+	}
+	return shards, nil
+}
+
+// labelSelector returns the given label selector of the spec of the IngressController, which
+// selects everything if it isn't set.
+func labelSelector(ic *unstructured.Unstructured, field string) (labels.Selector, error) {
+	raw, ok, _ := unstructured.NestedMap(ic.Object, "spec", field)
+	if !ok {
+		return labels.Everything(), nil
+	}
+	ls := &metav1.LabelSelector{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(raw, ls); err != nil {
+		return nil, fmt.Errorf("failed to parse the %s of IngressController %s: %w", field, ic.GetName(), err)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(ls)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s of IngressController %s: %w", field, ic.GetName(), err)
+	}
+	return selector, nil
+}
+
+// namespaceLabels returns the labels of the namespace of a Route, matched against the namespace
+// selectors of the router shards. A missing namespace has no labels.
+func (r *Reconciler) namespaceLabels(name string) (labels.Set, error) {
+	if r.namespaceLister == nil {
+		return nil, nil
+	}
+	ns, err := r.namespaceLister.Get(name)
+	if apierrs.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get namespace %s: %w", name, err)
+	}
+	return ns.Labels, nil
+}
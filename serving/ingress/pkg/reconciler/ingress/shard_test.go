@@ -0,0 +1,295 @@
+package ingress
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+	"knative.dev/pkg/controller"
+	logtesting "knative.dev/pkg/logging/testing"
+
+	ingresscontrollerinformer "github.com/openshift-knative/serverless-operator/serving/ingress/pkg/informers/ingresscontroller"
+	ingressconfig "github.com/openshift-knative/serverless-operator/serving/ingress/pkg/reconciler/ingress/config"
+	"github.com/openshift-knative/serverless-operator/serving/ingress/pkg/reconciler/ingress/resources"
+)
+
+func TestValidateShards(t *testing.T) {
+	defaultShard := ingressController("default", nil, "", "apps.example.com")
+	internalShard := ingressController("internal", map[string]interface{}{
+		"matchLabels": map[string]interface{}{"router": "internal"},
+	}, "internal.example.com", "internal.example.com")
+
+	tests := []struct {
+		name            string
+		routeLabels     string
+		namespaceLabels map[string]string
+		host            string
+		shards          []runtime.Object
+		want            []string
+	}{{
+		name:   "default router",
+		host:   "foo.apps.example.com",
+		shards: []runtime.Object{defaultShard, internalShard},
+	}, {
+		name:        "admitted by shard",
+		routeLabels: "router=internal",
+		host:        "foo.internal.example.com",
+		shards:      []runtime.Object{internalShard},
+	}, {
+		name:        "no shard selecting the labels",
+		routeLabels: "router=private",
+		host:        "foo.internal.example.com",
+		shards:      []runtime.Object{internalShard},
+		want:        []string{`Warning NoRouterShard No router shard selects the labels map[router:private] of the Route for host "foo.internal.example.com"`},
+	}, {
+		name:        "host outside of the shard domain",
+		routeLabels: "router=internal",
+		host:        "foo.apps.example.com",
+		shards:      []runtime.Object{internalShard},
+		want:        []string{`Warning RouterShardDomainMismatch Host "foo.apps.example.com" isn't in the domain of the router shards [internal] selecting the labels map[router:internal]`},
+	}, {
+		name:            "admitted by shard selecting the namespace",
+		routeLabels:     "router=internal",
+		namespaceLabels: map[string]string{"router": "internal"},
+		host:            "foo.internal.example.com",
+		shards:          []runtime.Object{withNamespaceSelector(internalShard, "router", "internal")},
+	}, {
+		name:        "shard not selecting the namespace",
+		routeLabels: "router=internal",
+		host:        "foo.internal.example.com",
+		shards:      []runtime.Object{withNamespaceSelector(internalShard, "router", "internal")},
+		want:        []string{`Warning NoRouterShard No router shard selects the labels map[router:internal] of the Route for host "foo.internal.example.com"`},
+	}, {
+		name:        "no IngressControllers",
+		routeLabels: "router=internal",
+		host:        "foo.internal.example.com",
+		want:        []string{`Warning NoRouterShard No router shard selects the labels map[router:internal] of the Route for host "foo.internal.example.com"`},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			ctx := controller.WithEventRecorder(logtesting.TestContextWithLogger(t), recorder)
+			ctx = ingressconfig.ToContext(ctx, &ingressconfig.Config{Network: &ingressconfig.Network{}})
+
+			ing := ing(ingNamespace, ingName)
+			if test.routeLabels != "" {
+				ing.Annotations[resources.RouteLabelsAnnotation] = test.routeLabels
+			}
+			// The warnings are only recorded once for unchanged Routes.
+			routes := shardRoutes(ctx, t, ing, test.host)
+			r := &Reconciler{
+				ingressControllerLister: ingressControllerLister(test.shards...),
+				namespaceLister:         namespaceLister(namespace(routes[0].Namespace, test.namespaceLabels)),
+			}
+			for range 2 {
+				if err := r.validateShards(ctx, ing, routes); err != nil {
+					t.Fatalf("validateShards() = %v", err)
+				}
+			}
+
+			close(recorder.Events)
+			var got []string
+			for event := range recorder.Events {
+				got = append(got, event)
+			}
+			if !cmp.Equal(got, test.want) {
+				t.Errorf("events = %s", cmp.Diff(test.want, got))
+			}
+		})
+	}
+}
+
+func TestValidateClusterLocalShards(t *testing.T) {
+	internalShard := ingressController("internal", map[string]interface{}{
+		"matchLabels": map[string]interface{}{"router": "internal"},
+	}, "internal.example.com", "internal.example.com")
+	publicShard := ingressController("default", map[string]interface{}{
+		"matchExpressions": []interface{}{map[string]interface{}{"key": "router", "operator": "DoesNotExist"}},
+	}, "", "apps.example.com")
+	localRoute := &routev1.Route{ObjectMeta: metav1.ObjectMeta{Namespace: "ingress", Labels: map[string]string{"router": "internal"}}}
+	publicRoute := &routev1.Route{ObjectMeta: metav1.ObjectMeta{Namespace: "ingress"}}
+
+	tests := []struct {
+		name    string
+		shards  []runtime.Object
+		routes  []*routev1.Route
+		wantErr bool
+	}{{
+		name:   "only the internal shard selects the labels",
+		shards: []runtime.Object{publicShard, internalShard},
+		routes: []*routev1.Route{publicRoute, localRoute},
+	}, {
+		name:    "default router selects all the Routes",
+		shards:  []runtime.Object{ingressController("default", nil, "", "apps.example.com"), internalShard},
+		routes:  []*routev1.Route{publicRoute, localRoute},
+		wantErr: true,
+	}, {
+		name: "another shard selects the labels",
+		shards: []runtime.Object{publicShard, internalShard, ingressController("private", map[string]interface{}{
+			"matchLabels": map[string]interface{}{"router": "internal"},
+		}, "private.example.com", "private.example.com")},
+		routes:  []*routev1.Route{localRoute},
+		wantErr: true,
+	}, {
+		name: "another shard selects the labels but not the namespace",
+		shards: []runtime.Object{publicShard, internalShard, withNamespaceSelector(ingressController("private", map[string]interface{}{
+			"matchLabels": map[string]interface{}{"router": "internal"},
+		}, "private.example.com", "private.example.com"), "router", "private")},
+		routes: []*routev1.Route{localRoute},
+	}, {
+		name:   "no cluster-local Routes",
+		shards: []runtime.Object{ingressController("default", nil, "", "apps.example.com"), internalShard},
+		routes: []*routev1.Route{publicRoute},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := ingressconfig.ToContext(context.Background(), &ingressconfig.Config{Network: &ingressconfig.Network{
+				ClusterLocalRouteLabels: map[string]string{"router": "internal"},
+				ClusterLocalRouteDomain: "internal.example.com",
+			}})
+			r := &Reconciler{
+				ingressControllerLister: ingressControllerLister(test.shards...),
+				namespaceLister:         namespaceLister(namespace("ingress", nil)),
+			}
+			err := r.validateClusterLocalShards(ctx, test.routes)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("validateClusterLocalShards() = %v, wantErr %v", err, test.wantErr)
+			}
+			if err != nil && !errors.Is(err, resources.ErrClusterLocalRoutesExposed) {
+				t.Errorf("validateClusterLocalShards() = %v, want %v", err, resources.ErrClusterLocalRoutesExposed)
+			}
+		})
+	}
+}
+
+func TestWildcardsAllowed(t *testing.T) {
+	withWildcards := func(ic *unstructured.Unstructured) *unstructured.Unstructured {
+		_ = unstructured.SetNestedField(ic.Object, wildcardsAllowedPolicy, "spec", "routeAdmission", "wildcardPolicy")
+		return ic
+	}
+	wildcardRoute := func(labels map[string]string) *routev1.Route {
+		return &routev1.Route{
+			ObjectMeta: metav1.ObjectMeta{Labels: labels},
+			Spec:       routev1.RouteSpec{Host: "wildcard.ns.apps.example.com"},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		shards []runtime.Object
+		routes []*routev1.Route
+		want   bool
+	}{{
+		name:   "default router allows wildcards",
+		shards: []runtime.Object{withWildcards(ingressController("default", nil, "", "apps.example.com"))},
+		routes: []*routev1.Route{wildcardRoute(nil)},
+		want:   true,
+	}, {
+		name:   "default router disallows wildcards",
+		shards: []runtime.Object{ingressController("default", nil, "", "apps.example.com")},
+		routes: []*routev1.Route{wildcardRoute(nil)},
+	}, {
+		name: "shard selecting the route disallows wildcards",
+		shards: []runtime.Object{
+			withWildcards(ingressController("default", map[string]interface{}{
+				"matchLabels": map[string]interface{}{"router": "default"},
+			}, "", "apps.example.com")),
+			ingressController("internal", map[string]interface{}{
+				"matchLabels": map[string]interface{}{"router": "internal"},
+			}, "", "apps.example.com"),
+		},
+		routes: []*routev1.Route{wildcardRoute(map[string]string{"router": "internal"})},
+	}, {
+		name:   "shard not selecting the namespace",
+		shards: []runtime.Object{withWildcards(withNamespaceSelector(ingressController("default", nil, "", "apps.example.com"), "router", "default"))},
+		routes: []*routev1.Route{wildcardRoute(nil)},
+	}, {
+		name:   "host outside of the shard domain",
+		shards: []runtime.Object{withWildcards(ingressController("default", nil, "", "internal.example.com"))},
+		routes: []*routev1.Route{wildcardRoute(nil)},
+	}, {
+		name:   "subdomain is the shard domain",
+		shards: []runtime.Object{withWildcards(ingressController("default", nil, "", "ns.apps.example.com"))},
+		routes: []*routev1.Route{wildcardRoute(nil)},
+	}, {
+		name:   "no IngressControllers",
+		routes: []*routev1.Route{wildcardRoute(nil)},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := &Reconciler{ingressControllerLister: ingressControllerLister(test.shards...)}
+			got, err := r.wildcardsAllowed(context.Background(), test.routes)
+			if err != nil {
+				t.Fatalf("wildcardsAllowed() = %v", err)
+			}
+			if got != test.want {
+				t.Errorf("wildcardsAllowed() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func shardRoutes(ctx context.Context, t *testing.T, ing *v1alpha1.Ingress, host string) []*routev1.Route {
+	t.Helper()
+	ing.Spec.Rules[0].Hosts = []string{host}
+	routes, err := resources.MakeRoutes(ctx, ing, nil)
+	if err != nil {
+		t.Fatalf("MakeRoutes() = %v", err)
+	}
+	return routes
+}
+
+func ingressController(name string, routeSelector map[string]interface{}, specDomain, statusDomain string) *unstructured.Unstructured {
+	ic := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	ic.SetGroupVersionKind(ingresscontrollerinformer.Resource.GroupVersion().WithKind("IngressController"))
+	ic.SetNamespace(ingresscontrollerinformer.Namespace)
+	ic.SetName(name)
+	if routeSelector != nil {
+		_ = unstructured.SetNestedMap(ic.Object, routeSelector, "spec", "routeSelector")
+	}
+	if specDomain != "" {
+		_ = unstructured.SetNestedField(ic.Object, specDomain, "spec", "domain")
+	}
+	_ = unstructured.SetNestedField(ic.Object, statusDomain, "status", "domain")
+	return ic
+}
+
+// withNamespaceSelector sets the namespace selector of the IngressController to the given label.
+func withNamespaceSelector(ic *unstructured.Unstructured, key, value string) *unstructured.Unstructured {
+	ic = ic.DeepCopy()
+	_ = unstructured.SetNestedStringMap(ic.Object, map[string]string{key: value}, "spec", "namespaceSelector", "matchLabels")
+	return ic
+}
+
+func namespace(name string, labels map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func namespaceLister(namespaces ...*corev1.Namespace) corev1listers.NamespaceLister {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, ns := range namespaces {
+		_ = indexer.Add(ns)
+	}
+	return corev1listers.NewNamespaceLister(indexer)
+}
+
+func ingressControllerLister(objs ...runtime.Object) cache.GenericLister {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, obj := range objs {
+		_ = indexer.Add(obj)
+	}
+	return cache.NewGenericLister(indexer, ingresscontrollerinformer.Resource.GroupResource())
+}
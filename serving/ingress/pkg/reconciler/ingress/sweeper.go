@@ -0,0 +1,228 @@
+package ingress
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	routev1 "github.com/openshift/api/route/v1"
+	routev1client "github.com/openshift/client-go/route/clientset/versioned/typed/route/v1"
+	routev1lister "github.com/openshift/client-go/route/listers/route/v1"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+	ingressinformer "knative.dev/networking/pkg/client/injection/informers/networking/v1alpha1/ingress"
+	networkinglisters "knative.dev/networking/pkg/client/listers/networking/v1alpha1"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/reconciler"
+
+	routeclient "github.com/openshift-knative/serverless-operator/pkg/client/route/injection/client"
+	routeinformer "github.com/openshift-knative/serverless-operator/pkg/client/route/injection/informers/route/v1/route"
+	ingressconfig "github.com/openshift-knative/serverless-operator/serving/ingress/pkg/reconciler/ingress/config"
+	"github.com/openshift-knative/serverless-operator/serving/ingress/pkg/reconciler/ingress/resources"
+)
+
+// sweepInterval is the interval between two sweeps of the orphaned Routes.
+const sweepInterval = 10 * time.Minute
+
+// sweepKey is the only key of the sweeper's work queue.
+var sweepKey = types.NamespacedName{Name: "orphaned-routes"}
+
+// Sweeper implements controller.Reconciler deleting the Routes whose Ingress doesn't exist anymore,
+// e.g. because it was force-deleted without its finalizer running, and the wildcard Routes which
+// aren't used anymore.
+type Sweeper struct {
+	reconciler.LeaderAwareFuncs
+
+	ingressLister networkinglisters.IngressLister
+	routeLister   routev1lister.RouteLister
+	routeClient   routev1client.RouteV1Interface
+	recorder      record.EventRecorder
+	metrics       *metrics
+	configStore   reconciler.ConfigStore
+
+	// enqueueAfter schedules the next sweep.
+	enqueueAfter func(types.NamespacedName, time.Duration)
+}
+
+// Reconcile sweeps the orphaned Routes and schedules the next sweep.
+func (s *Sweeper) Reconcile(ctx context.Context, _ string) error {
+	// Only the leader sweeps, it's enqueued again on promotion.
+	if !s.IsLeaderFor(sweepKey) {
+		return nil
+	}
+	if s.configStore != nil {
+		ctx = s.configStore.ToContext(ctx)
+	}
+	if err := s.sweep(ctx); err != nil {
+		return err
+	}
+	s.enqueueAfter(sweepKey, sweepInterval)
+	return nil
+}
+
+func (s *Sweeper) sweep(ctx context.Context) error {
+	routes, err := s.routeLister.List(ownedRoutesSelector())
+	if err != nil {
+		return fmt.Errorf("failed to list routes: %w", err)
+	}
+
+	for _, route := range routes {
+		namespace := route.Labels[resources.OpenShiftIngressNamespaceLabelKey]
+		name := route.Labels[resources.OpenShiftIngressLabelKey]
+		if _, err := s.ingressLister.Ingresses(namespace).Get(name); err == nil {
+			continue
+		} else if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get ingress %s/%s: %w", namespace, name, err)
+		}
+
+		if err := s.deleteRoute(ctx, route, fmt.Sprintf("Deleted route of the deleted ingress %s/%s", namespace, name)); err != nil {
+			return err
+		}
+	}
+	return s.sweepWildcards(ctx)
+}
+
+// sweepWildcards deletes the wildcard Routes whose subdomain isn't exposed anymore, or all of them
+// if the wildcard Routes are disabled.
+func (s *Sweeper) sweepWildcards(ctx context.Context) error {
+	wildcard, _ := labels.NewRequirement(resources.WildcardRouteLabelKey, selection.Exists, nil)
+	routes, err := s.routeLister.List(labels.NewSelector().Add(*wildcard))
+	if err != nil {
+		return fmt.Errorf("failed to list wildcard routes: %w", err)
+	}
+	if len(routes) == 0 {
+		return nil
+	}
+
+	subdomains := sets.New[string]()
+	if ingressconfig.FromContextOrDefaults(ctx).Network.WildcardRoutes {
+		ingresses, err := s.ingressLister.List(labels.Everything())
+		if err != nil {
+			return fmt.Errorf("failed to list ingresses: %w", err)
+		}
+		for _, ing := range ingresses {
+			for _, rule := range ing.Spec.Rules {
+				for _, host := range rule.Hosts {
+					if _, subdomain, ok := strings.Cut(host, "."); ok {
+						subdomains.Insert(subdomain)
+					}
+				}
+			}
+		}
+	}
+
+	for _, route := range routes {
+		if subdomain := resources.WildcardSubdomain(route); !subdomains.Has(subdomain) {
+			if err := s.deleteRoute(ctx, route, fmt.Sprintf("Deleted wildcard route of the unused subdomain %s", subdomain)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Sweeper) deleteRoute(ctx context.Context, route *routev1.Route, message string) error {
+	logging.FromContext(ctx).Infof("Deleting route %s/%s: %s", route.Namespace, route.Name, message)
+	if err := s.routeClient.Routes(route.Namespace).Delete(ctx, route.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete route: %w", err)
+	}
+	s.recorder.Event(&corev1.ObjectReference{
+		APIVersion: routev1.GroupVersion.String(),
+		Kind:       "Route",
+		Namespace:  route.Namespace,
+		Name:       route.Name,
+		UID:        route.UID,
+	}, corev1.EventTypeNormal, "OrphanedRouteDeleted", message)
+	s.metrics.orphanedRoutesDeleted.Add(ctx, 1)
+	return nil
+}
+
+// ownedRoutesSelector selects the Routes generated for an Ingress.
+func ownedRoutesSelector() labels.Selector {
+	ingressName, _ := labels.NewRequirement(resources.OpenShiftIngressLabelKey, selection.Exists, nil)
+	ingressNamespace, _ := labels.NewRequirement(resources.OpenShiftIngressNamespaceLabelKey, selection.Exists, nil)
+	return labels.NewSelector().Add(*ingressName, *ingressNamespace)
+}
+
+// NewOrphanedRouteSweeper returns a controller periodically deleting the Routes whose Ingress
+// doesn't exist anymore.
+func NewOrphanedRouteSweeper(
+	ctx context.Context,
+	_ configmap.Watcher,
+) *controller.Impl {
+	logger := logging.FromContext(ctx)
+
+	m, err := newMetrics(nil)
+	if err != nil {
+		logger.Fatalw("Failed to register the ingress metrics", zap.Error(err))
+	}
+
+	s := &Sweeper{
+		LeaderAwareFuncs: reconciler.LeaderAwareFuncs{
+			PromoteFunc: func(bkt reconciler.Bucket, enq func(reconciler.Bucket, types.NamespacedName)) error {
+				enq(bkt, sweepKey)
+				return nil
+			},
+		},
+		ingressLister: ingressinformer.Get(ctx).Lister(),
+		routeLister:   routeinformer.Get(ctx).Lister(),
+		routeClient:   routeclient.Get(ctx).RouteV1(),
+		recorder:      newEventRecorder(ctx, "orphaned-route-sweeper"),
+		metrics:       m,
+	}
+
+	// The sweeper is the only controller looking at all the Routes, it reports their number.
+	if err := observeRoutes(nil, s.routeLister); err != nil {
+		logger.Fatalw("Failed to register the Routes metric", zap.Error(err))
+	}
+
+	configStore := ingressconfig.NewStore(logger.Named("config-store"))
+	if err := configStore.WatchServingConfigs(ctx); err != nil {
+		logger.Fatalw("Failed to watch the Knative Serving configuration", zap.Error(err))
+	}
+	s.configStore = configStore
+
+	impl := controller.NewContext(ctx, s, controller.ControllerOptions{
+		WorkQueueName: "OrphanedRoutes",
+		Logger:        logger.Named("orphaned-route-sweeper"),
+	})
+	s.enqueueAfter = impl.EnqueueKeyAfter
+
+	return impl
+}
+
+// newEventRecorder returns the event recorder of the context or, as the generated reconcilers do,
+// a new one recording to the API server.
+func newEventRecorder(ctx context.Context, component string) record.EventRecorder {
+	if recorder := controller.GetEventRecorder(ctx); recorder != nil {
+		return recorder
+	}
+
+	logger := logging.FromContext(ctx)
+	broadcaster := record.NewBroadcaster()
+	watches := []watch.Interface{
+		broadcaster.StartLogging(logger.Named("event-broadcaster").Infof),
+		broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeclient.Get(ctx).CoreV1().Events("")}),
+	}
+	go func() {
+		<-ctx.Done()
+		for _, w := range watches {
+			w.Stop()
+		}
+	}()
+	return broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: component})
+}
@@ -0,0 +1,179 @@
+package ingress
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	routev1 "github.com/openshift/api/route/v1"
+	routefake "github.com/openshift/client-go/route/clientset/versioned/fake"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	logtesting "knative.dev/pkg/logging/testing"
+	"knative.dev/pkg/reconciler"
+
+	ingressconfig "github.com/openshift-knative/serverless-operator/serving/ingress/pkg/reconciler/ingress/config"
+	"github.com/openshift-knative/serverless-operator/serving/ingress/pkg/reconciler/ingress/resources"
+	rtesting "github.com/openshift-knative/serverless-operator/serving/ingress/pkg/reconciler/testing"
+)
+
+func TestSweeper(t *testing.T) {
+	unlabeled := route(ingNamespace, "unlabeled")
+	delete(unlabeled.Labels, resources.OpenShiftIngressLabelKey)
+	wildcard := func(subdomain string) *routev1.Route {
+		return &routev1.Route{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: ingNamespace,
+				Name:      resources.WildcardRouteName(svcName, subdomain),
+				Labels:    map[string]string{resources.WildcardRouteLabelKey: "true"},
+			},
+			Spec: routev1.RouteSpec{Host: "wildcard." + subdomain},
+		}
+	}
+	usedWildcard := wildcard(ingNamespace + ".default.domainName")
+	unusedWildcard := wildcard("other.default.domainName")
+	wildcards := &ingressconfig.Network{WildcardRoutes: true}
+
+	tests := []struct {
+		name        string
+		objects     []runtime.Object
+		network     *ingressconfig.Network
+		wantRoutes  []string
+		wantEvents  []string
+		wantDeleted int64
+	}{{
+		name:       "ingress exists",
+		objects:    []runtime.Object{ing(ingNamespace, ingName), route(ingNamespace, "route")},
+		wantRoutes: []string{"route"},
+	}, {
+		name:        "orphaned route",
+		objects:     []runtime.Object{route(ingNamespace, "route")},
+		wantEvents:  []string{"Normal OrphanedRouteDeleted Deleted route of the deleted ingress testNs/test"},
+		wantDeleted: 1,
+	}, {
+		name:       "route not owned by an ingress",
+		objects:    []runtime.Object{unlabeled},
+		wantRoutes: []string{"unlabeled"},
+	}, {
+		name:       "wildcard routes",
+		objects:    []runtime.Object{ing(ingNamespace, ingName), usedWildcard, unusedWildcard},
+		network:    wildcards,
+		wantRoutes: []string{usedWildcard.Name},
+		wantEvents: []string{
+			"Normal OrphanedRouteDeleted Deleted wildcard route of the unused subdomain other.default.domainName",
+		},
+		wantDeleted: 1,
+	}, {
+		name:    "wildcard routes disabled",
+		objects: []runtime.Object{ing(ingNamespace, ingName), usedWildcard},
+		wantEvents: []string{
+			"Normal OrphanedRouteDeleted Deleted wildcard route of the unused subdomain testNs.default.domainName",
+		},
+		wantDeleted: 1,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := logtesting.TestContextWithLogger(t)
+			if test.network != nil {
+				ctx = ingressconfig.ToContext(ctx, &ingressconfig.Config{Network: test.network})
+			}
+			listers := rtesting.NewListers(test.objects)
+			routeClient := routefake.NewSimpleClientset(listers.GetRouteObjects()...)
+			recorder := record.NewFakeRecorder(10)
+			reader := sdkmetric.NewManualReader()
+
+			var requeued time.Duration
+			s := &Sweeper{
+				ingressLister: listers.GetIngressLister(),
+				routeLister:   listers.GetRouteLister(),
+				routeClient:   routeClient.RouteV1(),
+				recorder:      recorder,
+				metrics:       testMetrics(t, sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+				enqueueAfter: func(_ types.NamespacedName, delay time.Duration) {
+					requeued = delay
+				},
+			}
+			if err := s.Promote(reconciler.UniversalBucket(), func(reconciler.Bucket, types.NamespacedName) {}); err != nil {
+				t.Fatalf("Promote() = %v", err)
+			}
+			if err := s.Reconcile(ctx, sweepKey.String()); err != nil {
+				t.Fatalf("Reconcile() = %v", err)
+			}
+			if requeued != sweepInterval {
+				t.Errorf("requeued after %v, want %v", requeued, sweepInterval)
+			}
+
+			routes, err := routeClient.RouteV1().Routes(ingNamespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				t.Fatalf("List() = %v", err)
+			}
+			var gotRoutes []string
+			for _, r := range routes.Items {
+				gotRoutes = append(gotRoutes, r.Name)
+			}
+			if !cmp.Equal(gotRoutes, test.wantRoutes) {
+				t.Errorf("routes = %s", cmp.Diff(test.wantRoutes, gotRoutes))
+			}
+
+			close(recorder.Events)
+			var gotEvents []string
+			for event := range recorder.Events {
+				gotEvents = append(gotEvents, event)
+			}
+			if !cmp.Equal(gotEvents, test.wantEvents) {
+				t.Errorf("events = %s", cmp.Diff(test.wantEvents, gotEvents))
+			}
+
+			if got := orphanedRoutesDeleted(ctx, t, reader); got != test.wantDeleted {
+				t.Errorf("deleted routes metric = %d, want %d", got, test.wantDeleted)
+			}
+		})
+	}
+}
+
+func TestSweeperNotLeader(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	listers := rtesting.NewListers([]runtime.Object{route(ingNamespace, "route")})
+	routeClient := routefake.NewSimpleClientset(listers.GetRouteObjects()...)
+
+	s := &Sweeper{
+		ingressLister: listers.GetIngressLister(),
+		routeLister:   listers.GetRouteLister(),
+		routeClient:   routeClient.RouteV1(),
+		enqueueAfter: func(types.NamespacedName, time.Duration) {
+			t.Error("A sweep was scheduled by a non-leader")
+		},
+	}
+	if err := s.Reconcile(ctx, sweepKey.String()); err != nil {
+		t.Fatalf("Reconcile() = %v", err)
+	}
+	if _, err := routeClient.RouteV1().Routes(ingNamespace).Get(ctx, "route", metav1.GetOptions{}); err != nil {
+		t.Errorf("Get() = %v, want the route to be kept", err)
+	}
+}
+
+func orphanedRoutesDeleted(ctx context.Context, t *testing.T, reader sdkmetric.Reader) int64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("Collect() = %v", err)
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if sum, ok := m.Data.(metricdata.Sum[int64]); ok && m.Name == "ocp.ingress.route.orphaned.deleted" {
+				var total int64
+				for _, dp := range sum.DataPoints {
+					total += dp.Value
+				}
+				return total
+			}
+		}
+	}
+	return 0
+}
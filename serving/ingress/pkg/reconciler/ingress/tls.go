@@ -0,0 +1,45 @@
+package ingress
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/reconciler"
+	"knative.dev/pkg/tracker"
+
+	"github.com/openshift-knative/serverless-operator/serving/ingress/pkg/reconciler/ingress/resources"
+)
+
+// tlsSecret returns the Secret holding the certificate the Routes of the ingress terminate TLS
+// with, nil if the router's default certificate is used. The Secret is tracked, so the Routes
+// are updated when it's rotated.
+func (r *Reconciler) tlsSecret(ing *v1alpha1.Ingress) (*corev1.Secret, error) {
+	name := ing.GetAnnotations()[resources.TLSSecretAnnotation]
+	if name == "" {
+		return nil, nil
+	}
+
+	ref := tracker.Reference{
+		APIVersion: "v1",
+		Kind:       "Secret",
+		Namespace:  ing.Namespace,
+		Name:       name,
+	}
+	if err := r.tracker.TrackReference(ref, ing); err != nil {
+		return nil, fmt.Errorf("failed to track secret %s/%s: %w", ing.Namespace, name, err)
+	}
+
+	secret, err := r.secretLister.Secrets(ing.Namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		// The ingress is reconciled again once the Secret shows up.
+		return nil, controller.NewPermanentError(reconciler.NewEvent(corev1.EventTypeWarning, "TLSSecretNotFound",
+			"Secret %s/%s referenced by the %s annotation doesn't exist or isn't labeled with %s",
+			ing.Namespace, name, resources.TLSSecretAnnotation, resources.TLSSecretLabelKey))
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get secret %s/%s: %w", ing.Namespace, name, err)
+	}
+	return secret, nil
+}
@@ -4,17 +4,24 @@ import (
 	routev1 "github.com/openshift/api/route/v1"
 	fakerouteclientset "github.com/openshift/client-go/route/clientset/versioned/fake"
 	routev1listers "github.com/openshift/client-go/route/listers/route/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	fakekubeclientset "k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	networking "knative.dev/networking/pkg/apis/networking/v1alpha1"
 	fakenetworkingclientset "knative.dev/networking/pkg/client/clientset/versioned/fake"
 	networkinglisters "knative.dev/networking/pkg/client/listers/networking/v1alpha1"
+	operatorv1beta1 "knative.dev/operator/pkg/apis/operator/v1beta1"
+	operatorv1beta1listers "knative.dev/operator/pkg/client/listers/operator/v1beta1"
 	"knative.dev/pkg/reconciler/testing"
 )
 
 var clientSetSchemes = []func(*runtime.Scheme) error{
 	fakenetworkingclientset.AddToScheme,
 	fakerouteclientset.AddToScheme,
+	operatorv1beta1.AddToScheme,
+	fakekubeclientset.AddToScheme,
 }
 
 type Listers struct {
@@ -68,3 +75,13 @@ func (l *Listers) GetIngressLister() networkinglisters.IngressLister {
 func (l *Listers) GetRouteLister() routev1listers.RouteLister {
 	return routev1listers.NewRouteLister(l.IndexerFor(&routev1.Route{}))
 }
+
+// GetKnativeServingLister get lister for KnativeServing resource.
+func (l *Listers) GetKnativeServingLister() operatorv1beta1listers.KnativeServingLister {
+	return operatorv1beta1listers.NewKnativeServingLister(l.IndexerFor(&operatorv1beta1.KnativeServing{}))
+}
+
+// GetSecretLister get lister for Secret resource.
+func (l *Listers) GetSecretLister() corev1listers.SecretLister {
+	return corev1listers.NewSecretLister(l.IndexerFor(&corev1.Secret{}))
+}
@@ -68,7 +68,7 @@ var (
 		// Checks if knative-openshift metrics are served
 		"controller_runtime_active_workers{controller=\"knativeserving-controller\"}",
 		// Checks if knative-openshift-ingress metrics are served
-		"go_memory_allocations_total{job=\"knative-openshift-ingress-metrics\"}",
+		"go_memory_allocations_total{job=\"knative-openshift-ingress-sm-service\"}",
 	}
 )
 